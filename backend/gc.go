@@ -0,0 +1,110 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/signal"
+	"time"
+
+	imagegc "github.com/Luke256/ducks/service/image_gc"
+	"github.com/Luke256/ducks/utils"
+)
+
+// defaultImageGCGracePeriod アップロード中の画像を削除しないよう、保存されてからこの期間は削除しない
+const defaultImageGCGracePeriod = 24 * time.Hour
+
+// runImageGC gcサブコマンドとして、参照されていない画像を削除します
+func runImageGC(args []string) {
+	fs := flag.NewFlagSet("gc", flag.ExitOnError)
+	dryRun := fs.Bool("dry-run", false, "report orphaned images without deleting them")
+	grace := fs.Duration("grace", imageGCGracePeriod(), "keep images saved within this period even if unreferenced")
+	fs.Parse(args)
+
+	storage, err := newStorage("")
+	if err != nil {
+		slog.Error("failed to create storage:", slog.String("error", err.Error()))
+		os.Exit(1)
+	}
+	manager := imagegc.NewManagerImpl(newRepository(), storage)
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	report, err := manager.Collect(ctx, imagegc.Options{GracePeriod: *grace, DryRun: *dryRun})
+	printImageGCReport(report)
+	if err != nil {
+		slog.Error("failed to collect orphaned images:", slog.String("error", err.Error()))
+		os.Exit(1)
+	}
+	if report.Failed > 0 {
+		os.Exit(1)
+	}
+}
+
+func printImageGCReport(report imagegc.Report) {
+	for _, image := range report.Orphaned {
+		status := "deleted"
+		switch {
+		case report.DryRun:
+			status = "orphaned"
+		case image.Error != "":
+			status = "failed: " + image.Error
+		}
+		fmt.Printf("%s\t%d bytes\t%s\t%s\n", image.FileName, image.Size, image.ModTime.Format(time.RFC3339), status)
+	}
+
+	fmt.Printf("scanned: %d, referenced: %d, recent: %d, ignored: %d, orphaned: %d\n",
+		report.Scanned, report.Referenced, report.Recent, report.Ignored, len(report.Orphaned))
+	if report.DryRun {
+		fmt.Println("dry run: no images were deleted")
+		return
+	}
+	fmt.Printf("deleted: %d (%d bytes), failed: %d\n", report.Deleted, report.FreedBytes, report.Failed)
+}
+
+// startImageGC IMAGE_GC_INTERVALが設定されている場合、その間隔で参照されていない画像を削除します
+func startImageGC(manager imagegc.Manager) {
+	value := os.Getenv("IMAGE_GC_INTERVAL")
+	if value == "" {
+		return
+	}
+	interval, err := time.ParseDuration(value)
+	if err != nil || interval <= 0 {
+		slog.Error("invalid IMAGE_GC_INTERVAL", "value", value)
+		panic("invalid IMAGE_GC_INTERVAL")
+	}
+	grace := imageGCGracePeriod()
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for range ticker.C {
+			report, err := manager.Collect(context.Background(), imagegc.Options{GracePeriod: grace})
+			if err != nil {
+				slog.Error("failed to collect orphaned images:", slog.String("error", err.Error()))
+				continue
+			}
+			slog.Info("Collected orphaned images",
+				"scanned", report.Scanned,
+				"deleted", report.Deleted,
+				"failed", report.Failed,
+				"freed_bytes", report.FreedBytes,
+			)
+		}
+	}()
+}
+
+// imageGCGracePeriod IMAGE_GC_GRACE_PERIODで指定された猶予期間を返します
+func imageGCGracePeriod() time.Duration {
+	value := utils.GetEnvOrDefault("IMAGE_GC_GRACE_PERIOD", defaultImageGCGracePeriod.String())
+	grace, err := time.ParseDuration(value)
+	if err != nil || grace < 0 {
+		slog.Error("invalid IMAGE_GC_GRACE_PERIOD", "value", value)
+		panic("invalid IMAGE_GC_GRACE_PERIOD")
+	}
+	return grace
+}
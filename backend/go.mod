@@ -12,12 +12,15 @@ require (
 	github.com/gen2brain/webp v0.5.5
 	github.com/go-gormigrate/gormigrate/v2 v2.1.5
 	github.com/go-ozzo/ozzo-validation/v4 v4.3.0
+	github.com/go-pdf/fpdf v0.9.0
 	github.com/go-sql-driver/mysql v1.9.3
 	github.com/google/uuid v1.6.0
 	github.com/hashicorp/go-multierror v1.1.1
 	github.com/joho/godotenv v1.5.1
 	github.com/labstack/echo/v4 v4.13.4
+	github.com/skip2/go-qrcode v0.0.0-20200617195104-da1b6568686e
 	github.com/stretchr/testify v1.10.0
+	golang.org/x/image v0.27.0
 	gorm.io/driver/mysql v1.6.0
 	gorm.io/gorm v1.31.1
 )
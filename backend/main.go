@@ -1,18 +1,34 @@
 package main
 
 import (
+	"context"
+	"crypto/rand"
+	"fmt"
 	"log/slog"
 	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
 
 	repository "github.com/Luke256/ducks/repository/gorm"
 	"github.com/Luke256/ducks/router"
 	v1 "github.com/Luke256/ducks/router/v1"
 	"github.com/Luke256/ducks/service/festival"
 	festivalstock "github.com/Luke256/ducks/service/festival_stock"
+	imagegc "github.com/Luke256/ducks/service/image_gc"
+	"github.com/Luke256/ducks/service/location"
 	"github.com/Luke256/ducks/service/poster"
 	"github.com/Luke256/ducks/service/sale"
 	stockitem "github.com/Luke256/ducks/service/stock_item"
+	"github.com/Luke256/ducks/service/upload"
+	"github.com/Luke256/ducks/utils"
+	"github.com/Luke256/ducks/utils/actiontoken"
+	"github.com/Luke256/ducks/utils/compressor"
+	"github.com/Luke256/ducks/utils/diskcache"
+	"github.com/Luke256/ducks/utils/storage"
+	"github.com/Luke256/ducks/utils/storage/local"
 	"github.com/Luke256/ducks/utils/storage/s3"
+	"github.com/Luke256/ducks/utils/transcode"
 
 	dsnConfig "github.com/go-sql-driver/mysql"
 	"github.com/joho/godotenv"
@@ -25,6 +41,15 @@ import (
 func main() {
 	godotenv.Load(".env")
 
+	if len(os.Args) > 1 && os.Args[1] == "gc" {
+		runImageGC(os.Args[2:])
+		return
+	}
+	if len(os.Args) > 1 && os.Args[1] == "migrate-storage" {
+		runStorageMigration(os.Args[2:])
+		return
+	}
+
 	router := setup()
 	router.Setup()
 
@@ -34,27 +59,79 @@ func main() {
 }
 
 func setup() *router.Router {
+	e := echo.New()
+
+	// address CORS
+	e.Use(middleware.CORSWithConfig(middleware.CORSConfig{
+		AllowOrigins: []string{"*"},
+		AllowHeaders: []string{echo.HeaderOrigin, echo.HeaderContentType, echo.HeaderAccept, echo.HeaderAuthorization, echo.HeaderCacheControl},
+		AllowMethods: []string{echo.GET, echo.HEAD, echo.PUT, echo.PATCH, echo.POST, echo.DELETE},
+	}))
+
+	repo := newRepository()
+
+	storage, err := newStorage("")
+	if err != nil {
+		slog.Error("failed to create storage:", slog.String("error", err.Error()))
+		panic(err)
+	}
+
+	actionTokenSecret := []byte(os.Getenv("ACTION_TOKEN_SECRET"))
+	if len(actionTokenSecret) == 0 {
+		// 再起動すると発行済みのトークンは使えなくなる
+		slog.Warn("ACTION_TOKEN_SECRET is not set, using a random secret")
+		actionTokenSecret = []byte(rand.Text())
+	}
+
+	festivalManager := festival.NewManagerImpl(repo)
+	posterManager := poster.NewManagerImpl(repo, storage, actiontoken.NewSigner(actionTokenSecret), imageProfile("POSTER", compressor.PosterProfile))
+	stockItemManager := stockitem.NewManagerImpl(repo, storage, imageProfile("ITEM", compressor.ItemProfile))
+	festivalStockManager := festivalstock.NewManagerImpl(repo, storage)
+	saleManager := sale.NewManagerImpl(repo)
+	locationManager := location.NewManagerImpl(repo, storage)
+
+	uploadManager := upload.NewManagerImpl(repo, storage, posterManager, stockItemManager)
+	if err := uploadManager.Resume(context.Background()); err != nil {
+		slog.Error("failed to resume image uploads:", slog.String("error", err.Error()))
+	}
+
+	startImageGC(imagegc.NewManagerImpl(repo, storage))
+
+	transcodeCacheSize, err := strconv.ParseInt(utils.GetEnvOrDefault("TRANSCODE_CACHE_MAX_BYTES", "268435456"), 10, 64)
+	if err != nil {
+		slog.Error("invalid TRANSCODE_CACHE_MAX_BYTES:", slog.String("error", err.Error()))
+		panic(err)
+	}
+	transcoder, err := transcode.NewCache(utils.GetEnvOrDefault("TRANSCODE_CACHE_DIR", filepath.Join(os.TempDir(), "ducks-transcode")), transcodeCacheSize)
+	if err != nil {
+		slog.Error("failed to create transcode cache:", slog.String("error", err.Error()))
+		panic(err)
+	}
+
+	v1Handler := v1.NewHandler(repo, festivalManager, posterManager, stockItemManager, festivalStockManager, saleManager, locationManager, uploadManager, storage, transcoder, v1.Config{
+		AdminToken:    os.Getenv("ADMIN_TOKEN"),
+		APIEndpoint:   os.Getenv("API_ENDPOINT"),
+		LabelFontPath: os.Getenv("LABEL_FONT_PATH"),
+	})
+
+	router := router.NewRouter(e, v1Handler, repo)
+
+	return router
+}
+
+// newRepository 環境変数で指定されたデータベースに接続します
+func newRepository() *repository.GormRepository {
 	dbUser := os.Getenv("NS_MARIADB_USER")
 	dbPassword := os.Getenv("NS_MARIADB_PASSWORD")
 	dbHost := os.Getenv("NS_MARIADB_HOSTNAME")
 	dbPort := os.Getenv("NS_MARIADB_PORT")
 	dbName := os.Getenv("NS_MARIADB_DATABASE")
-	bucketName := os.Getenv("S3_BUCKET_NAME")
 
-	if dbUser == "" || dbPassword == "" || dbHost == "" || dbPort == "" || dbName == "" || bucketName == "" {
+	if dbUser == "" || dbPassword == "" || dbHost == "" || dbPort == "" || dbName == "" {
 		slog.Error("environment variables are not set properly")
 		panic("environment variables are not set properly")
 	}
 
-	e := echo.New()
-
-	// address CORS
-	e.Use(middleware.CORSWithConfig(middleware.CORSConfig{
-		AllowOrigins: []string{"*"},
-		AllowHeaders: []string{echo.HeaderOrigin, echo.HeaderContentType, echo.HeaderAccept, echo.HeaderAuthorization, echo.HeaderCacheControl},
-		AllowMethods: []string{echo.GET, echo.HEAD, echo.PUT, echo.PATCH, echo.POST, echo.DELETE},
-	}))
-
 	DSNConfig := dsnConfig.Config{
 		User:                 dbUser,
 		Passwd:               dbPassword,
@@ -81,21 +158,73 @@ func setup() *router.Router {
 		panic(err)
 	}
 
-	storage, err := s3.NewS3Storage(bucketName)
-	if err != nil {
-		slog.Error("failed to create s3 storage:", slog.String("error", err.Error()))
-		panic(err)
-	}
+	return repo
+}
 
-	festivalManager := festival.NewManagerImpl(repo)
-	posterManager := poster.NewManagerImpl(repo, storage)
-	stockItemManager := stockitem.NewManagerImpl(repo, storage)
-	festivalStockManager := festivalstock.NewManagerImpl(repo, storage)
-	saleManager := sale.NewManagerImpl(repo)
+// newStorage STORAGE_BACKENDで指定されたストレージを作成します
+// prefixを指定すると、<prefix>STORAGE_BACKENDのように先頭にprefixを付けた環境変数を読み込みます
+func newStorage(prefix string) (storage.Storage, error) {
+	switch backend := utils.GetEnvOrDefault(prefix+"STORAGE_BACKEND", "s3"); backend {
+	case "s3":
+		bucketName := os.Getenv(prefix + "S3_BUCKET_NAME")
+		if bucketName == "" {
+			return nil, fmt.Errorf("%sS3_BUCKET_NAME is not set", prefix)
+		}
+		cacheSize, err := strconv.ParseInt(utils.GetEnvOrDefault(prefix+"S3_CACHE_MAX_BYTES", "1073741824"), 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %sS3_CACHE_MAX_BYTES: %w", prefix, err)
+		}
+		// 別のバケットのファイルと混ざらないよう、キャッシュの場所もprefixごとに分ける
+		cacheDir := filepath.Join(os.TempDir(), strings.ToLower(prefix)+"ducks-s3-cache")
+		cache, err := diskcache.New(utils.GetEnvOrDefault(prefix+"S3_CACHE_DIR", cacheDir), cacheSize)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create s3 cache: %w", err)
+		}
+		return s3.NewS3Storage(s3.Config{
+			Endpoint:   os.Getenv(prefix + "STORAGE_ENDPOINT"),
+			AccessKey:  os.Getenv(prefix + "STORAGE_ACCESS_KEY"),
+			SecretKey:  os.Getenv(prefix + "STORAGE_SECRET_KEY"),
+			BucketName: bucketName,
+		}, cache)
+	case "local":
+		return local.NewLocalStorage(utils.GetEnvOrDefault(prefix+"LOCAL_STORAGE_ROOT", "./data/images"))
+	default:
+		return nil, fmt.Errorf("unknown storage backend: %s", backend)
+	}
+}
 
-	v1Handler := v1.NewHandler(repo, festivalManager, posterManager, stockItemManager, festivalStockManager, saleManager, storage)
+// imageProfile 環境変数で画像の上限と圧縮の設定を上書きします
+// 上限はすべての用途で共通のIMAGE_MAX_*で、圧縮の設定は用途ごとの<prefix>_IMAGE_*で指定します
+func imageProfile(prefix string, profile compressor.Profile) compressor.Profile {
+	profile.MaxBytes = int64(envInt("IMAGE_MAX_BYTES", int(profile.MaxBytes)))
+	profile.MaxWidth = envInt("IMAGE_MAX_WIDTH", profile.MaxWidth)
+	profile.MaxHeight = envInt("IMAGE_MAX_HEIGHT", profile.MaxHeight)
+	profile.MaxEdge = envInt(prefix+"_IMAGE_MAX_EDGE", profile.MaxEdge)
+	profile.Quality = envInt(prefix+"_IMAGE_QUALITY", profile.Quality)
+
+	if value := os.Getenv(prefix + "_IMAGE_LOSSLESS"); value != "" {
+		lossless, err := strconv.ParseBool(value)
+		if err != nil {
+			slog.Error("invalid environment variable", "key", prefix+"_IMAGE_LOSSLESS", "value", value)
+			panic("invalid " + prefix + "_IMAGE_LOSSLESS")
+		}
+		profile.Lossless = lossless
+	}
 
-	router := router.NewRouter(e, v1Handler, repo)
+	return profile
+}
 
-	return router
+// envInt 環境変数を整数として読み込みます
+// 設定されていない場合はdefaultValueを返します
+func envInt(key string, defaultValue int) int {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		slog.Error("invalid environment variable", "key", key, "value", value)
+		panic("invalid " + key)
+	}
+	return n
 }
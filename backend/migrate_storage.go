@@ -0,0 +1,84 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/signal"
+
+	storagemigration "github.com/Luke256/ducks/service/storage_migration"
+)
+
+// runStorageMigration migrate-storageサブコマンドとして、参照されている画像を別のストレージにコピーします
+// 移行元は通常と同じ環境変数で、移行先は先頭に-to-prefixを付けた環境変数で指定します
+func runStorageMigration(args []string) {
+	fs := flag.NewFlagSet("migrate-storage", flag.ExitOnError)
+	dryRun := fs.Bool("dry-run", false, "report files to copy without copying them")
+	verifyExisting := fs.Bool("verify-existing", false, "compare the content of files already in the destination instead of their size")
+	prefix := fs.String("to-prefix", "MIGRATE_TO_", "prefix of the environment variables for the destination storage")
+	fs.Parse(args)
+
+	// 移行元と同じストレージを移行先にしてしまわないよう、既定値には頼らない
+	if os.Getenv(*prefix+"STORAGE_BACKEND") == "" {
+		slog.Error(*prefix + "STORAGE_BACKEND is not set")
+		os.Exit(1)
+	}
+
+	src, err := newStorage("")
+	if err != nil {
+		slog.Error("failed to create source storage:", slog.String("error", err.Error()))
+		os.Exit(1)
+	}
+	dst, err := newStorage(*prefix)
+	if err != nil {
+		slog.Error("failed to create destination storage:", slog.String("error", err.Error()))
+		os.Exit(1)
+	}
+	manager := storagemigration.NewManagerImpl(newRepository(), src, dst)
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	report, err := manager.Migrate(ctx, storagemigration.Options{
+		DryRun:         *dryRun,
+		VerifyExisting: *verifyExisting,
+		Progress:       printStorageMigrationProgress,
+	})
+	printStorageMigrationReport(report)
+	if err != nil {
+		slog.Error("failed to migrate storage:", slog.String("error", err.Error()))
+		os.Exit(1)
+	}
+	if report.Missing > 0 || report.Failed > 0 {
+		os.Exit(1)
+	}
+}
+
+func printStorageMigrationProgress(p storagemigration.Progress) {
+	status := p.File.Status
+	if p.File.Error != "" {
+		status += ": " + p.File.Error
+	}
+	fmt.Printf("[%d/%d]\t%s\t%d bytes\t%s\n", p.Done, p.Total, p.File.FileName, p.File.Size, status)
+}
+
+func printStorageMigrationReport(report storagemigration.Report) {
+	fmt.Printf("images: %d, files: %d, copied: %d (%d bytes), skipped: %d, missing: %d, failed: %d\n",
+		report.Images, report.Files, report.Copied, report.CopiedBytes, report.Skipped, report.Missing, report.Failed)
+	for _, file := range report.Problems {
+		status := file.Status
+		if file.Error != "" {
+			status += ": " + file.Error
+		}
+		fmt.Printf("%s\t%s\n", file.FileName, status)
+	}
+	if report.DryRun {
+		fmt.Printf("dry run: %d files would be copied\n", report.Pending)
+		return
+	}
+	if report.Missing == 0 && report.Failed == 0 && report.Copied+report.Skipped == report.Files {
+		fmt.Println("all referenced images are in the destination storage")
+	}
+}
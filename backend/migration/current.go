@@ -11,7 +11,16 @@ import (
 // 新たなマイグレーションを行う場合は、この配列の末尾に必ず追加すること
 func Migrations() []*gormigrate.Migration {
 	return []*gormigrate.Migration{
-		v1(), // v1 販売管理システムの追加
+		v1(),  // v1 販売管理システムの追加
+		v2(),  // v2 レジ画面の表示順・表示状態・ボタン色の追加
+		v3(),  // v3 イベントの開催期間・ステータスの追加
+		v4(),  // v4 論理削除の追加
+		v5(),  // v5 ポスターのステータス変更履歴の追加
+		v6(),  // v6 ポスターの掲示場所の追加
+		v7(),  // v7 掲示場所の巡回順の追加
+		v8(),  // v8 ポスターの掲示期間の追加
+		v9(),  // v9 ポスターの承認フローの追加
+		v10(), // v10 画像の直接アップロードの追加
 	}
 }
 
@@ -25,5 +34,9 @@ func AllTables() []any {
 		&model.StockItem{},
 		&model.FestivalStock{},
 		&model.SaleRecord{},
+		&model.PosterStatusHistory{},
+		&model.Location{},
+		&model.PosterPlacement{},
+		&model.ImageUpload{},
 	}
 }
@@ -0,0 +1,20 @@
+package migration
+
+import (
+	"github.com/Luke256/ducks/model"
+
+	"github.com/go-gormigrate/gormigrate/v2"
+	"gorm.io/gorm"
+)
+
+// v10 画像の直接アップロードの追加
+func v10() *gormigrate.Migration {
+	return &gormigrate.Migration{
+		ID: "10",
+		Migrate: func(db *gorm.DB) error {
+			return db.AutoMigrate(
+				&model.ImageUpload{},
+			)
+		},
+	}
+}
@@ -0,0 +1,20 @@
+package migration
+
+import (
+	"github.com/Luke256/ducks/model"
+
+	"github.com/go-gormigrate/gormigrate/v2"
+	"gorm.io/gorm"
+)
+
+// v2 レジ画面の表示順・表示状態・ボタン色の追加
+func v2() *gormigrate.Migration {
+	return &gormigrate.Migration{
+		ID: "2",
+		Migrate: func(db *gorm.DB) error {
+			return db.AutoMigrate(
+				&model.FestivalStock{},
+			)
+		},
+	}
+}
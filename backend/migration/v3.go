@@ -0,0 +1,20 @@
+package migration
+
+import (
+	"github.com/Luke256/ducks/model"
+
+	"github.com/go-gormigrate/gormigrate/v2"
+	"gorm.io/gorm"
+)
+
+// v3 イベントの開催期間・ステータスの追加
+func v3() *gormigrate.Migration {
+	return &gormigrate.Migration{
+		ID: "3",
+		Migrate: func(db *gorm.DB) error {
+			return db.AutoMigrate(
+				&model.Festival{},
+			)
+		},
+	}
+}
@@ -0,0 +1,23 @@
+package migration
+
+import (
+	"github.com/Luke256/ducks/model"
+
+	"github.com/go-gormigrate/gormigrate/v2"
+	"gorm.io/gorm"
+)
+
+// v4 論理削除の追加
+func v4() *gormigrate.Migration {
+	return &gormigrate.Migration{
+		ID: "4",
+		Migrate: func(db *gorm.DB) error {
+			return db.AutoMigrate(
+				&model.Festival{},
+				&model.Poster{},
+				&model.StockItem{},
+				&model.FestivalStock{},
+			)
+		},
+	}
+}
@@ -0,0 +1,21 @@
+package migration
+
+import (
+	"github.com/Luke256/ducks/model"
+
+	"github.com/go-gormigrate/gormigrate/v2"
+	"gorm.io/gorm"
+)
+
+// v6 ポスターの掲示場所の追加
+func v6() *gormigrate.Migration {
+	return &gormigrate.Migration{
+		ID: "6",
+		Migrate: func(db *gorm.DB) error {
+			return db.AutoMigrate(
+				&model.Location{},
+				&model.PosterPlacement{},
+			)
+		},
+	}
+}
@@ -0,0 +1,20 @@
+package migration
+
+import (
+	"github.com/Luke256/ducks/model"
+
+	"github.com/go-gormigrate/gormigrate/v2"
+	"gorm.io/gorm"
+)
+
+// v7 掲示場所の巡回順の追加
+func v7() *gormigrate.Migration {
+	return &gormigrate.Migration{
+		ID: "7",
+		Migrate: func(db *gorm.DB) error {
+			return db.AutoMigrate(
+				&model.Location{},
+			)
+		},
+	}
+}
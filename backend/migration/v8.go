@@ -0,0 +1,20 @@
+package migration
+
+import (
+	"github.com/Luke256/ducks/model"
+
+	"github.com/go-gormigrate/gormigrate/v2"
+	"gorm.io/gorm"
+)
+
+// v8 ポスターの掲示期間の追加
+func v8() *gormigrate.Migration {
+	return &gormigrate.Migration{
+		ID: "8",
+		Migrate: func(db *gorm.DB) error {
+			return db.AutoMigrate(
+				&model.Poster{},
+			)
+		},
+	}
+}
@@ -0,0 +1,28 @@
+package migration
+
+import (
+	"github.com/Luke256/ducks/model"
+
+	"github.com/go-gormigrate/gormigrate/v2"
+	"gorm.io/gorm"
+)
+
+// v9 ポスターの承認フローの追加
+func v9() *gormigrate.Migration {
+	return &gormigrate.Migration{
+		ID: "9",
+		Migrate: func(db *gorm.DB) error {
+			if err := db.AutoMigrate(
+				&model.Poster{},
+			); err != nil {
+				return err
+			}
+
+			// 既存の未回収のポスターは掲示済みとして扱う
+			return db.Unscoped().
+				Model(&model.Poster{}).
+				Where("status = ?", "uncollected").
+				Update("status", "posted").Error
+		},
+	}
+}
@@ -1,11 +1,18 @@
 package model
 
 import (
+	"time"
+
 	"github.com/google/uuid"
+	"gorm.io/gorm"
 )
 
 type Festival struct {
-	ID          uuid.UUID `gorm:"type:char(36);primaryKey" json:"id"`
-	Name        string    `json:"name"`
-	Description string    `json:"description"`
+	ID          uuid.UUID      `gorm:"type:char(36);primaryKey" json:"id"`
+	Name        string         `json:"name"`
+	Description string         `json:"description"`
+	StartDate   *time.Time     `json:"start_date"`
+	EndDate     *time.Time     `json:"end_date"`
+	Status      string         `gorm:"type:varchar(16);not null;default:'planning'" json:"status"`
+	DeletedAt   gorm.DeletedAt `gorm:"index" json:"deleted_at"`
 }
@@ -2,14 +2,19 @@ package model
 
 import (
 	"github.com/google/uuid"
+	"gorm.io/gorm"
 )
 
 type FestivalStock struct {
-	ID          uuid.UUID `gorm:"type:char(36);primary_key"`
-	FestivalID  uuid.UUID `gorm:"type:char(36);not null;index"`
-	StockItemID uuid.UUID `gorm:"type:char(36);not null;index"`
-	Price       int       `gorm:"not null"`
-	Description string    `gorm:"type:text"`
+	ID          uuid.UUID      `gorm:"type:char(36);primary_key"`
+	FestivalID  uuid.UUID      `gorm:"type:char(36);not null;index"`
+	StockItemID uuid.UUID      `gorm:"type:char(36);not null;index"`
+	Price       int            `gorm:"not null"`
+	Description string         `gorm:"type:text"`
+	Position    int            `gorm:"not null;default:0"`
+	Hidden      bool           `gorm:"not null;default:false"`
+	Color       string         `gorm:"type:varchar(16);not null;default:''"`
+	DeletedAt   gorm.DeletedAt `gorm:"index"`
 
 	Festival  Festival  `gorm:"constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
 	StockItem StockItem `gorm:"constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
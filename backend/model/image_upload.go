@@ -0,0 +1,22 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ImageUpload 署名付きURLでストレージに直接アップロードされる画像
+// アップロードの完了が通知された後、非同期に圧縮してポスターやアイテムに設定します
+type ImageUpload struct {
+	ID          uuid.UUID `gorm:"type:char(36);primary_key;" json:"id"`
+	TargetType  string    `gorm:"type:varchar(32);not null;" json:"target_type"`
+	TargetID    uuid.UUID `gorm:"type:char(36);not null;index;" json:"target_id"`
+	ObjectKey   string    `gorm:"type:varchar(255);not null;" json:"object_key"`
+	ContentType string    `gorm:"type:varchar(255);not null;" json:"content_type"`
+	Status      string    `gorm:"type:varchar(32);not null;index;" json:"status"`
+	Error       string    `gorm:"type:text;not null;" json:"error"`
+	ExpiresAt   time.Time `gorm:"not null;" json:"expires_at"`
+	CreatedAt   time.Time `gorm:"not null;" json:"created_at"`
+	UpdatedAt   time.Time `gorm:"not null;" json:"updated_at"`
+}
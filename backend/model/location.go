@@ -0,0 +1,15 @@
+package model
+
+import (
+	"github.com/google/uuid"
+)
+
+type Location struct {
+	ID         uuid.UUID `gorm:"type:char(36);primary_key;" json:"id"`
+	Building   string    `gorm:"type:varchar(100);not null;index:idx_location,priority:1;" json:"building"`
+	Floor      string    `gorm:"type:varchar(32);not null;index:idx_location,priority:2;" json:"floor"`
+	BoardName  string    `gorm:"type:varchar(100);not null;" json:"board_name"`
+	Latitude   *float64  `json:"latitude"`
+	Longitude  *float64  `json:"longitude"`
+	RouteOrder int       `gorm:"not null;default:0;index;" json:"route_order"`
+}
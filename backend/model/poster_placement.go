@@ -0,0 +1,19 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type PosterPlacement struct {
+	ID         uuid.UUID `gorm:"type:char(36);primary_key;" json:"id"`
+	PosterID   uuid.UUID `gorm:"type:char(36);not null;uniqueIndex:idx_poster_placement,priority:1;" json:"poster_id"`
+	LocationID uuid.UUID `gorm:"type:char(36);not null;uniqueIndex:idx_poster_placement,priority:2;index;" json:"location_id"`
+	Status     string    `gorm:"type:varchar(32);not null;" json:"status"`
+	CreatedAt  time.Time `gorm:"not null;" json:"created_at"`
+	UpdatedAt  time.Time `gorm:"not null;" json:"updated_at"`
+
+	Poster   Poster   `gorm:"foreignKey:PosterID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"-"`
+	Location Location `gorm:"foreignKey:LocationID;constraint:OnUpdate:CASCADE,OnDelete:RESTRICT;" json:"location"`
+}
@@ -0,0 +1,18 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type PosterStatusHistory struct {
+	ID             uuid.UUID `gorm:"type:char(36);primary_key;" json:"id"`
+	PosterID       uuid.UUID `gorm:"type:char(36);not null;index;" json:"poster_id"`
+	PreviousStatus string    `gorm:"type:varchar(32);not null;" json:"previous_status"`
+	NewStatus      string    `gorm:"type:varchar(32);not null;" json:"new_status"`
+	Note           string    `gorm:"type:text;not null;" json:"note"`
+	CreatedAt      time.Time `gorm:"not null;index;" json:"created_at"`
+
+	Poster Poster `gorm:"foreignKey:PosterID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"-"`
+}
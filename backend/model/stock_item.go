@@ -2,12 +2,14 @@ package model
 
 import (
 	"github.com/google/uuid"
+	"gorm.io/gorm"
 )
 
 type StockItem struct {
-	ID          uuid.UUID `gorm:"type:char(36);primary_key"`
-	Name        string    `gorm:"type:varchar(100);not null"`
-	Category    string    `gorm:"type:varchar(100);not null;index"`
-	Description string    `gorm:"type:text;"`
-	ImageID     string    `gorm:"type:text;not null"`
+	ID          uuid.UUID      `gorm:"type:char(36);primary_key"`
+	Name        string         `gorm:"type:varchar(100);not null"`
+	Category    string         `gorm:"type:varchar(100);not null;index"`
+	Description string         `gorm:"type:text;"`
+	ImageID     string         `gorm:"type:text;not null"`
+	DeletedAt   gorm.DeletedAt `gorm:"index"`
 }
@@ -1,13 +1,16 @@
 package repository
 
 import (
+	"time"
+
 	"github.com/Luke256/ducks/model"
 	"github.com/google/uuid"
 )
 
 type FestivalRepository interface {
 	// RegisterFestival イベントを登録します
-	RegisterFestival(name string, description string) (model.Festival, error)
+	// 登録直後のステータスは準備中(planning)になります
+	RegisterFestival(name string, description string, startDate, endDate *time.Time) (model.Festival, error)
 
 	// GetFestivalByID イベントIDからイベントを取得します
 	GetFestivalByID(festivalID uuid.UUID) (model.Festival, error)
@@ -16,8 +19,24 @@ type FestivalRepository interface {
 	GetAllFestivals() ([]model.Festival, error)
 
 	// UpdateFestival イベント情報を更新します
-	UpdateFestival(festivalID uuid.UUID, name string, description string) error
+	UpdateFestival(festivalID uuid.UUID, name string, description string, startDate, endDate *time.Time) error
+
+	// UpdateFestivalStatus イベントのステータスを更新します
+	UpdateFestivalStatus(festivalID uuid.UUID, status string) error
+
+	// CloneFestival 既存のイベントをもとに新しいイベントを作成します
+	// 販売するアイテムは価格にpriceDeltaを加えて複製されます(0未満にはなりません)
+	// withPostersがtrueの場合、ポスター情報も未回収の状態で複製されます
+	CloneFestival(sourceFestivalID uuid.UUID, name string, description string, priceDelta int, withPosters bool) (model.Festival, error)
 
-	// DeleteFestival イベントを削除します
+	// DeleteFestival イベントを、そのポスターと販売するアイテムとともに削除します
+	// 削除は論理削除であり、RestoreFestivalで復元できます
 	DeleteFestival(festivalID uuid.UUID) error
+
+	// GetDeletedFestivals 削除済みのイベントを取得します
+	GetDeletedFestivals() ([]model.Festival, error)
+
+	// RestoreFestival 削除済みのイベントを、イベントとともに削除されたポスターと販売するアイテムとあわせて復元します
+	// イベントより前に個別に削除されていたものは削除されたままにします
+	RestoreFestival(festivalID uuid.UUID) error
 }
\ No newline at end of file
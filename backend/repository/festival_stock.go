@@ -7,17 +7,34 @@ import (
 
 type FestivalStockRepository interface {
 	// RegisterFestivalStock イベントで販売するアイテムを登録します
+	// 表示順はイベント内の末尾になります
 	RegisterFestivalStock(festivalID, itemID uuid.UUID, price int, description string) (model.FestivalStock, error)
 
 	// GetFestivalStockByID イベントで販売するアイテムをIDで取得します
 	GetFestivalStockByID(festivalStockID uuid.UUID) (model.FestivalStock, error)
 
 	// QueryFestivalStocks イベントIDやカテゴリで販売するアイテムを検索します
-	QueryFestivalStocks(festivalID uuid.UUID, category string) ([]model.FestivalStock, error)
+	// 結果は表示順に並びます。visibleOnlyがtrueの場合、非表示のアイテムを除外します
+	QueryFestivalStocks(festivalID uuid.UUID, category string, visibleOnly bool) ([]model.FestivalStock, error)
 
 	// UpdateFestivalStock イベントで販売するアイテムを更新します
 	UpdateFestivalStock(festivalStockID uuid.UUID, description string) error
 
+	// UpdateFestivalStockDisplay イベントで販売するアイテムの表示状態とボタン色を更新します
+	UpdateFestivalStockDisplay(festivalStockID uuid.UUID, hidden bool, color string) error
+
+	// ReorderFestivalStocks イベントで販売するアイテムの表示順を並び替えます
+	// 指定されなかったアイテムは、現在の順序を保ったまま末尾に並びます
+	ReorderFestivalStocks(festivalID uuid.UUID, festivalStockIDs []uuid.UUID) error
+
 	// DeleteFestivalStock イベントで販売するアイテムを削除します
+	// 削除は論理削除であり、販売記録は残ります。RestoreFestivalStockで復元できます
 	DeleteFestivalStock(festivalStockID uuid.UUID) error
-}
\ No newline at end of file
+
+	// GetDeletedFestivalStocks 削除済みのイベントで販売するアイテムを取得します
+	GetDeletedFestivalStocks() ([]model.FestivalStock, error)
+
+	// RestoreFestivalStock 削除済みのイベントで販売するアイテムを復元します
+	// イベントかアイテムが削除されている場合はErrNotFoundを返します
+	RestoreFestivalStock(festivalStockID uuid.UUID) error
+}
@@ -11,6 +11,8 @@ func wrapGormError(err error) error {
 		return repository.ErrNotFound
 	case gorm.ErrForeignKeyViolated:
 		return repository.ErrForeignKey
+	case gorm.ErrDuplicatedKey:
+		return repository.ErrAlreadyExists
 	default:
 		return err
 	}
@@ -2,6 +2,7 @@ package gorm
 
 import (
 	"context"
+	"time"
 
 	"github.com/Luke256/ducks/model"
 	"github.com/Luke256/ducks/repository"
@@ -9,7 +10,7 @@ import (
 	"gorm.io/gorm"
 )
 
-func (r *GormRepository) RegisterFestival(name string, description string) (model.Festival, error) {
+func (r *GormRepository) RegisterFestival(name string, description string, startDate, endDate *time.Time) (model.Festival, error) {
 	festivalID, err := uuid.NewV7()
 	if err != nil {
 		return model.Festival{}, wrapGormError(err)
@@ -19,6 +20,9 @@ func (r *GormRepository) RegisterFestival(name string, description string) (mode
 		ID:          festivalID,
 		Name:        name,
 		Description: description,
+		StartDate:   startDate,
+		EndDate:     endDate,
+		Status:      "planning",
 	}
 
 	ctx := context.Background()
@@ -52,16 +56,18 @@ func (r *GormRepository) GetAllFestivals() ([]model.Festival, error) {
 	return festivals, nil
 }
 
-func (r *GormRepository) UpdateFestival(festivalID uuid.UUID, name string, description string) error {
+func (r *GormRepository) UpdateFestival(festivalID uuid.UUID, name string, description string, startDate, endDate *time.Time) error {
 	ctx := context.Background()
 
 	rows, err := gorm.G[model.Festival](r.db).
 		Where(&model.Festival{ID: festivalID}, "ID").
-		Select("Name", "Description").
+		Select("Name", "Description", "StartDate", "EndDate").
 		Updates(ctx, model.Festival{
 			ID:          festivalID,
 			Name:        name,
 			Description: description,
+			StartDate:   startDate,
+			EndDate:     endDate,
 		})
 	if err != nil {
 		return wrapGormError(err)
@@ -74,19 +80,193 @@ func (r *GormRepository) UpdateFestival(festivalID uuid.UUID, name string, descr
 	return nil
 }
 
-func (r *GormRepository) DeleteFestival(festivalID uuid.UUID) error {
+func (r *GormRepository) UpdateFestivalStatus(festivalID uuid.UUID, status string) error {
 	ctx := context.Background()
 
 	rows, err := gorm.G[model.Festival](r.db).
 		Where(&model.Festival{ID: festivalID}, "ID").
-		Delete(ctx)
+		Select("Status").
+		Updates(ctx, model.Festival{Status: status})
 	if err != nil {
 		return wrapGormError(err)
 	}
-
 	if rows == 0 {
-		return repository.ErrNotFound
+		// 値が変わらない場合も影響行数は0になるため、存在を確認する
+		if _, err := r.GetFestivalByID(festivalID); err != nil {
+			return err
+		}
 	}
 
 	return nil
-}
\ No newline at end of file
+}
+
+func (r *GormRepository) CloneFestival(sourceFestivalID uuid.UUID, name string, description string, priceDelta int, withPosters bool) (model.Festival, error) {
+	festivalID, err := uuid.NewV7()
+	if err != nil {
+		return model.Festival{}, err
+	}
+
+	festival := model.Festival{
+		ID:          festivalID,
+		Name:        name,
+		Description: description,
+		Status:      "planning",
+	}
+
+	ctx := context.Background()
+
+	err = r.db.Transaction(func(tx *gorm.DB) error {
+		if _, err := gorm.G[model.Festival](tx).
+			Where(&model.Festival{ID: sourceFestivalID}, "ID").
+			First(ctx); err != nil {
+			return err
+		}
+
+		if err := gorm.G[model.Festival](tx).Create(ctx, &festival); err != nil {
+			return err
+		}
+
+		stocks, err := gorm.G[model.FestivalStock](tx).
+			Where(model.FestivalStock{FestivalID: sourceFestivalID}, "FestivalID").
+			Find(ctx)
+		if err != nil {
+			return err
+		}
+		for _, stock := range stocks {
+			id, err := uuid.NewV7()
+			if err != nil {
+				return err
+			}
+
+			clone := model.FestivalStock{
+				ID:          id,
+				FestivalID:  festivalID,
+				StockItemID: stock.StockItemID,
+				Price:       max(stock.Price+priceDelta, 0),
+				Description: stock.Description,
+				Position:    stock.Position,
+				Hidden:      stock.Hidden,
+				Color:       stock.Color,
+			}
+			if err := gorm.G[model.FestivalStock](tx).Create(ctx, &clone); err != nil {
+				return err
+			}
+		}
+
+		if !withPosters {
+			return nil
+		}
+
+		posters, err := gorm.G[model.Poster](tx).
+			Where(&model.Poster{FestivalID: sourceFestivalID}, "FestivalID").
+			Find(ctx)
+		if err != nil {
+			return err
+		}
+		for _, poster := range posters {
+			id, err := uuid.NewV7()
+			if err != nil {
+				return err
+			}
+
+			clone := model.Poster{
+				ID:          id,
+				FestivalID:  festivalID,
+				PosterName:  poster.PosterName,
+				Description: poster.Description,
+				ImageID:     poster.ImageID,
+				Status:      "submitted",
+			}
+			if err := gorm.G[model.Poster](tx).Create(ctx, &clone); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return model.Festival{}, wrapGormError(err)
+	}
+
+	return festival, nil
+}
+
+func (r *GormRepository) DeleteFestival(festivalID uuid.UUID) error {
+	ctx := context.Background()
+
+	// 復元時に一緒に削除したものを見分けられるよう、同じ日時で削除する
+	deletedAt := time.Now()
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		rows, err := gorm.G[model.Festival](tx).
+			Where(&model.Festival{ID: festivalID}, "ID").
+			Update(ctx, "deleted_at", deletedAt)
+		if err != nil {
+			return err
+		}
+		if rows == 0 {
+			return repository.ErrNotFound
+		}
+
+		if _, err := gorm.G[model.Poster](tx).
+			Where(&model.Poster{FestivalID: festivalID}, "FestivalID").
+			Update(ctx, "deleted_at", deletedAt); err != nil {
+			return err
+		}
+		_, err = gorm.G[model.FestivalStock](tx).
+			Where(&model.FestivalStock{FestivalID: festivalID}, "FestivalID").
+			Update(ctx, "deleted_at", deletedAt)
+		return err
+	})
+
+	return wrapGormError(err)
+}
+
+func (r *GormRepository) GetDeletedFestivals() ([]model.Festival, error) {
+	ctx := context.Background()
+	festivals, err := gorm.G[model.Festival](r.db.Unscoped()).
+		Where("deleted_at IS NOT NULL").
+		Order("deleted_at DESC").
+		Find(ctx)
+	if err != nil {
+		return nil, wrapGormError(err)
+	}
+	return festivals, nil
+}
+
+func (r *GormRepository) RestoreFestival(festivalID uuid.UUID) error {
+	ctx := context.Background()
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		festival, err := gorm.G[model.Festival](tx.Unscoped()).
+			Where(&model.Festival{ID: festivalID}, "ID").
+			Where("deleted_at IS NOT NULL").
+			First(ctx)
+		if err != nil {
+			return err
+		}
+
+		if _, err := gorm.G[model.Festival](tx.Unscoped()).
+			Where(&model.Festival{ID: festivalID}, "ID").
+			Update(ctx, "deleted_at", nil); err != nil {
+			return err
+		}
+
+		// イベントとともに削除されたものだけを復元する
+		if _, err := gorm.G[model.Poster](tx.Unscoped()).
+			Where(&model.Poster{FestivalID: festivalID}, "FestivalID").
+			Where("deleted_at = ?", festival.DeletedAt.Time).
+			Update(ctx, "deleted_at", nil); err != nil {
+			return err
+		}
+		// 削除されたアイテムの在庫は、アイテムとあわせて復元する
+		_, err = gorm.G[model.FestivalStock](tx.Unscoped()).
+			Where(&model.FestivalStock{FestivalID: festivalID}, "FestivalID").
+			Where("deleted_at = ?", festival.DeletedAt.Time).
+			Where("stock_item_id IN (?)", tx.Model(&model.StockItem{}).Select("id")).
+			Update(ctx, "deleted_at", nil)
+		return err
+	})
+
+	return wrapGormError(err)
+}
@@ -2,6 +2,7 @@ package gorm
 
 import (
 	"context"
+	"database/sql"
 
 	"github.com/Luke256/ducks/model"
 	"github.com/Luke256/ducks/repository"
@@ -27,7 +28,22 @@ func (r *GormRepository) RegisterFestivalStock(festivalID, itemID uuid.UUID, pri
 
 	ctx := context.Background()
 
-	if err := gorm.G[model.FestivalStock](r.db).Create(ctx, &stock); err != nil {
+	err = r.db.Transaction(func(tx *gorm.DB) error {
+		// 表示順はイベント内の末尾にする
+		var maxPosition sql.NullInt64
+		if err := gorm.G[model.FestivalStock](tx).
+			Where(model.FestivalStock{FestivalID: festivalID}, "FestivalID").
+			Select("MAX(position)").
+			Scan(ctx, &maxPosition); err != nil {
+			return err
+		}
+		if maxPosition.Valid {
+			stock.Position = int(maxPosition.Int64) + 1
+		}
+
+		return gorm.G[model.FestivalStock](tx).Create(ctx, &stock)
+	})
+	if err != nil {
 		return model.FestivalStock{}, wrapGormError(err)
 	}
 
@@ -50,10 +66,10 @@ func (r *GormRepository) GetFestivalStockByID(festivalStockID uuid.UUID) (model.
 	return stock, nil
 }
 
-func (r *GormRepository) QueryFestivalStocks(festivalID uuid.UUID, category string) ([]model.FestivalStock, error) {
+func (r *GormRepository) QueryFestivalStocks(festivalID uuid.UUID, category string, visibleOnly bool) ([]model.FestivalStock, error) {
 	ctx := context.Background()
 
-	stocks, err := gorm.G[model.FestivalStock](r.db).
+	query := gorm.G[model.FestivalStock](r.db).
 		Joins(clause.JoinTarget{Association: "StockItem"}, func(db gorm.JoinBuilder, joinTable clause.Table, curTable clause.Table) error {
 			db.Where(model.StockItem{Category: category})
 			return nil
@@ -61,7 +77,14 @@ func (r *GormRepository) QueryFestivalStocks(festivalID uuid.UUID, category stri
 		Joins(clause.JoinTarget{Association: "Festival"}, func(db gorm.JoinBuilder, joinTable clause.Table, curTable clause.Table) error {
 			db.Where(model.Festival{ID: festivalID})
 			return nil
-		}).
+		})
+	if visibleOnly {
+		query = query.Where(model.FestivalStock{Hidden: false}, "Hidden")
+	}
+
+	stocks, err := query.
+		Order("festival_stocks.position").
+		Order("festival_stocks.id").
 		Find(ctx)
 
 	if err != nil {
@@ -88,6 +111,80 @@ func (r *GormRepository) UpdateFestivalStock(festivalStockID uuid.UUID, descript
 	return nil
 }
 
+func (r *GormRepository) UpdateFestivalStockDisplay(festivalStockID uuid.UUID, hidden bool, color string) error {
+	ctx := context.Background()
+
+	rows, err := gorm.G[model.FestivalStock](r.db).
+		Where(model.FestivalStock{ID: festivalStockID}, "ID").
+		Select("Hidden", "Color").
+		Updates(ctx, model.FestivalStock{Hidden: hidden, Color: color})
+	if err != nil {
+		return wrapGormError(err)
+	}
+	if rows == 0 {
+		// 値が変わらない場合も影響行数は0になるため、存在を確認する
+		if _, err := r.GetFestivalStockByID(festivalStockID); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (r *GormRepository) ReorderFestivalStocks(festivalID uuid.UUID, festivalStockIDs []uuid.UUID) error {
+	ctx := context.Background()
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		stocks, err := gorm.G[model.FestivalStock](tx).
+			Where(model.FestivalStock{FestivalID: festivalID}, "FestivalID").
+			Order("position").
+			Order("id").
+			Find(ctx)
+		if err != nil {
+			return err
+		}
+
+		exists := make(map[uuid.UUID]bool, len(stocks))
+		for _, stock := range stocks {
+			exists[stock.ID] = true
+		}
+
+		ordered := make([]uuid.UUID, 0, len(stocks))
+		seen := make(map[uuid.UUID]bool, len(stocks))
+		for _, id := range festivalStockIDs {
+			if !exists[id] {
+				return repository.ErrNotFound
+			}
+			if seen[id] {
+				continue
+			}
+			seen[id] = true
+			ordered = append(ordered, id)
+		}
+		for _, stock := range stocks {
+			if !seen[stock.ID] {
+				ordered = append(ordered, stock.ID)
+			}
+		}
+
+		for i, id := range ordered {
+			if _, err := gorm.G[model.FestivalStock](tx).
+				Where(model.FestivalStock{ID: id}, "ID").
+				Select("Position").
+				Updates(ctx, model.FestivalStock{Position: i}); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return wrapGormError(err)
+	}
+
+	return nil
+}
+
 func (r *GormRepository) DeleteFestivalStock(festivalStockID uuid.UUID) error {
 	ctx := context.Background()
 
@@ -104,3 +201,39 @@ func (r *GormRepository) DeleteFestivalStock(festivalStockID uuid.UUID) error {
 
 	return nil
 }
+
+func (r *GormRepository) GetDeletedFestivalStocks() ([]model.FestivalStock, error) {
+	ctx := context.Background()
+
+	stocks, err := gorm.G[model.FestivalStock](r.db.Unscoped()).
+		Where("deleted_at IS NOT NULL").
+		Preload("Festival", nil).
+		Preload("StockItem", nil).
+		Order("deleted_at DESC").
+		Find(ctx)
+	if err != nil {
+		return nil, wrapGormError(err)
+	}
+
+	return stocks, nil
+}
+
+func (r *GormRepository) RestoreFestivalStock(festivalStockID uuid.UUID) error {
+	ctx := context.Background()
+
+	// 削除されたイベントやアイテムの在庫は、イベントやアイテムとあわせて復元する
+	rows, err := gorm.G[model.FestivalStock](r.db.Unscoped()).
+		Where(model.FestivalStock{ID: festivalStockID}, "ID").
+		Where("deleted_at IS NOT NULL").
+		Where("festival_id IN (?)", r.db.Model(&model.Festival{}).Select("id")).
+		Where("stock_item_id IN (?)", r.db.Model(&model.StockItem{}).Select("id")).
+		Update(ctx, "deleted_at", nil)
+	if err != nil {
+		return wrapGormError(err)
+	}
+	if rows == 0 {
+		return repository.ErrNotFound
+	}
+
+	return nil
+}
@@ -24,6 +24,14 @@ func TestRegisterFestivalStock(t *testing.T) {
 		assert.Equal(t, 500, festivalStock.Price)
 		assert.Equal(t, "Stock Description", festivalStock.Description)
 	})
+
+	t.Run("Register Festival Stock Appends Position", func(t *testing.T) {
+		first, err := repo.RegisterFestivalStock(fes.ID, item.ID, 100, "First")
+		assert.NoError(t, err)
+		second, err := repo.RegisterFestivalStock(fes.ID, item.ID, 200, "Second")
+		assert.NoError(t, err)
+		assert.Equal(t, first.Position+1, second.Position)
+	})
 }
 
 func TestGetFestivalStockByID(t *testing.T) {
@@ -72,7 +80,7 @@ func TestQueryFestivalStocks(t *testing.T) {
 	stock4 := mustCreateFestivalStock(t, repo, fes2.ID, item1.ID, 700, "Stock Description 4")
 
 	t.Run("Query All Festival Stocks", func(t *testing.T) {
-		stocks, err := repo.QueryFestivalStocks(uuid.Nil, "")
+		stocks, err := repo.QueryFestivalStocks(uuid.Nil, "", false)
 		assert.NoError(t, err)
 		assert.Len(t, stocks, 4)
 
@@ -95,7 +103,7 @@ func TestQueryFestivalStocks(t *testing.T) {
 	})
 
 	t.Run("Query Festival Stocks by Festival ID", func(t *testing.T) {
-		stocks, err := repo.QueryFestivalStocks(fes1.ID, "")
+		stocks, err := repo.QueryFestivalStocks(fes1.ID, "", false)
 		assert.NoError(t, err)
 		assert.Len(t, stocks, 3)
 
@@ -109,7 +117,7 @@ func TestQueryFestivalStocks(t *testing.T) {
 	})
 
 	t.Run("Query Festival Stocks by Category", func(t *testing.T) {
-		stocks, err := repo.QueryFestivalStocks(uuid.Nil, "Category1")
+		stocks, err := repo.QueryFestivalStocks(uuid.Nil, "Category1", false)
 		assert.NoError(t, err)
 		assert.Len(t, stocks, 3)
 
@@ -123,7 +131,7 @@ func TestQueryFestivalStocks(t *testing.T) {
 	})
 
 	t.Run("Query Festival Stocks by Festival ID and Category", func(t *testing.T) {
-		stocks, err := repo.QueryFestivalStocks(fes1.ID, "Category1")
+		stocks, err := repo.QueryFestivalStocks(fes1.ID, "Category1", false)
 		assert.NoError(t, err)
 		assert.Len(t, stocks, 2)
 
@@ -168,6 +176,79 @@ func TestUpdateFestivalStockPrice(t *testing.T) {
 	})
 }
 
+func TestUpdateFestivalStockDisplay(t *testing.T) {
+	repo := setup(t, common)
+
+	fes := mustCreateFestival(t, repo, "Fest for Stock", "Festival Description")
+	item := mustCreateStockItem(t, repo, "Stock Item", "Item Description", "Category", "image_id")
+	fesStock := mustCreateFestivalStock(t, repo, fes.ID, item.ID, 500, "Stock Description")
+
+	t.Run("Update Festival Stock Display", func(t *testing.T) {
+		err := repo.UpdateFestivalStockDisplay(fesStock.ID, true, "#ff0000")
+		assert.NoError(t, err)
+
+		updatedStock, err := repo.GetFestivalStockByID(fesStock.ID)
+		assert.NoError(t, err)
+		assert.True(t, updatedStock.Hidden)
+		assert.Equal(t, "#ff0000", updatedStock.Color)
+	})
+
+	t.Run("Update Festival Stock Display with Same Values", func(t *testing.T) {
+		err := repo.UpdateFestivalStockDisplay(fesStock.ID, true, "#ff0000")
+		assert.NoError(t, err)
+	})
+
+	t.Run("Hidden Festival Stock Is Excluded from Visible Query", func(t *testing.T) {
+		stocks, err := repo.QueryFestivalStocks(fes.ID, "", true)
+		assert.NoError(t, err)
+		assert.Len(t, stocks, 0)
+
+		stocks, err = repo.QueryFestivalStocks(fes.ID, "", false)
+		assert.NoError(t, err)
+		assert.Len(t, stocks, 1)
+	})
+
+	t.Run("Update Non-Existent Festival Stock Display", func(t *testing.T) {
+		id, err := uuid.NewV7()
+		assert.NoError(t, err)
+		err = repo.UpdateFestivalStockDisplay(id, false, "")
+		assert.Equal(t, repository.ErrNotFound, err)
+	})
+}
+
+func TestReorderFestivalStocks(t *testing.T) {
+	repo := setup(t, common)
+
+	fes := mustCreateFestival(t, repo, "Fest for Stock", "Festival Description")
+	other := mustCreateFestival(t, repo, "Other Fest for Stock", "Festival Description")
+	item := mustCreateStockItem(t, repo, "Stock Item", "Item Description", "Category", "image_id")
+	stock1 := mustCreateFestivalStock(t, repo, fes.ID, item.ID, 100, "Stock 1")
+	stock2 := mustCreateFestivalStock(t, repo, fes.ID, item.ID, 200, "Stock 2")
+	stock3 := mustCreateFestivalStock(t, repo, fes.ID, item.ID, 300, "Stock 3")
+	otherStock := mustCreateFestivalStock(t, repo, other.ID, item.ID, 400, "Other Stock")
+
+	t.Run("Reorder Festival Stocks", func(t *testing.T) {
+		err := repo.ReorderFestivalStocks(fes.ID, []uuid.UUID{stock3.ID, stock1.ID})
+		assert.NoError(t, err)
+
+		stocks, err := repo.QueryFestivalStocks(fes.ID, "", false)
+		assert.NoError(t, err)
+		assert.Len(t, stocks, 3)
+		assert.Equal(t, stock3.ID, stocks[0].ID)
+		assert.Equal(t, stock1.ID, stocks[1].ID)
+		assert.Equal(t, stock2.ID, stocks[2].ID)
+	})
+
+	t.Run("Reorder with Stock of Another Festival", func(t *testing.T) {
+		err := repo.ReorderFestivalStocks(fes.ID, []uuid.UUID{otherStock.ID})
+		assert.Equal(t, repository.ErrNotFound, err)
+
+		stocks, err := repo.QueryFestivalStocks(fes.ID, "", false)
+		assert.NoError(t, err)
+		assert.Equal(t, stock3.ID, stocks[0].ID)
+	})
+}
+
 func TestDeleteFestivalStock(t *testing.T) {
 	repo := setup(t, common)
 
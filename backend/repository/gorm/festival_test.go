@@ -11,7 +11,7 @@ import (
 func TestRegisterFestival(t *testing.T) {
 	repo := setup(t, common)
 
-	id, err := repo.RegisterFestival("Test Fest", "A fun festival")
+	id, err := repo.RegisterFestival("Test Fest", "A fun festival", nil, nil)
 	assert.NoError(t, err)
 	assert.NotEqual(t, "", id)
 }
@@ -70,7 +70,7 @@ func TestUpdateFestival(t *testing.T) {
 	festival := mustCreateFestival(t, repo, "Old Fest", "Old Description")
 
 	t.Run("Update Festival", func(t *testing.T) {
-		err := repo.UpdateFestival(festival.ID, "New Fest", "New Description")
+		err := repo.UpdateFestival(festival.ID, "New Fest", "New Description", nil, nil)
 		assert.NoError(t, err)
 		updatedFestival, err := repo.GetFestivalByID(festival.ID)
 		assert.NoError(t, err)
@@ -80,7 +80,7 @@ func TestUpdateFestival(t *testing.T) {
 
 	t.Run("Update Non-Existent Festival", func(t *testing.T) {
 		nonExistentID := uuid.New()
-		err := repo.UpdateFestival(nonExistentID, "Ghost Fest", "No Description")
+		err := repo.UpdateFestival(nonExistentID, "Ghost Fest", "No Description", nil, nil)
 		assert.Equal(t, repository.ErrNotFound, err)
 	})
 }
@@ -102,4 +102,165 @@ func TestDeleteFestival(t *testing.T) {
 		err := repo.DeleteFestival(nonExistentID)
 		assert.Equal(t, repository.ErrNotFound, err)
 	})
-}
\ No newline at end of file
+}
+
+func TestCloneFestival(t *testing.T) {
+	repo := setup(t, common)
+
+	source := mustCreateFestival(t, repo, "Clone Source", "Source Description")
+	item := mustCreateStockItem(t, repo, "Clone Item", "Item Description", "Category", "image_id")
+	stock := mustCreateFestivalStock(t, repo, source.ID, item.ID, 100, "Stock Description")
+	poster := mustCreatePoster(t, repo, source.ID, "Clone Poster", "Poster Description", "clone-img")
+	mustChangePosterStatus(t, repo, poster.ID, "submitted", "in_review")
+
+	t.Run("Clone Festival with Posters", func(t *testing.T) {
+		cloned, err := repo.CloneFestival(source.ID, "Cloned", "Cloned Description", -30, true)
+		assert.NoError(t, err)
+		assert.NotEqual(t, source.ID, cloned.ID)
+		assert.Equal(t, "Cloned", cloned.Name)
+
+		stocks, err := repo.QueryFestivalStocks(cloned.ID, "", false)
+		assert.NoError(t, err)
+		assert.Len(t, stocks, 1)
+		assert.NotEqual(t, stock.ID, stocks[0].ID)
+		assert.Equal(t, item.ID, stocks[0].StockItemID)
+		assert.Equal(t, 70, stocks[0].Price)
+
+		posters, err := repo.GetPostersByFestivalID(cloned.ID)
+		assert.NoError(t, err)
+		assert.Len(t, posters, 1)
+		assert.Equal(t, "Clone Poster", posters[0].PosterName)
+		assert.Equal(t, "clone-img", posters[0].ImageID)
+		assert.Equal(t, "submitted", posters[0].Status)
+
+		count, err := repo.CountPostersByImageID("clone-img")
+		assert.NoError(t, err)
+		assert.Equal(t, int64(2), count)
+	})
+
+	t.Run("Clone Festival without Posters", func(t *testing.T) {
+		cloned, err := repo.CloneFestival(source.ID, "Cloned", "", -1000, false)
+		assert.NoError(t, err)
+
+		stocks, err := repo.QueryFestivalStocks(cloned.ID, "", false)
+		assert.NoError(t, err)
+		assert.Len(t, stocks, 1)
+		assert.Equal(t, 0, stocks[0].Price)
+
+		posters, err := repo.GetPostersByFestivalID(cloned.ID)
+		assert.NoError(t, err)
+		assert.Len(t, posters, 0)
+	})
+
+	t.Run("Clone Non-Existent Festival", func(t *testing.T) {
+		_, err := repo.CloneFestival(uuid.New(), "Ghost", "", 0, false)
+		assert.Equal(t, repository.ErrNotFound, err)
+	})
+}
+
+func TestUpdateFestivalStatus(t *testing.T) {
+	repo := setup(t, common)
+
+	festival := mustCreateFestival(t, repo, "Status Fest", "Status Description")
+
+	t.Run("Default Status", func(t *testing.T) {
+		assert.Equal(t, "planning", festival.Status)
+	})
+
+	t.Run("Update Festival Status", func(t *testing.T) {
+		err := repo.UpdateFestivalStatus(festival.ID, "closed")
+		assert.NoError(t, err)
+		updatedFestival, err := repo.GetFestivalByID(festival.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, "closed", updatedFestival.Status)
+	})
+
+	t.Run("Update Festival Status with Same Value", func(t *testing.T) {
+		err := repo.UpdateFestivalStatus(festival.ID, "closed")
+		assert.NoError(t, err)
+	})
+
+	t.Run("Update Non-Existent Festival Status", func(t *testing.T) {
+		err := repo.UpdateFestivalStatus(uuid.New(), "open")
+		assert.Equal(t, repository.ErrNotFound, err)
+	})
+}
+
+func TestRestoreFestival(t *testing.T) {
+	repo := setup(t, common)
+
+	festival := mustCreateFestival(t, repo, "Restore Fest", "To be restored")
+	assert.NoError(t, repo.DeleteFestival(festival.ID))
+
+	t.Run("Deleted Festival Is Listed", func(t *testing.T) {
+		festivals, err := repo.GetDeletedFestivals()
+		assert.NoError(t, err)
+		found := false
+		for _, fest := range festivals {
+			if fest.ID == festival.ID {
+				found = true
+				assert.True(t, fest.DeletedAt.Valid)
+			}
+		}
+		assert.True(t, found, "deleted festival not found in GetDeletedFestivals result")
+	})
+
+	t.Run("Restore Deleted Festival", func(t *testing.T) {
+		err := repo.RestoreFestival(festival.ID)
+		assert.NoError(t, err)
+		restored, err := repo.GetFestivalByID(festival.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, "Restore Fest", restored.Name)
+	})
+
+	t.Run("Restore Not Deleted Festival", func(t *testing.T) {
+		err := repo.RestoreFestival(festival.ID)
+		assert.Equal(t, repository.ErrNotFound, err)
+	})
+}
+
+func TestDeleteFestival_Cascade(t *testing.T) {
+	repo := setup(t, common)
+
+	festival := mustCreateFestival(t, repo, "Cascade Fest", "Deleted with children")
+	item := mustCreateStockItem(t, repo, "Cascade Item", "Item Description", "Category", "image_id")
+	stock := mustCreateFestivalStock(t, repo, festival.ID, item.ID, 100, "Stock Description")
+	item2 := mustCreateStockItem(t, repo, "Cascade Item 2", "Item Description", "Category", "image_id")
+	deletedStock := mustCreateFestivalStock(t, repo, festival.ID, item2.ID, 200, "Deleted before festival")
+	poster := mustCreatePoster(t, repo, festival.ID, "Cascade Poster", "Poster Description", "cascade-img")
+	deletedPoster := mustCreatePoster(t, repo, festival.ID, "Cascade Deleted Poster", "Poster Description", "cascade-img")
+
+	// イベントより前に個別に削除されたもの
+	assert.NoError(t, repo.DeleteFestivalStock(deletedStock.ID))
+	assert.NoError(t, repo.DeletePoster(deletedPoster.ID))
+
+	assert.NoError(t, repo.DeleteFestival(festival.ID))
+
+	t.Run("Children Are Deleted", func(t *testing.T) {
+		_, err := repo.GetPosterByID(poster.ID)
+		assert.Equal(t, repository.ErrNotFound, err)
+		_, err = repo.GetFestivalStockByID(stock.ID)
+		assert.Equal(t, repository.ErrNotFound, err)
+	})
+
+	t.Run("Children Cannot Be Restored Alone", func(t *testing.T) {
+		_, err := repo.RestorePoster(poster.ID)
+		assert.Equal(t, repository.ErrNotFound, err)
+		assert.Equal(t, repository.ErrNotFound, repo.RestoreFestivalStock(stock.ID))
+	})
+
+	t.Run("Restore With Festival", func(t *testing.T) {
+		assert.NoError(t, repo.RestoreFestival(festival.ID))
+
+		_, err := repo.GetPosterByID(poster.ID)
+		assert.NoError(t, err)
+		_, err = repo.GetFestivalStockByID(stock.ID)
+		assert.NoError(t, err)
+
+		// 個別に削除されていたものは削除されたまま
+		_, err = repo.GetPosterByID(deletedPoster.ID)
+		assert.Equal(t, repository.ErrNotFound, err)
+		_, err = repo.GetFestivalStockByID(deletedStock.ID)
+		assert.Equal(t, repository.ErrNotFound, err)
+	})
+}
@@ -105,7 +105,7 @@ func setup(t *testing.T, dbKey string) *GormRepository {
 func mustCreateFestival(t *testing.T, repo *GormRepository, name string, description string) model.Festival {
 	t.Helper()
 
-	festival, err := repo.RegisterFestival(name, description)
+	festival, err := repo.RegisterFestival(name, description, nil, nil)
 	if err != nil {
 		t.Fatalf("failed to register festival: %v", err)
 	}
@@ -157,4 +157,24 @@ func mustCreateSaleRecord(t *testing.T, repo *GormRepository, festivalStockID uu
 	}
 
 	return saleRecord[0]
-}
\ No newline at end of file
+}
+
+func mustCreateLocation(t *testing.T, repo *GormRepository, building, floor, boardName string) model.Location {
+	t.Helper()
+
+	location, err := repo.RegisterLocation(building, floor, boardName, nil, nil)
+	if err != nil {
+		t.Fatalf("failed to register location: %v", err)
+	}
+
+	return location
+}
+
+func mustChangePosterStatus(t *testing.T, repo *GormRepository, posterID uuid.UUID, from, to string) {
+	t.Helper()
+
+	err := repo.UpdatePosterStatus(posterID, repository.PosterStatusChange{From: from, To: to})
+	if err != nil {
+		t.Fatalf("failed to change poster status: %v", err)
+	}
+}
@@ -0,0 +1,33 @@
+package gorm
+
+import (
+	"context"
+	"slices"
+
+	"github.com/Luke256/ducks/model"
+	"gorm.io/gorm"
+)
+
+func (r *GormRepository) ListImageIDs() ([]string, error) {
+	ctx := context.Background()
+
+	var posterImageIDs []string
+	if err := gorm.G[model.Poster](r.db.Unscoped()).
+		Distinct("image_id").
+		Where("image_id <> ?", "").
+		Scan(ctx, &posterImageIDs); err != nil {
+		return nil, wrapGormError(err)
+	}
+
+	var itemImageIDs []string
+	if err := gorm.G[model.StockItem](r.db.Unscoped()).
+		Distinct("image_id").
+		Where("image_id <> ?", "").
+		Scan(ctx, &itemImageIDs); err != nil {
+		return nil, wrapGormError(err)
+	}
+
+	imageIDs := append(posterImageIDs, itemImageIDs...)
+	slices.Sort(imageIDs)
+	return slices.Compact(imageIDs), nil
+}
@@ -0,0 +1,37 @@
+package gorm
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestListImageIDs(t *testing.T) {
+	repo := setup(t, common)
+
+	festival := mustCreateFestival(t, repo, "Image Festival", "Festival for image listing")
+	mustCreatePoster(t, repo, festival.ID, "Image Poster", "", "list-img-poster")
+	deleted := mustCreatePoster(t, repo, festival.ID, "Deleted Image Poster", "", "list-img-deleted")
+	mustCreateStockItem(t, repo, "Image Item", "", "Cat", "list-img-item")
+	mustCreateStockItem(t, repo, "Shared Image Item", "", "Cat", "list-img-poster")
+
+	if err := repo.DeletePoster(deleted.ID); err != nil {
+		t.Fatalf("failed to delete poster: %v", err)
+	}
+
+	imageIDs, err := repo.ListImageIDs()
+
+	assert.NoError(t, err)
+	assert.Contains(t, imageIDs, "list-img-poster")
+	assert.Contains(t, imageIDs, "list-img-deleted")
+	assert.Contains(t, imageIDs, "list-img-item")
+	assert.NotContains(t, imageIDs, "")
+
+	count := 0
+	for _, id := range imageIDs {
+		if id == "list-img-poster" {
+			count++
+		}
+	}
+	assert.Equal(t, 1, count)
+}
@@ -0,0 +1,84 @@
+package gorm
+
+import (
+	"context"
+	"time"
+
+	"github.com/Luke256/ducks/model"
+	"github.com/Luke256/ducks/repository"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+func (r *GormRepository) CreateImageUpload(targetType string, targetID uuid.UUID, objectKey string, contentType string, status string, expiresAt time.Time) (model.ImageUpload, error) {
+	id, err := uuid.NewV7()
+	if err != nil {
+		return model.ImageUpload{}, err
+	}
+
+	upload := model.ImageUpload{
+		ID:          id,
+		TargetType:  targetType,
+		TargetID:    targetID,
+		ObjectKey:   objectKey,
+		ContentType: contentType,
+		Status:      status,
+		ExpiresAt:   expiresAt,
+	}
+
+	ctx := context.Background()
+
+	if err := gorm.G[model.ImageUpload](r.db).Create(ctx, &upload); err != nil {
+		return model.ImageUpload{}, wrapGormError(err)
+	}
+
+	return upload, nil
+}
+
+func (r *GormRepository) GetImageUploadByID(id uuid.UUID) (model.ImageUpload, error) {
+	ctx := context.Background()
+
+	upload, err := gorm.G[model.ImageUpload](r.db).
+		Where(&model.ImageUpload{ID: id}, "ID").
+		First(ctx)
+	if err != nil {
+		return model.ImageUpload{}, wrapGormError(err)
+	}
+
+	return upload, nil
+}
+
+func (r *GormRepository) GetImageUploadsByStatus(status string) ([]model.ImageUpload, error) {
+	ctx := context.Background()
+
+	uploads, err := gorm.G[model.ImageUpload](r.db).
+		Where(&model.ImageUpload{Status: status}, "Status").
+		Order("created_at").
+		Find(ctx)
+	if err != nil {
+		return nil, wrapGormError(err)
+	}
+
+	return uploads, nil
+}
+
+func (r *GormRepository) UpdateImageUploadStatus(id uuid.UUID, from string, to string, errorMessage string) error {
+	ctx := context.Background()
+
+	// 同時に変更された場合に一方だけが成功するよう、現在のステータスを条件に更新する
+	rows, err := gorm.G[model.ImageUpload](r.db).
+		Where(&model.ImageUpload{ID: id, Status: from}, "ID", "Status").
+		Select("Status", "Error").
+		Updates(ctx, model.ImageUpload{Status: to, Error: errorMessage})
+	if err != nil {
+		return wrapGormError(err)
+	}
+	if rows > 0 {
+		return nil
+	}
+
+	if _, err := r.GetImageUploadByID(id); err != nil {
+		return err
+	}
+	return repository.ErrConflict
+}
@@ -0,0 +1,49 @@
+package gorm
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Luke256/ducks/repository"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestImageUpload(t *testing.T) {
+	repo := setup(t, s2)
+
+	targetID := uuid.New()
+	upload, err := repo.CreateImageUpload("poster", targetID, "uploads/test", "image/png", "waiting", time.Now().Add(time.Hour))
+	assert.NoError(t, err)
+	assert.NotEqual(t, uuid.Nil, upload.ID)
+
+	t.Run("Get Image Upload", func(t *testing.T) {
+		fetched, err := repo.GetImageUploadByID(upload.ID)
+
+		assert.NoError(t, err)
+		assert.Equal(t, targetID, fetched.TargetID)
+		assert.Equal(t, "uploads/test", fetched.ObjectKey)
+		assert.Equal(t, "waiting", fetched.Status)
+
+		_, err = repo.GetImageUploadByID(uuid.New())
+		assert.Equal(t, repository.ErrNotFound, err)
+	})
+
+	t.Run("Update Image Upload Status", func(t *testing.T) {
+		assert.NoError(t, repo.UpdateImageUploadStatus(upload.ID, "waiting", "processing", ""))
+
+		// 既に変更されている場合は競合とする
+		assert.Equal(t, repository.ErrConflict, repo.UpdateImageUploadStatus(upload.ID, "waiting", "processing", ""))
+		assert.Equal(t, repository.ErrNotFound, repo.UpdateImageUploadStatus(uuid.New(), "waiting", "processing", ""))
+
+		processing, err := repo.GetImageUploadsByStatus("processing")
+		assert.NoError(t, err)
+		assert.Len(t, processing, 1)
+
+		assert.NoError(t, repo.UpdateImageUploadStatus(upload.ID, "processing", "failed", "invalid image format"))
+		fetched, err := repo.GetImageUploadByID(upload.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, "failed", fetched.Status)
+		assert.Equal(t, "invalid image format", fetched.Error)
+	})
+}
@@ -0,0 +1,278 @@
+package gorm
+
+import (
+	"context"
+
+	"github.com/Luke256/ducks/model"
+	"github.com/Luke256/ducks/repository"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+func (r *GormRepository) RegisterLocation(building, floor, boardName string, latitude, longitude *float64) (model.Location, error) {
+	id, err := uuid.NewV7()
+	if err != nil {
+		return model.Location{}, err
+	}
+
+	location := model.Location{
+		ID:        id,
+		Building:  building,
+		Floor:     floor,
+		BoardName: boardName,
+		Latitude:  latitude,
+		Longitude: longitude,
+	}
+
+	ctx := context.Background()
+
+	if err := gorm.G[model.Location](r.db).Create(ctx, &location); err != nil {
+		return model.Location{}, wrapGormError(err)
+	}
+
+	return location, nil
+}
+
+func (r *GormRepository) GetLocations() ([]model.Location, error) {
+	ctx := context.Background()
+
+	locations, err := gorm.G[model.Location](r.db).
+		Order("route_order").
+		Order("building").
+		Order("floor").
+		Order("board_name").
+		Find(ctx)
+	if err != nil {
+		return nil, wrapGormError(err)
+	}
+
+	return locations, nil
+}
+
+func (r *GormRepository) GetLocationByID(id uuid.UUID) (model.Location, error) {
+	ctx := context.Background()
+
+	location, err := gorm.G[model.Location](r.db).
+		Where(&model.Location{ID: id}, "ID").
+		First(ctx)
+	if err != nil {
+		return model.Location{}, wrapGormError(err)
+	}
+
+	return location, nil
+}
+
+func (r *GormRepository) UpdateLocation(id uuid.UUID, building, floor, boardName string, latitude, longitude *float64) error {
+	ctx := context.Background()
+
+	rows, err := gorm.G[model.Location](r.db).
+		Where(&model.Location{ID: id}, "ID").
+		Select("Building", "Floor", "BoardName", "Latitude", "Longitude").
+		Updates(ctx, model.Location{
+			Building:  building,
+			Floor:     floor,
+			BoardName: boardName,
+			Latitude:  latitude,
+			Longitude: longitude,
+		})
+	if err != nil {
+		return wrapGormError(err)
+	}
+	if rows == 0 {
+		// 値が変わらない場合も0件になるため、存在を確認する
+		if _, err := r.GetLocationByID(id); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (r *GormRepository) DeleteLocation(id uuid.UUID) error {
+	ctx := context.Background()
+
+	rows, err := gorm.G[model.Location](r.db).
+		Where(&model.Location{ID: id}, "ID").
+		Delete(ctx)
+	if err != nil {
+		return wrapGormError(err)
+	}
+	if rows == 0 {
+		return repository.ErrNotFound
+	}
+
+	return nil
+}
+
+func (r *GormRepository) ReorderLocations(locationIDs []uuid.UUID) error {
+	ctx := context.Background()
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		locations, err := gorm.G[model.Location](tx).
+			Order("route_order").
+			Order("building").
+			Order("floor").
+			Order("board_name").
+			Find(ctx)
+		if err != nil {
+			return err
+		}
+
+		exists := make(map[uuid.UUID]bool, len(locations))
+		for _, location := range locations {
+			exists[location.ID] = true
+		}
+
+		ordered := make([]uuid.UUID, 0, len(locations))
+		seen := make(map[uuid.UUID]bool, len(locations))
+		for _, id := range locationIDs {
+			if !exists[id] {
+				return repository.ErrNotFound
+			}
+			if seen[id] {
+				continue
+			}
+			seen[id] = true
+			ordered = append(ordered, id)
+		}
+		for _, location := range locations {
+			if !seen[location.ID] {
+				ordered = append(ordered, location.ID)
+			}
+		}
+
+		for i, id := range ordered {
+			if _, err := gorm.G[model.Location](tx).
+				Where(model.Location{ID: id}, "ID").
+				Select("RouteOrder").
+				Updates(ctx, model.Location{RouteOrder: i}); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return wrapGormError(err)
+	}
+
+	return nil
+}
+
+func (r *GormRepository) RegisterPosterPlacement(posterID, locationID uuid.UUID, status string) (model.PosterPlacement, error) {
+	id, err := uuid.NewV7()
+	if err != nil {
+		return model.PosterPlacement{}, err
+	}
+
+	ctx := context.Background()
+
+	var placement model.PosterPlacement
+	err = r.db.Transaction(func(tx *gorm.DB) error {
+		// 削除済みのポスターには掲示できない
+		if _, err := gorm.G[model.Poster](tx).
+			Where(&model.Poster{ID: posterID}, "ID").
+			First(ctx); err != nil {
+			return err
+		}
+
+		location, err := gorm.G[model.Location](tx).
+			Where(&model.Location{ID: locationID}, "ID").
+			First(ctx)
+		if err != nil {
+			return err
+		}
+
+		placement = model.PosterPlacement{
+			ID:         id,
+			PosterID:   posterID,
+			LocationID: locationID,
+			Status:     status,
+		}
+		if err := gorm.G[model.PosterPlacement](tx).Create(ctx, &placement); err != nil {
+			return err
+		}
+		placement.Location = location
+
+		return nil
+	})
+	if err != nil {
+		return model.PosterPlacement{}, wrapGormError(err)
+	}
+
+	return placement, nil
+}
+
+func (r *GormRepository) GetPosterPlacementsByPosterID(posterID uuid.UUID) ([]model.PosterPlacement, error) {
+	ctx := context.Background()
+
+	if _, err := r.GetPosterByID(posterID); err != nil {
+		return nil, err
+	}
+
+	placements, err := gorm.G[model.PosterPlacement](r.db).
+		Where(&model.PosterPlacement{PosterID: posterID}, "PosterID").
+		Preload("Location", nil).
+		Order("created_at").
+		Order("id").
+		Find(ctx)
+	if err != nil {
+		return nil, wrapGormError(err)
+	}
+
+	return placements, nil
+}
+
+func (r *GormRepository) GetPosterPlacementsByFestivalID(festivalID uuid.UUID, posterStatus, status string) ([]model.PosterPlacement, error) {
+	ctx := context.Background()
+
+	// 削除済みのポスターの掲示は含めない
+	placements, err := gorm.G[model.PosterPlacement](r.db).
+		Joins(clause.JoinTarget{Association: "Poster"}, func(db gorm.JoinBuilder, joinTable clause.Table, curTable clause.Table) error {
+			db.Where(model.Poster{FestivalID: festivalID, Status: posterStatus})
+			return nil
+		}).
+		Joins(clause.JoinTarget{Association: "Location"}, nil).
+		Where(model.PosterPlacement{Status: status}).
+		Order("poster_placements.created_at").
+		Order("poster_placements.id").
+		Find(ctx)
+	if err != nil {
+		return nil, wrapGormError(err)
+	}
+
+	return placements, nil
+}
+
+func (r *GormRepository) UpdatePosterPlacementStatus(placementID uuid.UUID, status string) error {
+	ctx := context.Background()
+
+	rows, err := gorm.G[model.PosterPlacement](r.db).
+		Where(&model.PosterPlacement{ID: placementID}, "ID").
+		Updates(ctx, model.PosterPlacement{Status: status})
+	if err != nil {
+		return wrapGormError(err)
+	}
+	if rows == 0 {
+		return repository.ErrNotFound
+	}
+
+	return nil
+}
+
+func (r *GormRepository) DeletePosterPlacement(placementID uuid.UUID) error {
+	ctx := context.Background()
+
+	rows, err := gorm.G[model.PosterPlacement](r.db).
+		Where(&model.PosterPlacement{ID: placementID}, "ID").
+		Delete(ctx)
+	if err != nil {
+		return wrapGormError(err)
+	}
+	if rows == 0 {
+		return repository.ErrNotFound
+	}
+
+	return nil
+}
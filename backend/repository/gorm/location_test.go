@@ -0,0 +1,232 @@
+package gorm
+
+import (
+	"testing"
+
+	"github.com/Luke256/ducks/repository"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRegisterLocation(t *testing.T) {
+	repo := setup(t, common)
+
+	latitude, longitude := 35.6051, 139.6838
+	location, err := repo.RegisterLocation("Building A", "1F", "Entrance Board", &latitude, &longitude)
+	assert.NoError(t, err)
+	assert.NotEqual(t, uuid.Nil, location.ID)
+
+	got, err := repo.GetLocationByID(location.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, "Building A", got.Building)
+	assert.Equal(t, "1F", got.Floor)
+	assert.Equal(t, "Entrance Board", got.BoardName)
+	assert.InDelta(t, latitude, *got.Latitude, 1e-6)
+	assert.InDelta(t, longitude, *got.Longitude, 1e-6)
+}
+
+func TestGetLocations(t *testing.T) {
+	repo := setup(t, s1)
+
+	mustCreateLocation(t, repo, "Building B", "2F", "Board 1")
+	mustCreateLocation(t, repo, "Building A", "3F", "Board 2")
+	mustCreateLocation(t, repo, "Building A", "1F", "Board 3")
+
+	locations, err := repo.GetLocations()
+	assert.NoError(t, err)
+	assert.Len(t, locations, 3)
+	assert.Equal(t, "Board 3", locations[0].BoardName)
+	assert.Equal(t, "Board 2", locations[1].BoardName)
+	assert.Equal(t, "Board 1", locations[2].BoardName)
+}
+
+func TestUpdateLocation(t *testing.T) {
+	repo := setup(t, common)
+
+	location := mustCreateLocation(t, repo, "Old Building", "1F", "Old Board")
+
+	t.Run("Update Existing Location", func(t *testing.T) {
+		err := repo.UpdateLocation(location.ID, "New Building", "2F", "New Board", nil, nil)
+		assert.NoError(t, err)
+
+		got, err := repo.GetLocationByID(location.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, "New Building", got.Building)
+		assert.Equal(t, "2F", got.Floor)
+		assert.Equal(t, "New Board", got.BoardName)
+	})
+
+	t.Run("Update Without Changes", func(t *testing.T) {
+		err := repo.UpdateLocation(location.ID, "New Building", "2F", "New Board", nil, nil)
+		assert.NoError(t, err)
+	})
+
+	t.Run("Update Non-Existent Location", func(t *testing.T) {
+		err := repo.UpdateLocation(uuid.New(), "Building", "1F", "Board", nil, nil)
+		assert.Equal(t, repository.ErrNotFound, err)
+	})
+}
+
+func TestDeleteLocation(t *testing.T) {
+	repo := setup(t, common)
+
+	festival := mustCreateFestival(t, repo, "Location Fest", "Fest for locations")
+	poster := mustCreatePoster(t, repo, festival.ID, "PlacedPoster", "placed-desc", "placed-img")
+	used := mustCreateLocation(t, repo, "Building", "1F", "Used Board")
+	unused := mustCreateLocation(t, repo, "Building", "1F", "Unused Board")
+
+	_, err := repo.RegisterPosterPlacement(poster.ID, used.ID, "uncollected")
+	assert.NoError(t, err)
+
+	t.Run("Delete Unused Location", func(t *testing.T) {
+		err := repo.DeleteLocation(unused.ID)
+		assert.NoError(t, err)
+		_, err = repo.GetLocationByID(unused.ID)
+		assert.Equal(t, repository.ErrNotFound, err)
+	})
+
+	t.Run("Delete Location In Use", func(t *testing.T) {
+		err := repo.DeleteLocation(used.ID)
+		assert.Equal(t, repository.ErrForeignKey, err)
+	})
+
+	t.Run("Delete Non-Existent Location", func(t *testing.T) {
+		err := repo.DeleteLocation(uuid.New())
+		assert.Equal(t, repository.ErrNotFound, err)
+	})
+}
+
+func TestPosterPlacement(t *testing.T) {
+	repo := setup(t, common)
+
+	festival := mustCreateFestival(t, repo, "Placement Fest", "Fest for placements")
+	poster := mustCreatePoster(t, repo, festival.ID, "PlacementPoster", "placement-desc", "placement-img")
+	location1 := mustCreateLocation(t, repo, "Building C", "1F", "Board A")
+	location2 := mustCreateLocation(t, repo, "Building C", "2F", "Board B")
+
+	placement1, err := repo.RegisterPosterPlacement(poster.ID, location1.ID, "uncollected")
+	assert.NoError(t, err)
+	assert.Equal(t, location1.ID, placement1.Location.ID)
+	placement2, err := repo.RegisterPosterPlacement(poster.ID, location2.ID, "uncollected")
+	assert.NoError(t, err)
+
+	t.Run("Duplicate Placement", func(t *testing.T) {
+		_, err := repo.RegisterPosterPlacement(poster.ID, location1.ID, "uncollected")
+		assert.Equal(t, repository.ErrAlreadyExists, err)
+	})
+
+	t.Run("Placement of Non-Existent Poster or Location", func(t *testing.T) {
+		_, err := repo.RegisterPosterPlacement(uuid.New(), location1.ID, "uncollected")
+		assert.Equal(t, repository.ErrNotFound, err)
+		_, err = repo.RegisterPosterPlacement(poster.ID, uuid.New(), "uncollected")
+		assert.Equal(t, repository.ErrNotFound, err)
+	})
+
+	t.Run("Update Placement Status", func(t *testing.T) {
+		err := repo.UpdatePosterPlacementStatus(placement2.ID, "collected")
+		assert.NoError(t, err)
+
+		placements, err := repo.GetPosterPlacementsByPosterID(poster.ID)
+		assert.NoError(t, err)
+		assert.Len(t, placements, 2)
+		assert.Equal(t, "uncollected", placements[0].Status)
+		assert.Equal(t, "Board A", placements[0].Location.BoardName)
+		assert.Equal(t, "collected", placements[1].Status)
+		assert.Equal(t, "Board B", placements[1].Location.BoardName)
+
+		err = repo.UpdatePosterPlacementStatus(uuid.New(), "collected")
+		assert.Equal(t, repository.ErrNotFound, err)
+	})
+
+	t.Run("Delete Placement", func(t *testing.T) {
+		err := repo.DeletePosterPlacement(placement1.ID)
+		assert.NoError(t, err)
+
+		placements, err := repo.GetPosterPlacementsByPosterID(poster.ID)
+		assert.NoError(t, err)
+		assert.Len(t, placements, 1)
+
+		err = repo.DeletePosterPlacement(placement1.ID)
+		assert.Equal(t, repository.ErrNotFound, err)
+	})
+
+	t.Run("Placements of Non-Existent Poster", func(t *testing.T) {
+		_, err := repo.GetPosterPlacementsByPosterID(uuid.New())
+		assert.Equal(t, repository.ErrNotFound, err)
+	})
+}
+
+func TestReorderLocations(t *testing.T) {
+	repo := setup(t, s2)
+
+	location1 := mustCreateLocation(t, repo, "Building A", "1F", "Board 1")
+	location2 := mustCreateLocation(t, repo, "Building B", "1F", "Board 2")
+	location3 := mustCreateLocation(t, repo, "Building C", "1F", "Board 3")
+
+	t.Run("Reorder Locations", func(t *testing.T) {
+		err := repo.ReorderLocations([]uuid.UUID{location3.ID, location1.ID})
+		assert.NoError(t, err)
+
+		locations, err := repo.GetLocations()
+		assert.NoError(t, err)
+		assert.Len(t, locations, 3)
+		assert.Equal(t, location3.ID, locations[0].ID)
+		assert.Equal(t, location1.ID, locations[1].ID)
+		assert.Equal(t, location2.ID, locations[2].ID)
+	})
+
+	t.Run("Reorder With Non-Existent Location", func(t *testing.T) {
+		err := repo.ReorderLocations([]uuid.UUID{uuid.New()})
+		assert.Equal(t, repository.ErrNotFound, err)
+	})
+}
+
+func TestGetPosterPlacementsByFestivalID(t *testing.T) {
+	repo := setup(t, common)
+
+	festival := mustCreateFestival(t, repo, "Route Fest", "Fest for routes")
+	other := mustCreateFestival(t, repo, "Other Route Fest", "Other fest")
+	poster1 := mustCreatePoster(t, repo, festival.ID, "RoutePoster1", "route-desc", "route-img1")
+	poster2 := mustCreatePoster(t, repo, festival.ID, "RoutePoster2", "route-desc", "route-img2")
+	deleted := mustCreatePoster(t, repo, festival.ID, "RoutePoster3", "route-desc", "route-img3")
+	otherPoster := mustCreatePoster(t, repo, other.ID, "OtherRoutePoster", "route-desc", "route-img4")
+	location := mustCreateLocation(t, repo, "Route Building", "1F", "Route Board")
+
+	_, err := repo.RegisterPosterPlacement(poster1.ID, location.ID, "uncollected")
+	assert.NoError(t, err)
+	_, err = repo.RegisterPosterPlacement(poster2.ID, location.ID, "collected")
+	assert.NoError(t, err)
+	_, err = repo.RegisterPosterPlacement(deleted.ID, location.ID, "uncollected")
+	assert.NoError(t, err)
+	_, err = repo.RegisterPosterPlacement(otherPoster.ID, location.ID, "uncollected")
+	assert.NoError(t, err)
+	assert.NoError(t, repo.DeletePoster(deleted.ID))
+
+	t.Run("All Statuses", func(t *testing.T) {
+		placements, err := repo.GetPosterPlacementsByFestivalID(festival.ID, "", "")
+		assert.NoError(t, err)
+		assert.Len(t, placements, 2)
+	})
+
+	t.Run("Filter By Status", func(t *testing.T) {
+		placements, err := repo.GetPosterPlacementsByFestivalID(festival.ID, "", "uncollected")
+		assert.NoError(t, err)
+		assert.Len(t, placements, 1)
+		assert.Equal(t, poster1.ID, placements[0].PosterID)
+		assert.Equal(t, "RoutePoster1", placements[0].Poster.PosterName)
+		assert.Equal(t, "route-img1", placements[0].Poster.ImageID)
+		assert.Equal(t, "Route Board", placements[0].Location.BoardName)
+	})
+
+	t.Run("Filter By Poster Status", func(t *testing.T) {
+		placements, err := repo.GetPosterPlacementsByFestivalID(festival.ID, "posted", "")
+		assert.NoError(t, err)
+		assert.Empty(t, placements)
+
+		assert.NoError(t, repo.UpdatePosterStatus(poster2.ID, repository.PosterStatusChange{From: "submitted", To: "posted"}))
+		placements, err = repo.GetPosterPlacementsByFestivalID(festival.ID, "posted", "")
+		assert.NoError(t, err)
+		assert.Len(t, placements, 1)
+		assert.Equal(t, poster2.ID, placements[0].PosterID)
+	})
+}
@@ -2,12 +2,16 @@ package gorm
 
 import (
 	"context"
+	"math"
+	"strings"
+	"time"
 
 	"github.com/Luke256/ducks/model"
 	"github.com/Luke256/ducks/repository"
 	"github.com/google/uuid"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 func (r *GormRepository) RegisterPoster(festivalID uuid.UUID, posterName, description, imageID string) (model.Poster, error) {
@@ -22,7 +26,7 @@ func (r *GormRepository) RegisterPoster(festivalID uuid.UUID, posterName, descri
 		PosterName:  posterName,
 		Description: description,
 		ImageID:     imageID,
-		Status:      "uncollected",
+		Status:      "submitted",
 	}
 
 	ctx := context.Background()
@@ -45,6 +49,64 @@ func (r *GormRepository) GetPostersByFestivalID(festivalID uuid.UUID) ([]model.P
 	return posters, nil
 }
 
+func (r *GormRepository) QueryPosters(query repository.PosterQuery) ([]model.Poster, int64, error) {
+	ctx := context.Background()
+
+	// Countで条件が変わらないよう、取得と件数の計算で別々に組み立てる
+	filter := func() gorm.ChainInterface[model.Poster] {
+		q := gorm.G[model.Poster](r.db).
+			Where(&model.Poster{FestivalID: query.FestivalID}, "FestivalID")
+		if query.Status != "" {
+			q = q.Where(&model.Poster{Status: query.Status}, "Status")
+		}
+		if query.Name != "" {
+			q = q.Where("posters.poster_name LIKE ?", "%"+escapeLike(query.Name)+"%")
+		}
+		return q
+	}
+
+	total, err := filter().Count(ctx, "*")
+	if err != nil {
+		return nil, 0, wrapGormError(err)
+	}
+
+	var column string
+	switch query.Sort {
+	case repository.PosterSortPostingStartDate:
+		column = "posters.posting_start_date"
+	case repository.PosterSortPostingEndDate:
+		column = "posters.posting_end_date"
+	default:
+		column = "posters.poster_name"
+	}
+
+	q := filter().
+		Preload("Festival", nil).
+		Order(clause.OrderByColumn{Column: clause.Column{Name: column, Raw: true}, Desc: query.Desc}).
+		Order("posters.id")
+	if query.Limit > 0 {
+		q = q.Limit(query.Limit)
+	} else if query.Offset > 0 {
+		// MySQLはLIMITのないOFFSETを受け付けないため、残りをすべて取得する上限を付ける
+		q = q.Limit(math.MaxInt)
+	}
+	if query.Offset > 0 {
+		q = q.Offset(query.Offset)
+	}
+
+	posters, err := q.Find(ctx)
+	if err != nil {
+		return nil, 0, wrapGormError(err)
+	}
+
+	return posters, total, nil
+}
+
+// escapeLike LIKE句で特殊な意味を持つ文字をエスケープします
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(s)
+}
+
 func (r *GormRepository) GetPosterByID(posterID uuid.UUID) (model.Poster, error) {
 	ctx := context.Background()
 
@@ -71,6 +133,17 @@ func (r *GormRepository) GetPosterByFestivalIDAndPosterName(festivalID uuid.UUID
 	return poster, nil
 }
 
+func (r *GormRepository) CountPostersByImageID(imageID string) (int64, error) {
+	ctx := context.Background()
+	count, err := gorm.G[model.Poster](r.db.Unscoped()).
+		Where(&model.Poster{ImageID: imageID}, "ImageID").
+		Count(ctx, "*")
+	if err != nil {
+		return 0, wrapGormError(err)
+	}
+	return count, nil
+}
+
 func (r *GormRepository) UpdatePoster(posterID uuid.UUID, posterName, description string) error {
 	ctx := context.Background()
 	rows, err := gorm.G[model.Poster](r.db).
@@ -83,17 +156,140 @@ func (r *GormRepository) UpdatePoster(posterID uuid.UUID, posterName, descriptio
 	return wrapGormError(err)
 }
 
-func (r *GormRepository) UpdatePosterStatus(posterID uuid.UUID, status string) error {
+func (r *GormRepository) UpdatePosterPostingPeriod(posterID uuid.UUID, startDate, endDate *time.Time) error {
 	ctx := context.Background()
+
 	rows, err := gorm.G[model.Poster](r.db).
 		Where(&model.Poster{ID: posterID}, "ID").
-		Updates(ctx, model.Poster{Status: status})
+		Select("PostingStartDate", "PostingEndDate").
+		Updates(ctx, model.Poster{PostingStartDate: startDate, PostingEndDate: endDate})
+	if err != nil {
+		return wrapGormError(err)
+	}
 	if rows == 0 {
-		return repository.ErrNotFound
+		// 値が変わらない場合も0件になるため、存在を確認する
+		if _, err := r.GetPosterByID(posterID); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (r *GormRepository) GetOverduePosters(festivalID *uuid.UUID, status string, now time.Time) ([]model.Poster, error) {
+	ctx := context.Background()
+
+	query := gorm.G[model.Poster](r.db).
+		Joins(clause.JoinTarget{Association: "Festival"}, nil).
+		Where(&model.Poster{Status: status}, "Status").
+		Where("COALESCE(posters.posting_end_date, Festival.end_date) < ?", now)
+	if festivalID != nil {
+		query = query.Where(&model.Poster{FestivalID: *festivalID}, "FestivalID")
 	}
+
+	posters, err := query.
+		Order("COALESCE(posters.posting_end_date, Festival.end_date)").
+		Order("posters.id").
+		Find(ctx)
+	if err != nil {
+		return nil, wrapGormError(err)
+	}
+
+	return posters, nil
+}
+
+func (r *GormRepository) UpdatePosterImage(posterID uuid.UUID, imageID string) (string, error) {
+	ctx := context.Background()
+
+	var oldImageID string
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		// 同時に差し替えられた場合に古い画像IDを取り違えないよう、行をロックする
+		poster, err := gorm.G[model.Poster](tx.Clauses(clause.Locking{Strength: clause.LockingStrengthUpdate})).
+			Where(&model.Poster{ID: posterID}, "ID").
+			First(ctx)
+		if err != nil {
+			return err
+		}
+		oldImageID = poster.ImageID
+
+		_, err = gorm.G[model.Poster](tx).
+			Where(&model.Poster{ID: posterID}, "ID").
+			Updates(ctx, model.Poster{ImageID: imageID})
+		return err
+	})
+	if err != nil {
+		return "", wrapGormError(err)
+	}
+
+	return oldImageID, nil
+}
+
+func (r *GormRepository) UpdatePosterStatus(posterID uuid.UUID, change repository.PosterStatusChange) error {
+	ctx := context.Background()
+
+	historyID, err := uuid.NewV7()
+	if err != nil {
+		return err
+	}
+
+	err = r.db.Transaction(func(tx *gorm.DB) error {
+		// 同時に変更された場合に遷移の検証をすり抜けないよう、行をロックする
+		poster, err := gorm.G[model.Poster](tx.Clauses(clause.Locking{Strength: clause.LockingStrengthUpdate})).
+			Where(&model.Poster{ID: posterID}, "ID").
+			First(ctx)
+		if err != nil {
+			return err
+		}
+		if poster.Status != change.From {
+			return repository.ErrConflict
+		}
+
+		if _, err := gorm.G[model.Poster](tx).
+			Where(&model.Poster{ID: posterID}, "ID").
+			Select("Status", "RejectionReason").
+			Updates(ctx, model.Poster{Status: change.To, RejectionReason: change.RejectionReason}); err != nil {
+			return err
+		}
+
+		if change.PlacementFrom != "" && change.PlacementTo != "" {
+			if _, err := gorm.G[model.PosterPlacement](tx).
+				Where(&model.PosterPlacement{PosterID: posterID, Status: change.PlacementFrom}, "PosterID", "Status").
+				Updates(ctx, model.PosterPlacement{Status: change.PlacementTo}); err != nil {
+				return err
+			}
+		}
+
+		history := model.PosterStatusHistory{
+			ID:             historyID,
+			PosterID:       posterID,
+			PreviousStatus: poster.Status,
+			NewStatus:      change.To,
+			Note:           change.Note,
+		}
+		return gorm.G[model.PosterStatusHistory](tx).Create(ctx, &history)
+	})
+
 	return wrapGormError(err)
 }
 
+func (r *GormRepository) GetPosterStatusHistory(posterID uuid.UUID) ([]model.PosterStatusHistory, error) {
+	ctx := context.Background()
+
+	if _, err := r.GetPosterByID(posterID); err != nil {
+		return nil, err
+	}
+
+	histories, err := gorm.G[model.PosterStatusHistory](r.db).
+		Where(&model.PosterStatusHistory{PosterID: posterID}, "PosterID").
+		Order("created_at").
+		Order("id").
+		Find(ctx)
+	if err != nil {
+		return nil, wrapGormError(err)
+	}
+	return histories, nil
+}
+
 func (r *GormRepository) DeletePoster(posterID uuid.UUID) error {
 	ctx := context.Background()
 	rowsAffected, err := gorm.G[model.Poster](r.db).
@@ -105,3 +301,66 @@ func (r *GormRepository) DeletePoster(posterID uuid.UUID) error {
 
 	return wrapGormError(err)
 }
+
+func (r *GormRepository) GetDeletedPosters() ([]model.Poster, error) {
+	ctx := context.Background()
+	posters, err := gorm.G[model.Poster](r.db.Unscoped()).
+		Where("deleted_at IS NOT NULL").
+		Preload("Festival", nil).
+		Order("deleted_at DESC").
+		Find(ctx)
+	if err != nil {
+		return nil, wrapGormError(err)
+	}
+	return posters, nil
+}
+
+func (r *GormRepository) RestorePoster(posterID uuid.UUID) (model.Poster, error) {
+	ctx := context.Background()
+
+	var poster model.Poster
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		deleted, err := gorm.G[model.Poster](tx.Unscoped()).
+			Where(&model.Poster{ID: posterID}, "ID").
+			Where("deleted_at IS NOT NULL").
+			First(ctx)
+		if err != nil {
+			return err
+		}
+
+		// 削除されたイベントのポスターは、イベントとあわせて復元する
+		if _, err := gorm.G[model.Festival](tx).
+			Where(&model.Festival{ID: deleted.FestivalID}, "ID").
+			First(ctx); err != nil {
+			return err
+		}
+
+		// 削除後に同じ名前のポスターが登録されている場合は復元できない
+		_, err = gorm.G[model.Poster](tx).
+			Where(&model.Poster{FestivalID: deleted.FestivalID, PosterName: deleted.PosterName}, "FestivalID", "PosterName").
+			First(ctx)
+		if err == nil {
+			return repository.ErrAlreadyExists
+		}
+		if err != gorm.ErrRecordNotFound {
+			return err
+		}
+
+		if _, err := gorm.G[model.Poster](tx.Unscoped()).
+			Where(&model.Poster{ID: posterID}, "ID").
+			Update(ctx, "deleted_at", nil); err != nil {
+			return err
+		}
+
+		poster, err = gorm.G[model.Poster](tx).
+			Where(&model.Poster{ID: posterID}, "ID").
+			Preload("Festival", nil).
+			First(ctx)
+		return err
+	})
+	if err != nil {
+		return model.Poster{}, wrapGormError(err)
+	}
+
+	return poster, nil
+}
@@ -2,7 +2,9 @@ package gorm
 
 import (
 	"testing"
+	"time"
 
+	"github.com/Luke256/ducks/model"
 	"github.com/Luke256/ducks/repository"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
@@ -47,6 +49,92 @@ func TestGetPostersByFestivalID(t *testing.T) {
 	})
 }
 
+func TestQueryPosters(t *testing.T) {
+	repo := setup(t, common)
+
+	festival := mustCreateFestival(t, repo, "Query Poster Fest", "Fest for querying posters")
+	other := mustCreateFestival(t, repo, "Other Query Fest", "Another fest")
+	alpha := mustCreatePoster(t, repo, festival.ID, "Alpha Concert", "desc", "img-q1")
+	beta := mustCreatePoster(t, repo, festival.ID, "Beta Concert", "desc", "img-q2")
+	gamma := mustCreatePoster(t, repo, festival.ID, "Gamma 100%", "desc", "img-q3")
+	mustCreatePoster(t, repo, other.ID, "Alpha Concert", "desc", "img-q4")
+	mustChangePosterStatus(t, repo, beta.ID, "submitted", "in_review")
+
+	ids := func(posters []model.Poster) []uuid.UUID {
+		result := make([]uuid.UUID, len(posters))
+		for i, p := range posters {
+			result[i] = p.ID
+		}
+		return result
+	}
+
+	t.Run("All Posters Sorted by Name", func(t *testing.T) {
+		posters, total, err := repo.QueryPosters(repository.PosterQuery{FestivalID: festival.ID})
+		assert.NoError(t, err)
+		assert.Equal(t, int64(3), total)
+		assert.Equal(t, []uuid.UUID{alpha.ID, beta.ID, gamma.ID}, ids(posters))
+		assert.Equal(t, festival.ID, posters[0].Festival.ID)
+	})
+
+	t.Run("Filter by Status", func(t *testing.T) {
+		posters, total, err := repo.QueryPosters(repository.PosterQuery{FestivalID: festival.ID, Status: "in_review"})
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1), total)
+		assert.Equal(t, []uuid.UUID{beta.ID}, ids(posters))
+	})
+
+	t.Run("Filter by Name", func(t *testing.T) {
+		posters, total, err := repo.QueryPosters(repository.PosterQuery{FestivalID: festival.ID, Name: "concert"})
+		assert.NoError(t, err)
+		assert.Equal(t, int64(2), total)
+		assert.Equal(t, []uuid.UUID{alpha.ID, beta.ID}, ids(posters))
+	})
+
+	t.Run("Filter by Name with Wildcard Character", func(t *testing.T) {
+		posters, total, err := repo.QueryPosters(repository.PosterQuery{FestivalID: festival.ID, Name: "%"})
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1), total)
+		assert.Equal(t, []uuid.UUID{gamma.ID}, ids(posters))
+	})
+
+	t.Run("Sort Descending with Pagination", func(t *testing.T) {
+		posters, total, err := repo.QueryPosters(repository.PosterQuery{
+			FestivalID: festival.ID,
+			Desc:       true,
+			Limit:      2,
+			Offset:     1,
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, int64(3), total)
+		assert.Equal(t, []uuid.UUID{beta.ID, alpha.ID}, ids(posters))
+	})
+
+	t.Run("Offset without Limit", func(t *testing.T) {
+		posters, total, err := repo.QueryPosters(repository.PosterQuery{
+			FestivalID: festival.ID,
+			Offset:     1,
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, int64(3), total)
+		assert.Len(t, posters, 2)
+	})
+
+	t.Run("Sort by Posting End Date", func(t *testing.T) {
+		early := time.Date(2026, 5, 1, 0, 0, 0, 0, time.UTC)
+		late := time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)
+		assert.NoError(t, repo.UpdatePosterPostingPeriod(alpha.ID, nil, &late))
+		assert.NoError(t, repo.UpdatePosterPostingPeriod(beta.ID, nil, &early))
+
+		posters, _, err := repo.QueryPosters(repository.PosterQuery{
+			FestivalID: festival.ID,
+			Sort:       repository.PosterSortPostingEndDate,
+			Desc:       true,
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, []uuid.UUID{alpha.ID, beta.ID, gamma.ID}, ids(posters))
+	})
+}
+
 func TestGetPosterByID(t *testing.T) {
 	repo := setup(t, common)
 
@@ -117,16 +205,217 @@ func TestUpdatePosterStatus(t *testing.T) {
 	poster := mustCreatePoster(t, repo, festival.ID, "PosterStatus", "status-desc", "status-img")
 
 	t.Run("Update Poster Status", func(t *testing.T) {
-		err := repo.UpdatePosterStatus(poster.ID, "collected")
+		assert.Equal(t, "submitted", poster.Status)
+
+		err := repo.UpdatePosterStatus(poster.ID, repository.PosterStatusChange{From: "submitted", To: "in_review"})
+		assert.NoError(t, err)
+		p, err := repo.GetPosterByID(poster.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, "in_review", p.Status)
+	})
+
+	t.Run("Update Poster Status With Rejection Reason", func(t *testing.T) {
+		err := repo.UpdatePosterStatus(poster.ID, repository.PosterStatusChange{
+			From:            "in_review",
+			To:              "rejected",
+			Note:            "logo is too small",
+			RejectionReason: "logo is too small",
+		})
 		assert.NoError(t, err)
 		p, err := repo.GetPosterByID(poster.ID)
 		assert.NoError(t, err)
-		assert.Equal(t, "collected", p.Status)
+		assert.Equal(t, "rejected", p.Status)
+		assert.Equal(t, "logo is too small", p.RejectionReason)
+
+		// 再提出すると差し戻しの理由は消える
+		err = repo.UpdatePosterStatus(poster.ID, repository.PosterStatusChange{From: "rejected", To: "submitted"})
+		assert.NoError(t, err)
+		p, err = repo.GetPosterByID(poster.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, "", p.RejectionReason)
+	})
+
+	t.Run("Update Poster Status From Stale Status", func(t *testing.T) {
+		err := repo.UpdatePosterStatus(poster.ID, repository.PosterStatusChange{From: "in_review", To: "approved"})
+		assert.Equal(t, repository.ErrConflict, err)
+		p, err := repo.GetPosterByID(poster.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, "submitted", p.Status)
+	})
+
+	t.Run("Update Poster Status With Placements", func(t *testing.T) {
+		posted := mustCreatePoster(t, repo, festival.ID, "PosterStatusPlaced", "status-desc", "status-img")
+		location1 := mustCreateLocation(t, repo, "Status Building", "1F", "Status Board 1")
+		location2 := mustCreateLocation(t, repo, "Status Building", "1F", "Status Board 2")
+		open, err := repo.RegisterPosterPlacement(posted.ID, location1.ID, "uncollected")
+		assert.NoError(t, err)
+		lost, err := repo.RegisterPosterPlacement(posted.ID, location2.ID, "lost")
+		assert.NoError(t, err)
+
+		err = repo.UpdatePosterStatus(posted.ID, repository.PosterStatusChange{
+			From:          "submitted",
+			To:            "collected",
+			PlacementFrom: "uncollected",
+			PlacementTo:   "collected",
+		})
+		assert.NoError(t, err)
+
+		placements, err := repo.GetPosterPlacementsByPosterID(posted.ID)
+		assert.NoError(t, err)
+		statuses := map[uuid.UUID]string{}
+		for _, p := range placements {
+			statuses[p.ID] = p.Status
+		}
+		assert.Equal(t, map[uuid.UUID]string{open.ID: "collected", lost.ID: "lost"}, statuses)
 	})
 
 	t.Run("Update Non-Existent Poster Status", func(t *testing.T) {
 		nonExistentID := uuid.New()
-		err := repo.UpdatePosterStatus(nonExistentID, "lost")
+		err := repo.UpdatePosterStatus(nonExistentID, repository.PosterStatusChange{From: "posted", To: "lost"})
+		assert.Equal(t, repository.ErrNotFound, err)
+	})
+}
+
+func TestUpdatePosterPostingPeriod(t *testing.T) {
+	repo := setup(t, common)
+
+	festival := mustCreateFestival(t, repo, "Poster Fest", "Fest for posters")
+	poster := mustCreatePoster(t, repo, festival.ID, "PosterPeriod", "period-desc", "period-img")
+
+	start := time.Date(2026, 11, 1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2026, 11, 10, 0, 0, 0, 0, time.UTC)
+
+	t.Run("Update Existing Poster", func(t *testing.T) {
+		err := repo.UpdatePosterPostingPeriod(poster.ID, &start, &end)
+		assert.NoError(t, err)
+
+		updated, err := repo.GetPosterByID(poster.ID)
+		assert.NoError(t, err)
+		assert.True(t, start.Equal(*updated.PostingStartDate))
+		assert.True(t, end.Equal(*updated.PostingEndDate))
+	})
+
+	t.Run("Update Without Changes", func(t *testing.T) {
+		err := repo.UpdatePosterPostingPeriod(poster.ID, &start, &end)
+		assert.NoError(t, err)
+	})
+
+	t.Run("Clear Posting Period", func(t *testing.T) {
+		err := repo.UpdatePosterPostingPeriod(poster.ID, nil, nil)
+		assert.NoError(t, err)
+
+		updated, err := repo.GetPosterByID(poster.ID)
+		assert.NoError(t, err)
+		assert.Nil(t, updated.PostingStartDate)
+		assert.Nil(t, updated.PostingEndDate)
+	})
+
+	t.Run("Update Non-Existent Poster", func(t *testing.T) {
+		err := repo.UpdatePosterPostingPeriod(uuid.New(), &start, &end)
+		assert.Equal(t, repository.ErrNotFound, err)
+	})
+}
+
+func TestGetOverduePosters(t *testing.T) {
+	repo := setup(t, common)
+
+	now := time.Date(2026, 11, 15, 0, 0, 0, 0, time.UTC)
+	festivalEnd := time.Date(2026, 11, 3, 0, 0, 0, 0, time.UTC)
+	posterEnd := time.Date(2026, 11, 1, 0, 0, 0, 0, time.UTC)
+	extendedEnd := time.Date(2026, 11, 30, 0, 0, 0, 0, time.UTC)
+
+	festival, err := repo.RegisterFestival("Overdue Fest", "Fest for overdue posters", nil, &festivalEnd)
+	assert.NoError(t, err)
+
+	fromFestival := mustCreatePoster(t, repo, festival.ID, "OverdueByFestival", "overdue-desc", "overdue-img1")
+	ownPeriod := mustCreatePoster(t, repo, festival.ID, "OverdueByPoster", "overdue-desc", "overdue-img2")
+	extended := mustCreatePoster(t, repo, festival.ID, "Extended", "overdue-desc", "overdue-img3")
+	collected := mustCreatePoster(t, repo, festival.ID, "Collected", "overdue-desc", "overdue-img4")
+
+	assert.NoError(t, repo.UpdatePosterPostingPeriod(ownPeriod.ID, nil, &posterEnd))
+	assert.NoError(t, repo.UpdatePosterPostingPeriod(extended.ID, nil, &extendedEnd))
+	for _, p := range []model.Poster{fromFestival, ownPeriod, extended, collected} {
+		mustChangePosterStatus(t, repo, p.ID, "submitted", "posted")
+	}
+	mustChangePosterStatus(t, repo, collected.ID, "posted", "collected")
+
+	t.Run("Overdue Posters Of Festival", func(t *testing.T) {
+		posters, err := repo.GetOverduePosters(&festival.ID, "posted", now)
+		assert.NoError(t, err)
+		assert.Len(t, posters, 2)
+		assert.Equal(t, ownPeriod.ID, posters[0].ID)
+		assert.Equal(t, fromFestival.ID, posters[1].ID)
+		assert.Equal(t, festival.ID, posters[1].Festival.ID)
+	})
+
+	t.Run("Overdue Posters Of All Festivals", func(t *testing.T) {
+		posters, err := repo.GetOverduePosters(nil, "posted", now)
+		assert.NoError(t, err)
+
+		ids := make([]uuid.UUID, len(posters))
+		for i, p := range posters {
+			ids[i] = p.ID
+		}
+		assert.Contains(t, ids, ownPeriod.ID)
+		assert.Contains(t, ids, fromFestival.ID)
+		assert.NotContains(t, ids, extended.ID)
+		assert.NotContains(t, ids, collected.ID)
+	})
+}
+
+func TestUpdatePosterImage(t *testing.T) {
+	repo := setup(t, common)
+
+	festival := mustCreateFestival(t, repo, "Poster Fest", "Fest for posters")
+	poster := mustCreatePoster(t, repo, festival.ID, "PosterImage", "image-desc", "old-img")
+
+	t.Run("Update Existing Poster", func(t *testing.T) {
+		oldImageID, err := repo.UpdatePosterImage(poster.ID, "new-img")
+		assert.NoError(t, err)
+		assert.Equal(t, "old-img", oldImageID)
+
+		updated, err := repo.GetPosterByID(poster.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, "new-img", updated.ImageID)
+	})
+
+	t.Run("Update Non-Existent Poster", func(t *testing.T) {
+		_, err := repo.UpdatePosterImage(uuid.New(), "new-img")
+		assert.Equal(t, repository.ErrNotFound, err)
+	})
+}
+
+func TestGetPosterStatusHistory(t *testing.T) {
+	repo := setup(t, common)
+
+	festival := mustCreateFestival(t, repo, "Poster Fest", "Fest for posters")
+	poster := mustCreatePoster(t, repo, festival.ID, "PosterHistory", "history-desc", "history-img")
+
+	t.Run("Empty History", func(t *testing.T) {
+		histories, err := repo.GetPosterStatusHistory(poster.ID)
+		assert.NoError(t, err)
+		assert.Len(t, histories, 0)
+	})
+
+	t.Run("Record Status Transitions", func(t *testing.T) {
+		assert.NoError(t, repo.UpdatePosterStatus(poster.ID, repository.PosterStatusChange{From: "submitted", To: "in_review", Note: "received at the office"}))
+		assert.NoError(t, repo.UpdatePosterStatus(poster.ID, repository.PosterStatusChange{From: "in_review", To: "approved"}))
+
+		histories, err := repo.GetPosterStatusHistory(poster.ID)
+		assert.NoError(t, err)
+		assert.Len(t, histories, 2)
+
+		assert.Equal(t, "submitted", histories[0].PreviousStatus)
+		assert.Equal(t, "in_review", histories[0].NewStatus)
+		assert.Equal(t, "received at the office", histories[0].Note)
+		assert.False(t, histories[0].CreatedAt.IsZero())
+
+		assert.Equal(t, "in_review", histories[1].PreviousStatus)
+		assert.Equal(t, "approved", histories[1].NewStatus)
+	})
+
+	t.Run("History of Non-Existent Poster", func(t *testing.T) {
+		_, err := repo.GetPosterStatusHistory(uuid.New())
 		assert.Equal(t, repository.ErrNotFound, err)
 	})
 }
@@ -149,4 +438,35 @@ func TestDeletePoster(t *testing.T) {
 		err := repo.DeletePoster(nonExistentID)
 		assert.Equal(t, repository.ErrNotFound, err)
 	})
-}
\ No newline at end of file
+}
+
+func TestRestorePoster(t *testing.T) {
+	repo := setup(t, common)
+
+	festival := mustCreateFestival(t, repo, "Poster Fest", "Fest for posters")
+	poster := mustCreatePoster(t, repo, festival.ID, "PosterToRestore", "restore-desc", "restore-img")
+	assert.NoError(t, repo.DeletePoster(poster.ID))
+
+	t.Run("Restore Deleted Poster", func(t *testing.T) {
+		restored, err := repo.RestorePoster(poster.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, poster.ID, restored.ID)
+		assert.Equal(t, festival.ID, restored.Festival.ID)
+
+		_, err = repo.GetPosterByID(poster.ID)
+		assert.NoError(t, err)
+	})
+
+	t.Run("Restore Poster with Duplicate Name", func(t *testing.T) {
+		assert.NoError(t, repo.DeletePoster(poster.ID))
+		mustCreatePoster(t, repo, festival.ID, "PosterToRestore", "new-desc", "new-img")
+
+		_, err := repo.RestorePoster(poster.ID)
+		assert.Equal(t, repository.ErrAlreadyExists, err)
+	})
+
+	t.Run("Restore Non-Existent Poster", func(t *testing.T) {
+		_, err := repo.RestorePoster(uuid.New())
+		assert.Equal(t, repository.ErrNotFound, err)
+	})
+}
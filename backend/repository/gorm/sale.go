@@ -74,7 +74,8 @@ func (r *GormRepository) GetSaleRecordsByFestivalStockID(festivalStockID uuid.UU
 func (r *GormRepository) QuerySaleRecords(festivalID, stockItemID uuid.UUID) ([]model.SaleRecord, error) {
 	ctx := context.Background()
 
-	saleRecords, err := gorm.G[model.SaleRecord](r.db).
+	// 削除済みのアイテムの販売記録も集計に含める
+	saleRecords, err := gorm.G[model.SaleRecord](r.db.Unscoped()).
 		Joins(clause.JoinTarget{Association: "FestivalStock"}, func(db gorm.JoinBuilder, joinTable clause.Table, curTable clause.Table) error {
 			db.Where(model.FestivalStock{
 				FestivalID:  festivalID,
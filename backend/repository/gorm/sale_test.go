@@ -185,3 +185,34 @@ func TestDeleteSaleRecord(t *testing.T) {
 		assert.Equal(t, repository.ErrNotFound, err)
 	})
 }
+
+func TestSaleRecordsSurviveFestivalStockDeletion(t *testing.T) {
+	repo := setup(t, common)
+
+	fes := mustCreateFestival(t, repo, "Test Festival", "A festival for testing")
+	stockItem := mustCreateStockItem(t, repo, "Test Stock Item", "An item for testing", "Test Category", "")
+	fesStock := mustCreateFestivalStock(t, repo, fes.ID, stockItem.ID, 100, "Stock Description")
+	record := mustCreateSaleRecord(t, repo, fesStock.ID, 3)
+
+	assert.NoError(t, repo.DeleteFestivalStock(fesStock.ID))
+
+	t.Run("Sale Record Remains", func(t *testing.T) {
+		saleRecord, err := repo.GetSaleRecordByID(record.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, 3, saleRecord.Quantity)
+	})
+
+	t.Run("Query Includes Sales of Deleted Stock", func(t *testing.T) {
+		saleRecords, err := repo.QuerySaleRecords(fes.ID, uuid.Nil)
+		assert.NoError(t, err)
+		assert.Len(t, saleRecords, 1)
+	})
+
+	t.Run("Restore Festival Stock", func(t *testing.T) {
+		assert.NoError(t, repo.RestoreFestivalStock(fesStock.ID))
+
+		saleRecords, err := repo.GetSaleRecordsByFestivalStockID(fesStock.ID)
+		assert.NoError(t, err)
+		assert.Len(t, saleRecords, 1)
+	})
+}
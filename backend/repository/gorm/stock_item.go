@@ -2,6 +2,7 @@ package gorm
 
 import (
 	"context"
+	"time"
 
 	"github.com/Luke256/ducks/model"
 	"github.com/Luke256/ducks/repository"
@@ -91,15 +92,69 @@ func (r *GormRepository) UpdateStockItem(id uuid.UUID, name string, description
 func (r *GormRepository) DeleteStockItem(id uuid.UUID) error {
 	ctx := context.Background()
 
-	rows, err := gorm.G[model.StockItem](r.db).
-		Where(model.StockItem{ID: id}, "ID").
-		Delete(ctx)
+	// 復元時に一緒に削除したものを見分けられるよう、同じ日時で削除する
+	deletedAt := time.Now()
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		rows, err := gorm.G[model.StockItem](tx).
+			Where(&model.StockItem{ID: id}, "ID").
+			Update(ctx, "deleted_at", deletedAt)
+		if err != nil {
+			return err
+		}
+		if rows == 0 {
+			return repository.ErrNotFound
+		}
+
+		// 削除されたアイテムを販売しないよう、イベントの在庫も削除する
+		_, err = gorm.G[model.FestivalStock](tx).
+			Where(&model.FestivalStock{StockItemID: id}, "StockItemID").
+			Update(ctx, "deleted_at", deletedAt)
+		return err
+	})
+
+	return wrapGormError(err)
+}
+
+func (r *GormRepository) GetDeletedStockItems() ([]model.StockItem, error) {
+	ctx := context.Background()
+
+	items, err := gorm.G[model.StockItem](r.db.Unscoped()).
+		Where("deleted_at IS NOT NULL").
+		Order("deleted_at DESC").
+		Find(ctx)
 	if err != nil {
-		return wrapGormError(err)
-	}
-	if rows == 0 {
-		return repository.ErrNotFound
+		return nil, wrapGormError(err)
 	}
-	
-	return nil
+
+	return items, nil
+}
+
+func (r *GormRepository) RestoreStockItem(id uuid.UUID) error {
+	ctx := context.Background()
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		item, err := gorm.G[model.StockItem](tx.Unscoped()).
+			Where(&model.StockItem{ID: id}, "ID").
+			Where("deleted_at IS NOT NULL").
+			First(ctx)
+		if err != nil {
+			return err
+		}
+
+		if _, err := gorm.G[model.StockItem](tx.Unscoped()).
+			Where(&model.StockItem{ID: id}, "ID").
+			Update(ctx, "deleted_at", nil); err != nil {
+			return err
+		}
+
+		// アイテムとともに削除された在庫だけを復元する
+		_, err = gorm.G[model.FestivalStock](tx.Unscoped()).
+			Where(&model.FestivalStock{StockItemID: id}, "StockItemID").
+			Where("deleted_at = ?", item.DeletedAt.Time).
+			Update(ctx, "deleted_at", nil)
+		return err
+	})
+
+	return wrapGormError(err)
 }
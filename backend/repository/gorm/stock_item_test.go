@@ -158,4 +158,58 @@ func TestDeleteStockItem(t *testing.T) {
 
 		assert.Equal(t, repository.ErrNotFound, err)
 	})
-}
\ No newline at end of file
+}
+
+func TestRestoreStockItem(t *testing.T) {
+	repo := setup(t, common)
+
+	item := mustCreateStockItem(t, repo, "Restore Item", "Item Description", "Category", "image_id")
+	assert.NoError(t, repo.DeleteStockItem(item.ID))
+
+	t.Run("Restore Deleted Stock Item", func(t *testing.T) {
+		err := repo.RestoreStockItem(item.ID)
+		assert.NoError(t, err)
+
+		_, err = repo.GetStockItemByID(item.ID)
+		assert.NoError(t, err)
+	})
+
+	t.Run("Restore Not Deleted Stock Item", func(t *testing.T) {
+		err := repo.RestoreStockItem(item.ID)
+		assert.Equal(t, repository.ErrNotFound, err)
+	})
+}
+
+func TestDeleteStockItem_Cascade(t *testing.T) {
+	repo := setup(t, common)
+
+	festival := mustCreateFestival(t, repo, "Item Cascade Fest", "Stocks deleted with the item")
+	item := mustCreateStockItem(t, repo, "Cascade Item", "Item Description", "Category", "image_id")
+	stock := mustCreateFestivalStock(t, repo, festival.ID, item.ID, 100, "Stock Description")
+	deletedStock := mustCreateFestivalStock(t, repo, festival.ID, item.ID, 200, "Deleted before item")
+
+	// アイテムより前に個別に削除された在庫
+	assert.NoError(t, repo.DeleteFestivalStock(deletedStock.ID))
+
+	assert.NoError(t, repo.DeleteStockItem(item.ID))
+
+	t.Run("Stocks Are Deleted", func(t *testing.T) {
+		_, err := repo.GetFestivalStockByID(stock.ID)
+		assert.Equal(t, repository.ErrNotFound, err)
+	})
+
+	t.Run("Stocks Cannot Be Restored Alone", func(t *testing.T) {
+		assert.Equal(t, repository.ErrNotFound, repo.RestoreFestivalStock(stock.ID))
+	})
+
+	t.Run("Restore With Item", func(t *testing.T) {
+		assert.NoError(t, repo.RestoreStockItem(item.ID))
+
+		_, err := repo.GetFestivalStockByID(stock.ID)
+		assert.NoError(t, err)
+
+		// 個別に削除されていた在庫は削除されたまま
+		_, err = repo.GetFestivalStockByID(deletedStock.ID)
+		assert.Equal(t, repository.ErrNotFound, err)
+	})
+}
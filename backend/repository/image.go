@@ -0,0 +1,7 @@
+package repository
+
+type ImageRepository interface {
+	// ListImageIDs ポスターとアイテムが参照している画像のIDを重複なく返します
+	// 削除済みのポスターやアイテムは復元できるため、それらが参照している画像も含みます
+	ListImageIDs() ([]string, error)
+}
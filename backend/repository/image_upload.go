@@ -0,0 +1,23 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/Luke256/ducks/model"
+	"github.com/google/uuid"
+)
+
+type ImageUploadRepository interface {
+	// CreateImageUpload 画像の直接アップロードを登録します
+	CreateImageUpload(targetType string, targetID uuid.UUID, objectKey string, contentType string, status string, expiresAt time.Time) (model.ImageUpload, error)
+
+	// GetImageUploadByID IDから画像の直接アップロードを取得します
+	GetImageUploadByID(id uuid.UUID) (model.ImageUpload, error)
+
+	// GetImageUploadsByStatus 指定されたステータスの画像の直接アップロードを、古い順に取得します
+	GetImageUploadsByStatus(status string) ([]model.ImageUpload, error)
+
+	// UpdateImageUploadStatus ステータスがfromの場合のみ、toに変更します
+	// ステータスがfromでない場合はErrConflictを返します
+	UpdateImageUploadStatus(id uuid.UUID, from string, to string, errorMessage string) error
+}
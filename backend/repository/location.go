@@ -0,0 +1,45 @@
+package repository
+
+import (
+	"github.com/Luke256/ducks/model"
+	"github.com/google/uuid"
+)
+
+type LocationRepository interface {
+	// RegisterLocation 掲示場所を登録します
+	RegisterLocation(building, floor, boardName string, latitude, longitude *float64) (model.Location, error)
+
+	// GetLocations 全ての掲示場所を巡回順・建物・階・掲示板名の順に取得します
+	GetLocations() ([]model.Location, error)
+
+	// GetLocationByID IDから掲示場所を取得します
+	GetLocationByID(id uuid.UUID) (model.Location, error)
+
+	// UpdateLocation 掲示場所を更新します
+	UpdateLocation(id uuid.UUID, building, floor, boardName string, latitude, longitude *float64) error
+
+	// DeleteLocation 掲示場所を削除します
+	// ポスターが掲示されている場合はErrForeignKeyを返します
+	DeleteLocation(id uuid.UUID) error
+
+	// ReorderLocations 掲示場所の巡回順を指定された順に並び替えます
+	// 指定されなかった掲示場所は、元の順序を保って末尾に並べます
+	ReorderLocations(locationIDs []uuid.UUID) error
+
+	// RegisterPosterPlacement ポスターを掲示場所に掲示します
+	// 同じ掲示場所に既に掲示されている場合はErrAlreadyExistsを返します
+	RegisterPosterPlacement(posterID, locationID uuid.UUID, status string) (model.PosterPlacement, error)
+
+	// GetPosterPlacementsByPosterID ポスターの掲示場所一覧を取得します
+	GetPosterPlacementsByPosterID(posterID uuid.UUID) ([]model.PosterPlacement, error)
+
+	// GetPosterPlacementsByFestivalID イベントのポスターの掲示一覧を、ポスターと掲示場所を含めて取得します
+	// posterStatusとstatusはそれぞれポスターと掲示のステータスで絞り込み、空文字の場合は絞り込みません
+	GetPosterPlacementsByFestivalID(festivalID uuid.UUID, posterStatus, status string) ([]model.PosterPlacement, error)
+
+	// UpdatePosterPlacementStatus 掲示場所ごとのステータスを更新します
+	UpdatePosterPlacementStatus(placementID uuid.UUID, status string) error
+
+	// DeletePosterPlacement ポスターを掲示場所から外します
+	DeletePosterPlacement(placementID uuid.UUID) error
+}
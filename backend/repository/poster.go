@@ -1,11 +1,47 @@
 package repository
 
 import (
+	"time"
+
 	"github.com/Luke256/ducks/model"
 
 	"github.com/google/uuid"
 )
 
+// PosterStatusChange ポスターのステータス変更の内容
+type PosterStatusChange struct {
+	// From 変更前のステータス。現在のステータスと異なる場合は変更せずErrConflictを返す
+	From string
+	To   string
+	Note string
+	// RejectionReason 差し戻しの理由。空文字の場合は理由を消去する
+	RejectionReason string
+	// PlacementFrom, PlacementTo 空文字でない場合、ステータスがPlacementFromの掲示をPlacementToに変更する
+	PlacementFrom string
+	PlacementTo   string
+}
+
+const (
+	PosterSortName             = "name"
+	PosterSortPostingStartDate = "posting_start_date"
+	PosterSortPostingEndDate   = "posting_end_date"
+)
+
+// PosterQuery ポスターの検索条件
+// 空文字やゼロ値を指定した条件では絞り込みません
+type PosterQuery struct {
+	FestivalID uuid.UUID
+	Status     string
+	// Name ポスター名に含まれる文字列
+	Name string
+	// Sort 並び替えに用いる項目。空文字の場合はポスター名で並び替える
+	Sort string
+	Desc bool
+	// Limit 取得する最大件数。0の場合はOffset以降の全件を取得する
+	Limit  int
+	Offset int
+}
+
 type PosterRepository interface {
 	// RegisterPoster ポスターを登録します
 	// 登録に成功した場合、ポスターIDを返します
@@ -14,18 +50,50 @@ type PosterRepository interface {
 	// GetPostersByFestivalID イベントIDからポスター一覧を取得します
 	GetPostersByFestivalID(festivalID uuid.UUID) ([]model.Poster, error)
 
+	// QueryPosters 条件に一致するポスターを取得します
+	// 取得したポスターに加えて、LimitとOffsetを適用する前の件数を返します
+	QueryPosters(query PosterQuery) ([]model.Poster, int64, error)
+
 	// GetPosterByID ポスターIDからポスターを取得します
 	GetPosterByID(posterID uuid.UUID) (model.Poster, error)
 
 	// GetPosterByFestivalIDAndPosterName イベントIDとポスター名からポスターを取得します
 	GetPosterByFestivalIDAndPosterName(festivalID uuid.UUID, posterName string) (model.Poster, error)
 
+	// CountPostersByImageID 指定された画像を参照しているポスターの数を取得します
+	// 削除済みのポスターも数に含みます
+	CountPostersByImageID(imageID string) (int64, error)
+
 	// UpdatePoster ポスター情報を更新します
 	UpdatePoster(posterID uuid.UUID, posterName, description string) error
 
-	// UpdatePosterStatus ポスターのステータスを更新します
-	UpdatePosterStatus(posterID uuid.UUID, status string) error
+	// UpdatePosterPostingPeriod ポスターの掲示期間を更新します
+	// nilを指定した日時はイベントの開催期間に従います
+	UpdatePosterPostingPeriod(posterID uuid.UUID, startDate, endDate *time.Time) error
+
+	// GetOverduePosters 掲示期間の終了日時がnowより前で、指定されたステータスのポスターを終了日時の古い順に取得します
+	// ポスターの終了日時が未設定の場合はイベントの終了日時を用います
+	// festivalIDがnilの場合、全てのイベントのポスターを対象にします
+	GetOverduePosters(festivalID *uuid.UUID, status string, now time.Time) ([]model.Poster, error)
+
+	// UpdatePosterImage ポスターの画像を差し替え、差し替え前の画像IDを返します
+	UpdatePosterImage(posterID uuid.UUID, imageID string) (string, error)
+
+	// UpdatePosterStatus ポスターのステータスを更新し、変更履歴を記録します
+	// 現在のステータスがchange.Fromと異なる場合はErrConflictを返します
+	UpdatePosterStatus(posterID uuid.UUID, change PosterStatusChange) error
+
+	// GetPosterStatusHistory ポスターのステータス変更履歴を古い順に取得します
+	GetPosterStatusHistory(posterID uuid.UUID) ([]model.PosterStatusHistory, error)
 
 	// DeletePoster ポスターを削除します
+	// 削除は論理削除であり、RestorePosterで復元できます
 	DeletePoster(posterID uuid.UUID) error
+
+	// GetDeletedPosters 削除済みのポスターを取得します
+	GetDeletedPosters() ([]model.Poster, error)
+
+	// RestorePoster 削除済みのポスターを復元します
+	// イベントが削除されている場合はErrNotFoundを返します
+	RestorePoster(posterID uuid.UUID) (model.Poster, error)
 }
@@ -6,6 +6,7 @@ var (
 	ErrNotFound      = errors.New("record not found")
 	ErrAlreadyExists = errors.New("record already exists")
 	ErrForeignKey    = errors.New("foreign key constraint failed")
+	ErrConflict      = errors.New("record has been modified")
 )
 
 type Repository interface {
@@ -14,4 +15,7 @@ type Repository interface {
 	StockItemRepository
 	FestivalStockRepository
 	SaleRepository
+	LocationRepository
+	ImageRepository
+	ImageUploadRepository
 }
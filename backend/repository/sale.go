@@ -21,6 +21,7 @@ type SaleRepository interface {
 	GetSaleRecordsByFestivalStockID(festivalStockID uuid.UUID) ([]model.SaleRecord, error)
 
 	// QuerySaleRecords イベントIDと商品IDから販売記録を取得します
+	// 削除済みのイベントで販売するアイテムの販売記録も含みます
 	QuerySaleRecords(festivalID, stockItemID uuid.UUID) ([]model.SaleRecord, error)
 
 	// DeleteSaleRecord 販売記録を削除します
@@ -18,6 +18,13 @@ type StockItemRepository interface {
 	// UpdateStockItem アイテムを更新します
 	UpdateStockItem(id uuid.UUID, name string, description string, category string, imageID string) (model.StockItem, error)
 
-	// DeleteStockItem アイテムを削除します
+	// DeleteStockItem アイテムと、イベントで販売するそのアイテムを削除します
+	// 削除は論理削除であり、RestoreStockItemで復元できます
 	DeleteStockItem(id uuid.UUID) error
+
+	// GetDeletedStockItems 削除済みのアイテムを取得します
+	GetDeletedStockItems() ([]model.StockItem, error)
+
+	// RestoreStockItem 削除済みのアイテムを、一緒に削除されたイベントで販売するアイテムとともに復元します
+	RestoreStockItem(id uuid.UUID) error
 }
@@ -19,6 +19,10 @@ func Forbidden(message ...any) error {
 	return HTTPError(http.StatusForbidden, message)
 }
 
+func Conflict(message ...any) error {
+	return HTTPError(http.StatusConflict, message)
+}
+
 func InternalServerError(message ...any) error {
 	return HTTPError(http.StatusInternalServerError, message)
 }
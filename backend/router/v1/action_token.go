@@ -0,0 +1,154 @@
+package v1
+
+import (
+	"bytes"
+	"embed"
+	"html/template"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/Luke256/ducks/router/utils/herror"
+	"github.com/Luke256/ducks/service/poster"
+	"github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	// defaultActionTokenTTL 有効期間を指定しなかった場合のトークンの有効期間
+	defaultActionTokenTTL = 30 * 24 * time.Hour
+	// maxActionTokenTTL トークンに指定できる最長の有効期間
+	maxActionTokenTTL = 180 * 24 * time.Hour
+)
+
+//go:embed templates/action.html
+var actionFS embed.FS
+
+var actionTemplate = template.Must(template.ParseFS(actionFS, "templates/action.html"))
+
+type IssueActionTokenRequest struct {
+	ID         string `param:"id"`
+	Status     string `json:"status"`
+	TTLSeconds int64  `json:"ttl_seconds"`
+}
+
+func (r IssueActionTokenRequest) Validate() error {
+	return validation.ValidateStruct(&r,
+		validation.Field(&r.ID, validation.Required),
+		validation.Field(&r.Status,
+			validation.Required,
+			validation.In(
+				PosterStatusSubmitted,
+				PosterStatusInReview,
+				PosterStatusApproved,
+				PosterStatusPosted,
+				PosterStatusCollected,
+				PosterStatusLost,
+			),
+		),
+		validation.Field(&r.TTLSeconds, validation.Min(0), validation.Max(int64(maxActionTokenTTL/time.Second))),
+	)
+}
+
+type IssueActionTokenResponse struct {
+	poster.ActionToken
+	URL string `json:"url"`
+}
+
+type actionPage struct {
+	Target poster.ActionTarget
+	Error  string
+}
+
+// actionURL QRコードに埋め込む、トークンを適用するページのURLを返します
+func (h *Handler) actionURL(token string) string {
+	return h.config.APIEndpoint + "/api/v1/actions/" + token
+}
+
+func (h *Handler) IssuePosterActionToken(c echo.Context) error {
+	var req IssueActionTokenRequest
+	if err := c.Bind(&req); err != nil {
+		return herror.BadRequest("Invalid request")
+	}
+
+	if err := req.Validate(); err != nil {
+		return herror.BadRequest("Validation failed: " + err.Error())
+	}
+
+	posterID, err := uuid.Parse(req.ID)
+	if err != nil {
+		return herror.NotFound("Poster not found")
+	}
+
+	ttl := defaultActionTokenTTL
+	if req.TTLSeconds > 0 {
+		ttl = time.Duration(req.TTLSeconds) * time.Second
+	}
+
+	token, err := h.posterManager.IssueActionToken(posterID, req.Status, ttl)
+	if err != nil {
+		switch err {
+		case poster.ErrNotFound:
+			return herror.NotFound("Poster not found")
+		case poster.ErrReasonRequired:
+			return herror.BadRequest("Rejection requires a reason and cannot be done with an action token")
+		default:
+			slog.Error("failed to issue action token:", slog.String("error", err.Error()))
+			return herror.InternalServerError("Failed to issue action token")
+		}
+	}
+
+	return c.JSON(201, IssueActionTokenResponse{
+		ActionToken: token,
+		URL:         h.actionURL(token.Token),
+	})
+}
+
+// GetAction トークンで変更するポスターを確認し、変更を適用するためのページをHTMLで返します
+// ステータスは変更しません
+func (h *Handler) GetAction(c echo.Context) error {
+	target, err := h.posterManager.InspectActionToken(c.Param("token"))
+	if err != nil {
+		switch err {
+		case poster.ErrInvalidToken, poster.ErrNotFound:
+			return h.renderActionPage(c, http.StatusNotFound, actionPage{Error: "無効なリンクです"})
+		case poster.ErrTokenExpired:
+			return h.renderActionPage(c, http.StatusGone, actionPage{Error: "リンクの有効期限が切れています"})
+		default:
+			slog.Error("failed to inspect action token:", slog.String("error", err.Error()))
+			return herror.InternalServerError("Failed to inspect action token")
+		}
+	}
+
+	return h.renderActionPage(c, http.StatusOK, actionPage{Target: target})
+}
+
+func (h *Handler) ApplyAction(c echo.Context) error {
+	p, err := h.posterManager.ApplyActionToken(c.Param("token"))
+	if err != nil {
+		switch err {
+		case poster.ErrInvalidToken, poster.ErrNotFound:
+			return herror.NotFound("Invalid action token")
+		case poster.ErrTokenExpired:
+			return herror.HTTPError(http.StatusGone, "Action token has expired")
+		case poster.ErrInvalidTransition:
+			return herror.Conflict("Invalid status transition")
+		default:
+			slog.Error("failed to apply action token:", slog.String("error", err.Error()))
+			return herror.InternalServerError("Failed to apply action token")
+		}
+	}
+
+	return c.JSON(200, p)
+}
+
+func (h *Handler) renderActionPage(c echo.Context, code int, page actionPage) error {
+	var buf bytes.Buffer
+	if err := actionTemplate.Execute(&buf, page); err != nil {
+		slog.Error("failed to render action page:", slog.String("error", err.Error()))
+		return herror.InternalServerError("Failed to render action page")
+	}
+
+	return c.HTMLBlob(code, buf.Bytes())
+}
@@ -0,0 +1,132 @@
+package v1
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestPosterActionToken(t *testing.T) {
+	env := setup(t, s1)
+	e := env.R(t)
+
+	fes := env.mustCreateFestival(t, "Action Token Fest", "Festival for action tokens")
+	poster := env.mustCreatePoster(t, fes.ID, "Action Token Poster", "Poster collected via action token")
+	env.mustChangePosterStatus(t, poster.ID, PosterStatusInReview, PosterStatusApproved, PosterStatusPosted)
+
+	var token string
+
+	t.Run("issue action token", func(t *testing.T) {
+		resp := e.POST("/api/posters/{posterID}/action-tokens", poster.ID.String()).
+			WithJSON(map[string]any{
+				"status":      PosterStatusCollected,
+				"ttl_seconds": 3600,
+			}).
+			Expect().
+			Status(201).
+			JSON().
+			Object()
+		resp.Value("poster_id").IsEqual(poster.ID.String())
+		resp.Value("status").IsEqual(PosterStatusCollected)
+		resp.Value("url").String().HasSuffix("/api/v1/actions/" + resp.Value("token").String().Raw())
+
+		token = resp.Value("token").String().Raw()
+	})
+
+	t.Run("inspect action token", func(t *testing.T) {
+		e.GET("/api/actions/{token}", token).
+			Expect().
+			Status(200).
+			ContentType("text/html").
+			Body().
+			Contains(poster.Name)
+
+		// 確認だけではステータスは変わらない
+		e.GET("/api/posters/{posterID}", poster.ID.String()).
+			Expect().
+			Status(200).
+			JSON().
+			Object().
+			Value("status").IsEqual(PosterStatusPosted)
+	})
+
+	t.Run("apply action token", func(t *testing.T) {
+		e.POST("/api/actions/{token}", token).
+			Expect().
+			Status(200).
+			JSON().
+			Object().
+			Value("status").IsEqual(PosterStatusCollected)
+
+		history := e.GET("/api/posters/{posterID}/history", poster.ID.String()).
+			Expect().
+			Status(200).
+			JSON().
+			Object().
+			Value("history").
+			Array()
+		history.Value(len(history.Iter()) - 1).Object().Value("new_status").IsEqual(PosterStatusCollected)
+	})
+
+	t.Run("apply action token twice", func(t *testing.T) {
+		e.POST("/api/actions/{token}", token).
+			Expect().
+			Status(409)
+	})
+
+	t.Run("tampered action token", func(t *testing.T) {
+		first := "A"
+		if strings.HasPrefix(token, first) {
+			first = "B"
+		}
+		tampered := first + token[1:]
+		e.GET("/api/actions/{token}", tampered).
+			Expect().
+			Status(404)
+		e.POST("/api/actions/{token}", tampered).
+			Expect().
+			Status(404)
+	})
+
+	t.Run("expired action token", func(t *testing.T) {
+		expired, err := env.PM.IssueActionToken(poster.ID, PosterStatusLost, -time.Minute)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		e.GET("/api/actions/{token}", expired.Token).
+			Expect().
+			Status(410)
+		e.POST("/api/actions/{token}", expired.Token).
+			Expect().
+			Status(410)
+	})
+
+	t.Run("issue action token for rejection", func(t *testing.T) {
+		e.POST("/api/posters/{posterID}/action-tokens", poster.ID.String()).
+			WithJSON(map[string]any{
+				"status": PosterStatusRejected,
+			}).
+			Expect().
+			Status(400)
+	})
+
+	t.Run("issue action token with too long ttl", func(t *testing.T) {
+		e.POST("/api/posters/{posterID}/action-tokens", poster.ID.String()).
+			WithJSON(map[string]any{
+				"status":      PosterStatusCollected,
+				"ttl_seconds": 365 * 24 * 60 * 60,
+			}).
+			Expect().
+			Status(400)
+	})
+
+	t.Run("issue action token for non-existent poster", func(t *testing.T) {
+		e.POST("/api/posters/{posterID}/action-tokens", "00000000-0000-0000-0000-000000000000").
+			WithJSON(map[string]any{
+				"status": PosterStatusCollected,
+			}).
+			Expect().
+			Status(404)
+	})
+}
@@ -0,0 +1,18 @@
+package v1
+
+import (
+	"crypto/subtle"
+
+	"github.com/labstack/echo/v4"
+)
+
+// isAdminOverride リクエストが管理者トークンを持ち、イベントのロックを無視できるかを返します
+// 管理者トークンが設定されていない場合は常にfalseを返します
+func (h *Handler) isAdminOverride(c echo.Context) bool {
+	if h.config.AdminToken == "" {
+		return false
+	}
+
+	auth := c.Request().Header.Get(echo.HeaderAuthorization)
+	return subtle.ConstantTimeCompare([]byte(auth), []byte("Bearer "+h.config.AdminToken)) == 1
+}
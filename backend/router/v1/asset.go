@@ -1,24 +1,169 @@
 package v1
 
 import (
+	"errors"
+	"io"
 	"log/slog"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/Luke256/ducks/router/utils/herror"
+	"github.com/Luke256/ducks/utils/compressor"
+	"github.com/Luke256/ducks/utils/storage"
+	"github.com/Luke256/ducks/utils/transcode"
 
 	"github.com/labstack/echo/v4"
 )
 
+// GetImage 画像を返します
+// sizeに縮小画像の名前か長辺のピクセル数を指定すると、それに最も近い縮小画像を返します
+// 保存された形式をAcceptヘッダーで受け付けないクライアントには、JPEGかPNGに変換して返します
+// 条件付きリクエストとRangeリクエストはserveImageで処理します
 func (h *Handler) GetImage(c echo.Context) error {
 	imageID := c.Param("id")
 
-	file, err := h.storage.DownloadFile(imageID)
+	rendition, err := parseImageSize(c.QueryParam("size"))
+	if err != nil {
+		return herror.BadRequest("Invalid size")
+	}
+
+	fileName, file, meta, err := h.downloadImage(c, imageID, rendition)
 	if err != nil {
 		slog.Error("failed to download image", "error", err, "image_id", imageID)
 		return herror.NotFound()
 	}
 	defer file.Close()
 
+	contentType := meta.ContentType
+	if contentType == "" {
+		contentType = storage.ContentTypeByFileName(fileName)
+	}
+
 	c.Response().Header().Set(echo.HeaderCacheControl, "max-age=31536000, immutable")
+	c.Response().Header().Add(echo.HeaderVary, echo.HeaderAccept)
+
+	format := negotiateImageFormat(c.Request().Header.Get(echo.HeaderAccept), contentType)
+	if format == "" {
+		return serveImage(c, file, contentType, meta.ModTime, meta.Digest)
+	}
+
+	converted, err := h.transcoder.Open(fileName, format, func() (io.ReadCloser, error) {
+		return io.NopCloser(file), nil
+	})
+	if err != nil {
+		slog.Error("failed to transcode image", "error", err, "image_id", imageID, "format", format)
+		return herror.InternalServerError("Failed to transcode image")
+	}
+	defer converted.Close()
+
+	info, err := converted.Stat()
+	if err != nil {
+		slog.Error("failed to stat transcoded image", "error", err, "image_id", imageID, "format", format)
+		return herror.InternalServerError("Failed to transcode image")
+	}
+
+	return serveImage(c, converted, transcode.Formats[format], info.ModTime(), converted.Digest)
+}
+
+// serveImage 保存時に求めた内容のハッシュをETagとして画像を返します
+// If-None-Match, If-Modified-Sinceによる条件付きリクエストとRangeリクエストに対応します
+// 変換後の画像は変換前と内容が異なるため、形式ごとに異なるETagになります
+// ハッシュが不明な場合はETagを付けず、更新日時のみで条件付きリクエストに対応します
+func serveImage(c echo.Context, file io.ReadSeeker, contentType string, modTime time.Time, digest string) error {
+	header := c.Response().Header()
+	header.Set(echo.HeaderContentType, contentType)
+	if digest != "" {
+		header.Set("ETag", `"`+digest+`"`)
+	}
+
+	http.ServeContent(c.Response(), c.Request(), "", modTime, file)
+	return nil
+}
+
+// downloadImage 縮小画像をダウンロードし、そのファイル名とともに返します
+// 元の画像が小さい場合や縮小画像の導入前にアップロードされた画像など、縮小画像がない場合は元の画像を返します
+func (h *Handler) downloadImage(c echo.Context, imageID, rendition string) (string, io.ReadSeekCloser, storage.Metadata, error) {
+	ctx := c.Request().Context()
 
-	return c.Stream(200, "image/webp", file)
+	if rendition != "" {
+		fileName := storage.RenditionFileName(imageID, rendition)
+		file, meta, err := h.storage.DownloadFile(ctx, fileName)
+		if err == nil {
+			return fileName, file, meta, nil
+		}
+		if !errors.Is(err, storage.ErrFileNotFound) {
+			return "", nil, storage.Metadata{}, err
+		}
+	}
+
+	file, meta, err := h.storage.DownloadFile(ctx, imageID)
+	return imageID, file, meta, err
+}
+
+// parseImageSize sizeパラメータから縮小画像の名前を返します
+// 元の画像を表す場合は空文字を返します
+func parseImageSize(size string) (string, error) {
+	if size == "" || size == "original" {
+		return "", nil
+	}
+
+	if slices.ContainsFunc(compressor.Renditions, func(r compressor.Rendition) bool { return r.Name == size }) {
+		return size, nil
+	}
+
+	px, err := strconv.Atoi(size)
+	if err != nil || px <= 0 {
+		return "", errors.New("invalid size")
+	}
+	return compressor.PickRendition(px), nil
+}
+
+// negotiateImageFormat 保存された形式のまま返せない場合に、変換先の形式を返します
+// 変換が不要な場合は空文字を返します
+//
+// webpに対応していないブラウザもimage/*や*/*を送るため、webpはAcceptに明示されている場合のみ受け付けるとみなします
+func negotiateImageFormat(accept, contentType string) string {
+	// JPEGやPNGはどのクライアントでも表示できる
+	if accept == "" || contentType != "image/webp" {
+		return ""
+	}
+
+	qualities := parseAccept(accept)
+	if q, ok := qualities[contentType]; ok && q > 0 {
+		return ""
+	}
+
+	jpegQ, jpegOK := qualities["image/jpeg"]
+	pngQ, pngOK := qualities["image/png"]
+	if pngOK && pngQ > 0 && (!jpegOK || pngQ > jpegQ) {
+		return "png"
+	}
+	return "jpeg"
+}
+
+// parseAccept Acceptヘッダーを、メディアタイプごとの品質係数に変換します
+func parseAccept(accept string) map[string]float64 {
+	qualities := map[string]float64{}
+	for _, part := range strings.Split(accept, ",") {
+		mediaType, params, _ := strings.Cut(part, ";")
+		mediaType = strings.ToLower(strings.TrimSpace(mediaType))
+		if mediaType == "" {
+			continue
+		}
+
+		q := 1.0
+		for _, param := range strings.Split(params, ";") {
+			key, value, ok := strings.Cut(strings.TrimSpace(param), "=")
+			if ok && strings.TrimSpace(key) == "q" {
+				if v, err := strconv.ParseFloat(strings.TrimSpace(value), 64); err == nil {
+					q = v
+				}
+			}
+		}
+		qualities[mediaType] = q
+	}
+	return qualities
 }
@@ -0,0 +1,157 @@
+package v1
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+func TestGetImage_InvalidSize(t *testing.T) {
+	env := setup(t, common)
+	e := env.R(t)
+
+	for _, size := range []string{"huge", "0", "-1"} {
+		e.GET("/api/images/{id}", "image.webp").
+			WithQuery("size", size).
+			Expect().
+			Status(400)
+	}
+}
+
+func TestParseImageSize(t *testing.T) {
+	tests := []struct {
+		size string
+		want string
+	}{
+		{"", ""},
+		{"original", ""},
+		{"thumb", "thumb"},
+		{"medium", "medium"},
+		{"200", "thumb"},
+		{"800", "medium"},
+		{"4000", ""},
+	}
+
+	for _, tt := range tests {
+		got, err := parseImageSize(tt.size)
+		if err != nil {
+			t.Errorf("parseImageSize(%q) returned error: %v", tt.size, err)
+		}
+		if got != tt.want {
+			t.Errorf("parseImageSize(%q) = %q, want %q", tt.size, got, tt.want)
+		}
+	}
+}
+
+func TestNegotiateImageFormat(t *testing.T) {
+	tests := []struct {
+		name        string
+		accept      string
+		contentType string
+		want        string
+	}{
+		{"no accept header", "", "image/webp", ""},
+		{"accepts webp", "image/avif,image/webp,image/apng,image/*,*/*;q=0.8", "image/webp", ""},
+		{"wildcard only", "*/*", "image/webp", "jpeg"},
+		{"image wildcard without webp", "image/png,image/svg+xml,image/*;q=0.8,*/*;q=0.5", "image/webp", "png"},
+		{"rejects webp", "image/webp;q=0,image/jpeg", "image/webp", "jpeg"},
+		{"prefers jpeg", "image/jpeg,image/png;q=0.5", "image/webp", "jpeg"},
+		{"stored as jpeg", "image/png", "image/jpeg", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := negotiateImageFormat(tt.accept, tt.contentType); got != tt.want {
+				t.Errorf("negotiateImageFormat(%q, %q) = %q, want %q", tt.accept, tt.contentType, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestServeImage(t *testing.T) {
+	content := []byte("0123456789")
+	modTime := time.Date(2025, 4, 1, 12, 0, 0, 0, time.UTC)
+	digest := "84d89877f0d4041efb6bf91a16f0248f2fd573e6af05c19f96bedb9f882f7882"
+
+	serve := func(t *testing.T, header http.Header) *httptest.ResponseRecorder {
+		t.Helper()
+		req := httptest.NewRequest(http.MethodGet, "/api/images/image.webp", nil)
+		req.Header = header
+		rec := httptest.NewRecorder()
+		c := echo.New().NewContext(req, rec)
+		if err := serveImage(c, bytes.NewReader(content), "image/webp", modTime, digest); err != nil {
+			t.Fatalf("serveImage failed: %v", err)
+		}
+		return rec
+	}
+
+	rec := serve(t, http.Header{})
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rec.Code)
+	}
+	if rec.Body.String() != string(content) {
+		t.Errorf("unexpected body %q", rec.Body.String())
+	}
+	if rec.Header().Get(echo.HeaderContentType) != "image/webp" {
+		t.Errorf("unexpected content type %q", rec.Header().Get(echo.HeaderContentType))
+	}
+	etag := rec.Header().Get("ETag")
+	if etag != `"`+digest+`"` {
+		t.Fatalf("expected ETag from digest, got %q", etag)
+	}
+	if rec.Header().Get(echo.HeaderLastModified) != modTime.Format(http.TimeFormat) {
+		t.Errorf("unexpected Last-Modified %q", rec.Header().Get(echo.HeaderLastModified))
+	}
+
+	t.Run("If-None-Match", func(t *testing.T) {
+		rec := serve(t, http.Header{"If-None-Match": {etag}})
+		if rec.Code != http.StatusNotModified {
+			t.Errorf("expected 304, got %d", rec.Code)
+		}
+
+		rec = serve(t, http.Header{"If-None-Match": {`"other"`}})
+		if rec.Code != http.StatusOK {
+			t.Errorf("expected 200 for mismatched ETag, got %d", rec.Code)
+		}
+	})
+
+	t.Run("If-Modified-Since", func(t *testing.T) {
+		rec := serve(t, http.Header{"If-Modified-Since": {modTime.Format(http.TimeFormat)}})
+		if rec.Code != http.StatusNotModified {
+			t.Errorf("expected 304, got %d", rec.Code)
+		}
+
+		rec = serve(t, http.Header{"If-Modified-Since": {modTime.Add(-time.Hour).Format(http.TimeFormat)}})
+		if rec.Code != http.StatusOK {
+			t.Errorf("expected 200 for older date, got %d", rec.Code)
+		}
+	})
+
+	t.Run("Range", func(t *testing.T) {
+		rec := serve(t, http.Header{"Range": {"bytes=2-5"}})
+		if rec.Code != http.StatusPartialContent {
+			t.Fatalf("expected 206, got %d", rec.Code)
+		}
+		if rec.Body.String() != "2345" {
+			t.Errorf("unexpected body %q", rec.Body.String())
+		}
+		if rec.Header().Get("Content-Range") != "bytes 2-5/10" {
+			t.Errorf("unexpected Content-Range %q", rec.Header().Get("Content-Range"))
+		}
+
+		rec = serve(t, http.Header{"Range": {"bytes=20-"}})
+		if rec.Code != http.StatusRequestedRangeNotSatisfiable {
+			t.Errorf("expected 416, got %d", rec.Code)
+		}
+
+		// 内容が変わっている場合は全体を返す
+		rec = serve(t, http.Header{"Range": {"bytes=2-5"}, "If-Range": {`"other"`}})
+		if rec.Code != http.StatusOK {
+			t.Errorf("expected 200 for mismatched If-Range, got %d", rec.Code)
+		}
+	})
+}
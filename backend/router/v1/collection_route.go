@@ -0,0 +1,63 @@
+package v1
+
+import (
+	"bytes"
+	"embed"
+	"html/template"
+	"log/slog"
+
+	"github.com/Luke256/ducks/router/utils/herror"
+	"github.com/Luke256/ducks/service/location"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+//go:embed templates/collection_route.html
+var collectionRouteFS embed.FS
+
+var collectionRouteTemplate = template.Must(template.ParseFS(collectionRouteFS, "templates/collection_route.html"))
+
+func (h *Handler) GetCollectionRoute(c echo.Context) error {
+	route, err := h.getCollectionRoute(c)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(200, route)
+}
+
+// PrintCollectionRoute 印刷用のチェックリストをHTMLで返します
+func (h *Handler) PrintCollectionRoute(c echo.Context) error {
+	route, err := h.getCollectionRoute(c)
+	if err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+	if err := collectionRouteTemplate.Execute(&buf, route); err != nil {
+		slog.Error("failed to render collection route:", slog.String("error", err.Error()))
+		return herror.InternalServerError("Failed to render collection route")
+	}
+
+	return c.HTMLBlob(200, buf.Bytes())
+}
+
+func (h *Handler) getCollectionRoute(c echo.Context) (location.CollectionRoute, error) {
+	fesID, err := uuid.Parse(c.Param("festival_id"))
+	if err != nil {
+		return location.CollectionRoute{}, herror.NotFound("Festival not found")
+	}
+
+	route, err := h.locationManager.CollectionRoute(fesID)
+	if err != nil {
+		switch err {
+		case location.ErrNotFound:
+			return location.CollectionRoute{}, herror.NotFound("Festival not found")
+		default:
+			slog.Error("failed to get collection route:", slog.String("error", err.Error()))
+			return location.CollectionRoute{}, herror.InternalServerError("Failed to get collection route")
+		}
+	}
+
+	return route, nil
+}
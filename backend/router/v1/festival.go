@@ -1,6 +1,8 @@
 package v1
 
 import (
+	"time"
+
 	"github.com/Luke256/ducks/router/utils/herror"
 	"github.com/Luke256/ducks/service/festival"
 	"github.com/go-ozzo/ozzo-validation/v4"
@@ -9,8 +11,10 @@ import (
 )
 
 type CreateFestivalRequest struct {
-	Name        string `json:"name"`
-	Description string `json:"description"`
+	Name        string     `json:"name"`
+	Description string     `json:"description"`
+	StartDate   *time.Time `json:"start_date"`
+	EndDate     *time.Time `json:"end_date"`
 }
 
 func (r CreateFestivalRequest) Validate() error {
@@ -21,12 +25,50 @@ func (r CreateFestivalRequest) Validate() error {
 }
 
 type EditFestivalRequest struct {
+	ID          string     `param:"id"`
+	Name        string     `json:"name"`
+	Description string     `json:"description"`
+	StartDate   *time.Time `json:"start_date"`
+	EndDate     *time.Time `json:"end_date"`
+}
+
+func (r EditFestivalRequest) Validate() error {
+	return validation.ValidateStruct(&r,
+		validation.Field(&r.ID, validation.Required),
+		validation.Field(&r.Name, validation.Required),
+		validation.Field(&r.Description),
+	)
+}
+
+type UpdateFestivalStatusRequest struct {
+	ID     string `param:"id"`
+	Status string `json:"status"`
+}
+
+func (r UpdateFestivalStatusRequest) Validate() error {
+	return validation.ValidateStruct(&r,
+		validation.Field(&r.ID, validation.Required),
+		validation.Field(&r.Status,
+			validation.Required,
+			validation.In(
+				festival.FestivalStatusPlanning,
+				festival.FestivalStatusOpen,
+				festival.FestivalStatusClosed,
+				festival.FestivalStatusArchived,
+			),
+		),
+	)
+}
+
+type CloneFestivalRequest struct {
 	ID          string `param:"id"`
 	Name        string `json:"name"`
 	Description string `json:"description"`
+	PriceDelta  int    `json:"price_delta"`
+	WithPosters bool   `json:"with_posters"`
 }
 
-func (r EditFestivalRequest) Validate() error {
+func (r CloneFestivalRequest) Validate() error {
 	return validation.ValidateStruct(&r,
 		validation.Field(&r.ID, validation.Required),
 		validation.Field(&r.Name, validation.Required),
@@ -43,12 +85,17 @@ func (h *Handler) CreateFestival(c echo.Context) error {
 		return herror.BadRequest(err.Error())
 	}
 
-	festival, err := h.festivalManager.Create(req.Name, req.Description)
+	fest, err := h.festivalManager.Create(req.Name, req.Description, req.StartDate, req.EndDate)
 	if err != nil {
-		return herror.InternalServerError("failed to create festival")
+		switch err {
+		case festival.ErrInvalidPeriod:
+			return herror.BadRequest("end date must not be before start date")
+		default:
+			return herror.InternalServerError("failed to create festival")
+		}
 	}
 
-	return c.JSON(201, festival)
+	return c.JSON(201, fest)
 }
 
 func (h *Handler) ListFestivals(c echo.Context) error {
@@ -94,11 +141,13 @@ func (h *Handler) EditFestival(c echo.Context) error {
 		return herror.NotFound("festival not found")
 	}
 
-	err = h.festivalManager.Edit(id, req.Name, req.Description)
+	err = h.festivalManager.Edit(id, req.Name, req.Description, req.StartDate, req.EndDate)
 	if err != nil {
 		switch err {
 		case festival.ErrNotFound:
 			return herror.NotFound("festival not found")
+		case festival.ErrInvalidPeriod:
+			return herror.BadRequest("end date must not be before start date")
 		default:
 			return herror.InternalServerError("failed to edit festival")
 		}
@@ -112,6 +161,58 @@ func (h *Handler) EditFestival(c echo.Context) error {
 	return c.JSON(200, fest)
 }
 
+func (h *Handler) UpdateFestivalStatus(c echo.Context) error {
+	var req UpdateFestivalStatusRequest
+	if err := c.Bind(&req); err != nil {
+		return herror.BadRequest("invalid request body")
+	}
+	if err := req.Validate(); err != nil {
+		return herror.BadRequest(err.Error())
+	}
+	id, err := uuid.Parse(req.ID)
+	if err != nil {
+		return herror.NotFound("festival not found")
+	}
+
+	err = h.festivalManager.ChangeStatus(id, req.Status)
+	if err != nil {
+		switch err {
+		case festival.ErrNotFound:
+			return herror.NotFound("festival not found")
+		default:
+			return herror.InternalServerError("failed to update festival status")
+		}
+	}
+
+	return c.NoContent(204)
+}
+
+func (h *Handler) CloneFestival(c echo.Context) error {
+	var req CloneFestivalRequest
+	if err := c.Bind(&req); err != nil {
+		return herror.BadRequest("invalid request body")
+	}
+	if err := req.Validate(); err != nil {
+		return herror.BadRequest(err.Error())
+	}
+	id, err := uuid.Parse(req.ID)
+	if err != nil {
+		return herror.NotFound("festival not found")
+	}
+
+	fest, err := h.festivalManager.Clone(id, req.Name, req.Description, req.PriceDelta, req.WithPosters)
+	if err != nil {
+		switch err {
+		case festival.ErrNotFound:
+			return herror.NotFound("festival not found")
+		default:
+			return herror.InternalServerError("failed to clone festival")
+		}
+	}
+
+	return c.JSON(201, fest)
+}
+
 func (h *Handler) DeleteFestival(c echo.Context) error {
 	idStr := c.Param("id")
 
@@ -132,3 +233,27 @@ func (h *Handler) DeleteFestival(c echo.Context) error {
 
 	return c.NoContent(204)
 }
+
+func (h *Handler) RestoreFestival(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return herror.NotFound("festival not found")
+	}
+
+	err = h.festivalManager.Restore(id)
+	if err != nil {
+		switch err {
+		case festival.ErrNotFound:
+			return herror.NotFound("festival not found")
+		default:
+			return herror.InternalServerError("failed to restore festival")
+		}
+	}
+
+	fest, err := h.festivalManager.Get(id)
+	if err != nil {
+		return herror.InternalServerError("failed to get festival after restore")
+	}
+
+	return c.JSON(200, fest)
+}
@@ -1,6 +1,8 @@
 package v1
 
 import (
+	"regexp"
+
 	"github.com/Luke256/ducks/router/utils/herror"
 	"github.com/Luke256/ducks/service/festival"
 	festivalstock "github.com/Luke256/ducks/service/festival_stock"
@@ -25,9 +27,12 @@ func (r RegisterFestivalStockRequest) Validate() error {
 	)
 }
 
+var colorCodeRegexp = regexp.MustCompile(`^#[0-9a-fA-F]{6}$`)
+
 type QueryFestivalStocksRequest struct {
-	FestivalID string `param:"festival_id"`
-	Category   string `query:"category"`
+	FestivalID  string `param:"festival_id"`
+	Category    string `query:"category"`
+	VisibleOnly bool   `query:"visible_only"`
 }
 
 func (r QueryFestivalStocksRequest) Validate() error {
@@ -48,6 +53,31 @@ func (r UpdateFestivalStockRequest) Validate() error {
 	)
 }
 
+type UpdateFestivalStockDisplayRequest struct {
+	ID     string `param:"id"`
+	Hidden bool   `json:"hidden"`
+	Color  string `json:"color"`
+}
+
+func (r UpdateFestivalStockDisplayRequest) Validate() error {
+	return validation.ValidateStruct(&r,
+		validation.Field(&r.ID, validation.Required),
+		validation.Field(&r.Color, validation.Match(colorCodeRegexp)),
+	)
+}
+
+type ReorderFestivalStocksRequest struct {
+	FestivalID string   `param:"festival_id"`
+	StockIDs   []string `json:"stock_ids"`
+}
+
+func (r ReorderFestivalStocksRequest) Validate() error {
+	return validation.ValidateStruct(&r,
+		validation.Field(&r.FestivalID, validation.Required),
+		validation.Field(&r.StockIDs, validation.Required),
+	)
+}
+
 func (h *Handler) RegisterFestivalStock(c echo.Context) error {
 	var req RegisterFestivalStockRequest
 	if err := c.Bind(&req); err != nil {
@@ -67,13 +97,15 @@ func (h *Handler) RegisterFestivalStock(c echo.Context) error {
 		return herror.NotFound("Stock item not found")
 	}
 
-	festivalStock, err := h.festivalStockManager.Create(fesID, itemID, req.Price, req.Description)
+	festivalStock, err := h.festivalStockManager.Create(fesID, itemID, req.Price, req.Description, h.isAdminOverride(c))
 	if err != nil {
 		switch err {
 		case festival.ErrNotFound:
 			return herror.NotFound("Festival not found")
 		case stockitem.ErrNotFound:
 			return herror.NotFound("Stock item not found")
+		case festival.ErrLocked:
+			return herror.Forbidden("Festival is closed")
 		default:
 			return herror.InternalServerError("Failed to create festival stock")
 		}
@@ -115,7 +147,7 @@ func (h *Handler) QueryFestivalStocks(c echo.Context) error {
 		return herror.NotFound("Festival not found")
 	}
 
-	festivalStocks, err := h.festivalStockManager.Query(fesID, req.Category)
+	festivalStocks, err := h.festivalStockManager.Query(fesID, req.Category, req.VisibleOnly)
 	if err != nil {
 		return herror.InternalServerError("Failed to query festival stocks")
 	}
@@ -139,11 +171,13 @@ func (h *Handler) UpdateFestivalStock(c echo.Context) error {
 		return herror.NotFound("Festival stock not found")
 	}
 
-	err = h.festivalStockManager.Update(id, req.Description)
+	err = h.festivalStockManager.Update(id, req.Description, h.isAdminOverride(c))
 	if err != nil {
 		switch err {
 		case festivalstock.ErrNotFound:
 			return herror.NotFound("Festival stock not found")
+		case festival.ErrLocked:
+			return herror.Forbidden("Festival is closed")
 		default:
 			return herror.InternalServerError("Failed to update festival stock price")
 		}
@@ -152,17 +186,88 @@ func (h *Handler) UpdateFestivalStock(c echo.Context) error {
 	return c.NoContent(204)
 }
 
+func (h *Handler) UpdateFestivalStockDisplay(c echo.Context) error {
+	var req UpdateFestivalStockDisplayRequest
+	if err := c.Bind(&req); err != nil {
+		return herror.BadRequest("Invalid request body")
+	}
+	if err := req.Validate(); err != nil {
+		return herror.BadRequest("Validation failed: " + err.Error())
+	}
+
+	id, err := uuid.Parse(req.ID)
+	if err != nil {
+		return herror.NotFound("Festival stock not found")
+	}
+
+	err = h.festivalStockManager.UpdateDisplay(id, req.Hidden, req.Color, h.isAdminOverride(c))
+	if err != nil {
+		switch err {
+		case festivalstock.ErrNotFound:
+			return herror.NotFound("Festival stock not found")
+		case festival.ErrLocked:
+			return herror.Forbidden("Festival is closed")
+		default:
+			return herror.InternalServerError("Failed to update festival stock display")
+		}
+	}
+
+	return c.NoContent(204)
+}
+
+func (h *Handler) ReorderFestivalStocks(c echo.Context) error {
+	var req ReorderFestivalStocksRequest
+	if err := c.Bind(&req); err != nil {
+		return herror.BadRequest("Invalid request body")
+	}
+	if err := req.Validate(); err != nil {
+		return herror.BadRequest("Validation failed: " + err.Error())
+	}
+
+	fesID, err := uuid.Parse(req.FestivalID)
+	if err != nil {
+		return herror.NotFound("Festival not found")
+	}
+
+	ids := make([]uuid.UUID, len(req.StockIDs))
+	for i, s := range req.StockIDs {
+		id, err := uuid.Parse(s)
+		if err != nil {
+			return herror.NotFound("Festival stock not found")
+		}
+		ids[i] = id
+	}
+
+	err = h.festivalStockManager.Reorder(fesID, ids, h.isAdminOverride(c))
+	if err != nil {
+		switch err {
+		case festival.ErrNotFound:
+			return herror.NotFound("Festival not found")
+		case festivalstock.ErrNotFound:
+			return herror.NotFound("Festival stock not found")
+		case festival.ErrLocked:
+			return herror.Forbidden("Festival is closed")
+		default:
+			return herror.InternalServerError("Failed to reorder festival stocks")
+		}
+	}
+
+	return c.NoContent(204)
+}
+
 func (h *Handler) DeleteFestivalStock(c echo.Context) error {
 	id, err := uuid.Parse(c.Param("id"))
 	if err != nil {
 		return herror.NotFound("Festival stock not found")
 	}
 
-	err = h.festivalStockManager.Delete(id)
+	err = h.festivalStockManager.Delete(id, h.isAdminOverride(c))
 	if err != nil {
 		switch err {
 		case festivalstock.ErrNotFound:
 			return herror.NotFound("Festival stock not found")
+		case festival.ErrLocked:
+			return herror.Forbidden("Festival is closed")
 		default:
 			return herror.InternalServerError("Failed to delete festival stock")
 		}
@@ -170,3 +275,27 @@ func (h *Handler) DeleteFestivalStock(c echo.Context) error {
 
 	return c.NoContent(204)
 }
+
+func (h *Handler) RestoreFestivalStock(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return herror.NotFound("Festival stock not found")
+	}
+
+	err = h.festivalStockManager.Restore(id)
+	if err != nil {
+		switch err {
+		case festivalstock.ErrNotFound:
+			return herror.NotFound("Festival stock not found")
+		default:
+			return herror.InternalServerError("Failed to restore festival stock")
+		}
+	}
+
+	festivalStock, err := h.festivalStockManager.Get(id)
+	if err != nil {
+		return herror.InternalServerError("Failed to get festival stock")
+	}
+
+	return c.JSON(200, festivalStock)
+}
@@ -307,4 +307,116 @@ func TestDeleteFestivalStock(t *testing.T) {
 			Expect().
 			Status(404)
 	})
-}
\ No newline at end of file
+}
+
+func TestUpdateFestivalStockDisplay(t *testing.T) {
+	env := setup(t, common)
+	e := env.R(t)
+
+	fes := env.mustCreateFestival(t, "Test Festival", "A festival for testing")
+	item := env.mustCreateStockItem(t, "Test Stock Item", "A stock item for testing", "Category1")
+	fesStock := env.mustCreateFestivalStock(t, fes.ID, item.ID, 2000, "Stock Description")
+
+	t.Run("Update Festival Stock Display", func(t *testing.T) {
+		e.PATCH("/api/stocks/{festival_stock_id}/display", fesStock.ID).
+			WithJSON(map[string]any{
+				"hidden": true,
+				"color":  "#00ff00",
+			}).
+			Expect().
+			Status(204)
+
+		res := e.GET("/api/stocks/{festival_stock_id}", fesStock.ID).
+			Expect().
+			Status(200).
+			JSON().
+			Object()
+
+		res.Value("hidden").IsEqual(true)
+		res.Value("color").IsEqual("#00ff00")
+
+		e.GET("/api/festivals/{festival_id}/stocks", fes.ID).
+			WithQuery("visible_only", true).
+			Expect().
+			Status(200).
+			JSON().
+			Object().
+			Value("stocks").Array().Length().IsEqual(0)
+	})
+
+	t.Run("Update Festival Stock Display - Invalid Color", func(t *testing.T) {
+		e.PATCH("/api/stocks/{festival_stock_id}/display", fesStock.ID).
+			WithJSON(map[string]any{
+				"hidden": false,
+				"color":  "green",
+			}).
+			Expect().
+			Status(400)
+	})
+
+	t.Run("Update Festival Stock Display - Not Found", func(t *testing.T) {
+		e.PATCH("/api/stocks/{festival_stock_id}/display", uuid.New()).
+			WithJSON(map[string]any{
+				"hidden": false,
+			}).
+			Expect().
+			Status(404)
+	})
+}
+
+func TestReorderFestivalStocks(t *testing.T) {
+	env := setup(t, common)
+	e := env.R(t)
+
+	fes := env.mustCreateFestival(t, "Test Festival", "A festival for testing")
+	item := env.mustCreateStockItem(t, "Test Stock Item", "A stock item for testing", "Category1")
+	st1 := env.mustCreateFestivalStock(t, fes.ID, item.ID, 100, "Stock 1")
+	st2 := env.mustCreateFestivalStock(t, fes.ID, item.ID, 200, "Stock 2")
+
+	t.Run("Reorder Festival Stocks", func(t *testing.T) {
+		e.PUT("/api/festivals/{festival_id}/stocks/order", fes.ID).
+			WithJSON(map[string]any{
+				"stock_ids": []string{st2.ID.String(), st1.ID.String()},
+			}).
+			Expect().
+			Status(204)
+
+		stocks := e.GET("/api/festivals/{festival_id}/stocks", fes.ID).
+			Expect().
+			Status(200).
+			JSON().
+			Object().
+			Value("stocks").Array()
+
+		stocks.Length().IsEqual(2)
+		stocks.Value(0).Object().Value("id").IsEqual(st2.ID.String())
+		stocks.Value(1).Object().Value("id").IsEqual(st1.ID.String())
+	})
+
+	t.Run("Reorder Festival Stocks - Unknown Stock", func(t *testing.T) {
+		e.PUT("/api/festivals/{festival_id}/stocks/order", fes.ID).
+			WithJSON(map[string]any{
+				"stock_ids": []string{uuid.New().String()},
+			}).
+			Expect().
+			Status(404)
+	})
+
+	t.Run("Reorder Festival Stocks - Festival Not Found", func(t *testing.T) {
+		e.PUT("/api/festivals/{festival_id}/stocks/order", uuid.New()).
+			WithJSON(map[string]any{
+				"stock_ids": []string{st1.ID.String()},
+			}).
+			Expect().
+			Status(404)
+	})
+
+	t.Run("Reorder Festival Stocks - Empty List", func(t *testing.T) {
+		e.PUT("/api/festivals/{festival_id}/stocks/order", fes.ID).
+			WithJSON(map[string]any{
+				"stock_ids": []string{},
+			}).
+			Expect().
+			Status(400)
+	})
+}
@@ -44,11 +44,17 @@ func TestListFestivals(t *testing.T) {
 			"id": fest1.ID.String(),
 			"name": fest1.Name,
 			"description": fest1.Description,
+			"start_date": nil,
+			"end_date": nil,
+			"status": "planning",
 		},
 		map[string]any{
 			"id": fest2.ID.String(),
 			"name": fest2.Name,
 			"description": fest2.Description,
+			"start_date": nil,
+			"end_date": nil,
+			"status": "planning",
 		},
 	)
 }
@@ -126,4 +132,185 @@ func TestDeleteFestival(t *testing.T) {
 			Expect().
 			Status(404)
 	})
-}
\ No newline at end of file
+}
+
+func TestDeleteFestivalCascade(t *testing.T) {
+	env := setup(t, s1)
+	e := env.R(t)
+	fest := env.mustCreateFestival(t, "Cascade Festival", "Description")
+	item := env.mustCreateStockItem(t, "Cascade Item", "Item Description", "Category")
+	stock := env.mustCreateFestivalStock(t, fest.ID, item.ID, 100, "Stock Description")
+	poster := env.mustCreatePoster(t, fest.ID, "Cascade Poster", "Poster Description")
+
+	sale := map[string]any{
+		"items": []map[string]any{
+			{"stock_id": stock.ID.String(), "quantity": 1},
+		},
+	}
+
+	e.DELETE(fmt.Sprintf("/api/festivals/%s", fest.ID.String())).
+		Expect().
+		Status(204)
+
+	t.Run("children are deleted with festival", func(t *testing.T) {
+		e.GET(fmt.Sprintf("/api/posters/%s", poster.ID.String())).
+			Expect().
+			Status(404)
+		e.GET(fmt.Sprintf("/api/festivals/%s/posters", fest.ID.String())).
+			Expect().
+			Status(200).
+			JSON().
+			Object().
+			Value("total").IsEqual(0)
+		e.POST("/api/sales").
+			WithJSON(sale).
+			Expect().
+			Status(404)
+	})
+
+	t.Run("children are restored with festival", func(t *testing.T) {
+		e.POST(fmt.Sprintf("/api/festivals/%s/restore", fest.ID.String())).
+			Expect().
+			Status(200)
+		e.GET(fmt.Sprintf("/api/posters/%s", poster.ID.String())).
+			Expect().
+			Status(200)
+		e.POST("/api/sales").
+			WithJSON(sale).
+			Expect().
+			Status(201)
+	})
+}
+
+func TestCloneFestival(t *testing.T) {
+	env := setup(t, common)
+	e := env.R(t)
+	fest := env.mustCreateFestival(t, "Festival 2025", "Description")
+	item := env.mustCreateStockItem(t, "Item", "Item Description", "Category")
+	env.mustCreateFestivalStock(t, fest.ID, item.ID, 300, "Stock Description")
+	env.mustCreatePoster(t, fest.ID, "Poster", "Poster Description")
+
+	t.Run("clone festival", func(t *testing.T) {
+		resp := e.POST(fmt.Sprintf("/api/festivals/%s/clone", fest.ID.String())).
+			WithJSON(map[string]any{
+				"name":         "Festival 2026",
+				"description":  "Cloned",
+				"price_delta":  50,
+				"with_posters": true,
+			}).
+			Expect().
+			Status(201).
+			JSON().
+			Object()
+		resp.Value("name").IsEqual("Festival 2026")
+		resp.Value("description").IsEqual("Cloned")
+
+		clonedID := resp.Value("id").String().Raw()
+		stocks := e.GET(fmt.Sprintf("/api/festivals/%s/stocks", clonedID)).
+			Expect().
+			Status(200).
+			JSON().
+			Object().
+			Value("stocks").Array()
+		stocks.Length().IsEqual(1)
+		stocks.Value(0).Object().Value("price").IsEqual(350)
+
+		posters := e.GET(fmt.Sprintf("/api/festivals/%s/posters", clonedID)).
+			Expect().
+			Status(200).
+			JSON().
+			Object().
+			Value("posters").Array()
+		posters.Length().IsEqual(1)
+		posters.Value(0).Object().Value("status").IsEqual("submitted")
+	})
+
+	t.Run("clone non-existing festival", func(t *testing.T) {
+		e.POST("/api/festivals/00000000-0000-0000-0000-000000000000/clone").
+			WithJSON(map[string]any{
+				"name": "Name",
+			}).
+			Expect().
+			Status(404)
+	})
+
+	t.Run("clone without name", func(t *testing.T) {
+		e.POST(fmt.Sprintf("/api/festivals/%s/clone", fest.ID.String())).
+			WithJSON(map[string]any{
+				"description": "Description",
+			}).
+			Expect().
+			Status(400)
+	})
+}
+
+func TestUpdateFestivalStatus(t *testing.T) {
+	env := setup(t, common)
+	e := env.R(t)
+	fest := env.mustCreateFestival(t, "Festival", "Description")
+
+	t.Run("update festival status", func(t *testing.T) {
+		e.PATCH(fmt.Sprintf("/api/festivals/%s/status", fest.ID.String())).
+			WithJSON(map[string]any{
+				"status": "open",
+			}).
+			Expect().
+			Status(204)
+		e.GET(fmt.Sprintf("/api/festivals/%s", fest.ID.String())).
+			Expect().
+			Status(200).
+			JSON().
+			Object().
+			Value("status").IsEqual("open")
+	})
+
+	t.Run("invalid status", func(t *testing.T) {
+		e.PATCH(fmt.Sprintf("/api/festivals/%s/status", fest.ID.String())).
+			WithJSON(map[string]any{
+				"status": "finished",
+			}).
+			Expect().
+			Status(400)
+	})
+
+	t.Run("non-existing festival", func(t *testing.T) {
+		e.PATCH("/api/festivals/00000000-0000-0000-0000-000000000000/status").
+			WithJSON(map[string]any{
+				"status": "open",
+			}).
+			Expect().
+			Status(404)
+	})
+}
+
+func TestFestivalPeriod(t *testing.T) {
+	env := setup(t, common)
+	e := env.R(t)
+
+	t.Run("create festival with period", func(t *testing.T) {
+		resp := e.POST("/api/festivals").
+			WithJSON(map[string]any{
+				"name":       "Festival with Period",
+				"start_date": "2026-11-01T00:00:00Z",
+				"end_date":   "2026-11-03T00:00:00Z",
+			}).
+			Expect().
+			Status(201).
+			JSON().
+			Object()
+		resp.Value("start_date").IsEqual("2026-11-01T00:00:00Z")
+		resp.Value("end_date").IsEqual("2026-11-03T00:00:00Z")
+		resp.Value("status").IsEqual("planning")
+	})
+
+	t.Run("end date before start date", func(t *testing.T) {
+		e.POST("/api/festivals").
+			WithJSON(map[string]any{
+				"name":       "Invalid Period",
+				"start_date": "2026-11-03T00:00:00Z",
+				"end_date":   "2026-11-01T00:00:00Z",
+			}).
+			Expect().
+			Status(400)
+	})
+}
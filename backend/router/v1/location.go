@@ -0,0 +1,304 @@
+package v1
+
+import (
+	"log/slog"
+
+	"github.com/Luke256/ducks/router/utils/herror"
+	"github.com/Luke256/ducks/service/location"
+	"github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+type LocationRequest struct {
+	Building  string   `json:"building"`
+	Floor     string   `json:"floor"`
+	BoardName string   `json:"board_name"`
+	Latitude  *float64 `json:"latitude"`
+	Longitude *float64 `json:"longitude"`
+}
+
+func (r LocationRequest) Validate() error {
+	return validation.ValidateStruct(&r,
+		validation.Field(&r.Building, validation.Required, validation.Length(1, 100)),
+		validation.Field(&r.Floor, validation.Length(0, 32)),
+		validation.Field(&r.BoardName, validation.Required, validation.Length(1, 100)),
+		// 座標は緯度・経度の両方を指定するか、両方とも省略する
+		validation.Field(&r.Latitude, validation.When(r.Longitude != nil, validation.NotNil), validation.Min(-90.0), validation.Max(90.0)),
+		validation.Field(&r.Longitude, validation.When(r.Latitude != nil, validation.NotNil), validation.Min(-180.0), validation.Max(180.0)),
+	)
+}
+
+type ReorderLocationsRequest struct {
+	LocationIDs []string `json:"location_ids"`
+}
+
+func (r ReorderLocationsRequest) Validate() error {
+	return validation.ValidateStruct(&r,
+		validation.Field(&r.LocationIDs, validation.Required),
+	)
+}
+
+type PlacePosterRequest struct {
+	LocationID string `json:"location_id"`
+}
+
+func (r PlacePosterRequest) Validate() error {
+	return validation.ValidateStruct(&r,
+		validation.Field(&r.LocationID, validation.Required),
+	)
+}
+
+type UpdatePlacementStatusRequest struct {
+	Status string `json:"status"`
+}
+
+func (r UpdatePlacementStatusRequest) Validate() error {
+	return validation.ValidateStruct(&r,
+		validation.Field(&r.Status,
+			validation.Required,
+			validation.In(
+				location.PlacementStatusUncollected,
+				location.PlacementStatusCollected,
+				location.PlacementStatusLost,
+			),
+		),
+	)
+}
+
+func (h *Handler) CreateLocation(c echo.Context) error {
+	var req LocationRequest
+	if err := c.Bind(&req); err != nil {
+		return herror.BadRequest("Invalid request")
+	}
+	if err := req.Validate(); err != nil {
+		return herror.BadRequest("Validation failed: " + err.Error())
+	}
+
+	loc, err := h.locationManager.Create(req.Building, req.Floor, req.BoardName, req.Latitude, req.Longitude)
+	if err != nil {
+		slog.Error("failed to create location:", slog.String("error", err.Error()))
+		return herror.InternalServerError("Failed to create location")
+	}
+
+	return c.JSON(201, loc)
+}
+
+func (h *Handler) ListLocations(c echo.Context) error {
+	locations, err := h.locationManager.List()
+	if err != nil {
+		slog.Error("failed to list locations:", slog.String("error", err.Error()))
+		return herror.InternalServerError("Failed to list locations")
+	}
+
+	return c.JSON(200, map[string]any{
+		"locations": locations,
+	})
+}
+
+func (h *Handler) GetLocation(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return herror.NotFound("Location not found")
+	}
+
+	loc, err := h.locationManager.Get(id)
+	if err != nil {
+		switch err {
+		case location.ErrNotFound:
+			return herror.NotFound("Location not found")
+		default:
+			slog.Error("failed to get location:", slog.String("error", err.Error()))
+			return herror.InternalServerError("Failed to get location")
+		}
+	}
+
+	return c.JSON(200, loc)
+}
+
+func (h *Handler) EditLocation(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return herror.NotFound("Location not found")
+	}
+
+	var req LocationRequest
+	if err := c.Bind(&req); err != nil {
+		return herror.BadRequest("Invalid request")
+	}
+	if err := req.Validate(); err != nil {
+		return herror.BadRequest("Validation failed: " + err.Error())
+	}
+
+	err = h.locationManager.Edit(id, req.Building, req.Floor, req.BoardName, req.Latitude, req.Longitude)
+	if err != nil {
+		switch err {
+		case location.ErrNotFound:
+			return herror.NotFound("Location not found")
+		default:
+			slog.Error("failed to edit location:", slog.String("error", err.Error()))
+			return herror.InternalServerError("Failed to edit location")
+		}
+	}
+
+	return c.NoContent(204)
+}
+
+func (h *Handler) DeleteLocation(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return herror.NotFound("Location not found")
+	}
+
+	err = h.locationManager.Delete(id)
+	if err != nil {
+		switch err {
+		case location.ErrNotFound:
+			return herror.NotFound("Location not found")
+		case location.ErrInUse:
+			return herror.Conflict("Location has posters placed")
+		default:
+			slog.Error("failed to delete location:", slog.String("error", err.Error()))
+			return herror.InternalServerError("Failed to delete location")
+		}
+	}
+
+	return c.NoContent(204)
+}
+
+func (h *Handler) ReorderLocations(c echo.Context) error {
+	var req ReorderLocationsRequest
+	if err := c.Bind(&req); err != nil {
+		return herror.BadRequest("Invalid request")
+	}
+	if err := req.Validate(); err != nil {
+		return herror.BadRequest("Validation failed: " + err.Error())
+	}
+
+	ids := make([]uuid.UUID, len(req.LocationIDs))
+	for i, s := range req.LocationIDs {
+		id, err := uuid.Parse(s)
+		if err != nil {
+			return herror.NotFound("Location not found")
+		}
+		ids[i] = id
+	}
+
+	err := h.locationManager.Reorder(ids)
+	if err != nil {
+		switch err {
+		case location.ErrNotFound:
+			return herror.NotFound("Location not found")
+		default:
+			slog.Error("failed to reorder locations:", slog.String("error", err.Error()))
+			return herror.InternalServerError("Failed to reorder locations")
+		}
+	}
+
+	return c.NoContent(204)
+}
+
+func (h *Handler) PlacePoster(c echo.Context) error {
+	posterID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return herror.NotFound("Poster not found")
+	}
+
+	var req PlacePosterRequest
+	if err := c.Bind(&req); err != nil {
+		return herror.BadRequest("Invalid request")
+	}
+	if err := req.Validate(); err != nil {
+		return herror.BadRequest("Validation failed: " + err.Error())
+	}
+
+	locationID, err := uuid.Parse(req.LocationID)
+	if err != nil {
+		return herror.NotFound("Location not found")
+	}
+
+	placement, err := h.locationManager.Place(posterID, locationID)
+	if err != nil {
+		switch err {
+		case location.ErrNotFound:
+			return herror.NotFound("Poster or location not found")
+		case location.ErrAlreadyExists:
+			return herror.Conflict("Poster is already placed at the location")
+		default:
+			slog.Error("failed to place poster:", slog.String("error", err.Error()))
+			return herror.InternalServerError("Failed to place poster")
+		}
+	}
+
+	return c.JSON(201, placement)
+}
+
+func (h *Handler) ListPosterPlacements(c echo.Context) error {
+	posterID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return herror.NotFound("Poster not found")
+	}
+
+	placements, err := h.locationManager.ListPlacements(posterID)
+	if err != nil {
+		switch err {
+		case location.ErrNotFound:
+			return herror.NotFound("Poster not found")
+		default:
+			slog.Error("failed to list poster placements:", slog.String("error", err.Error()))
+			return herror.InternalServerError("Failed to list poster placements")
+		}
+	}
+
+	return c.JSON(200, map[string]any{
+		"placements": placements,
+	})
+}
+
+func (h *Handler) UpdatePlacementStatus(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return herror.NotFound("Placement not found")
+	}
+
+	var req UpdatePlacementStatusRequest
+	if err := c.Bind(&req); err != nil {
+		return herror.BadRequest("Invalid request")
+	}
+	if err := req.Validate(); err != nil {
+		return herror.BadRequest("Validation failed: " + err.Error())
+	}
+
+	err = h.locationManager.ChangePlacementStatus(id, req.Status)
+	if err != nil {
+		switch err {
+		case location.ErrNotFound:
+			return herror.NotFound("Placement not found")
+		default:
+			slog.Error("failed to update placement status:", slog.String("error", err.Error()))
+			return herror.InternalServerError("Failed to update placement status")
+		}
+	}
+
+	return c.NoContent(204)
+}
+
+func (h *Handler) DeletePlacement(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return herror.NotFound("Placement not found")
+	}
+
+	err = h.locationManager.RemovePlacement(id)
+	if err != nil {
+		switch err {
+		case location.ErrNotFound:
+			return herror.NotFound("Placement not found")
+		default:
+			slog.Error("failed to delete placement:", slog.String("error", err.Error()))
+			return herror.InternalServerError("Failed to delete placement")
+		}
+	}
+
+	return c.NoContent(204)
+}
@@ -0,0 +1,268 @@
+package v1
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestCreateLocation(t *testing.T) {
+	env := setup(t, common)
+	e := env.R(t)
+
+	t.Run("create location", func(t *testing.T) {
+		resp := e.POST("/api/locations").
+			WithJSON(map[string]any{
+				"building":   "Building A",
+				"floor":      "1F",
+				"board_name": "Entrance Board",
+				"latitude":   35.6051,
+				"longitude":  139.6838,
+			}).
+			Expect().
+			Status(201).
+			JSON().
+			Object()
+		resp.Value("id").NotNull()
+		resp.Value("building").IsEqual("Building A")
+		resp.Value("floor").IsEqual("1F")
+		resp.Value("board_name").IsEqual("Entrance Board")
+		resp.Value("latitude").IsEqual(35.6051)
+	})
+
+	t.Run("coordinates must be given together", func(t *testing.T) {
+		e.POST("/api/locations").
+			WithJSON(map[string]any{
+				"building":   "Building A",
+				"board_name": "Board",
+				"latitude":   35.6051,
+			}).
+			Expect().
+			Status(400)
+	})
+
+	t.Run("missing board name", func(t *testing.T) {
+		e.POST("/api/locations").
+			WithJSON(map[string]any{
+				"building": "Building A",
+			}).
+			Expect().
+			Status(400)
+	})
+}
+
+func TestEditLocation(t *testing.T) {
+	env := setup(t, common)
+	e := env.R(t)
+	loc := env.mustCreateLocation(t, "Old Building", "1F", "Old Board")
+
+	t.Run("edit existing location", func(t *testing.T) {
+		e.PUT(fmt.Sprintf("/api/locations/%s", loc.ID.String())).
+			WithJSON(map[string]any{
+				"building":   "New Building",
+				"floor":      "2F",
+				"board_name": "New Board",
+			}).
+			Expect().
+			Status(204)
+
+		resp := e.GET(fmt.Sprintf("/api/locations/%s", loc.ID.String())).
+			Expect().
+			Status(200).
+			JSON().
+			Object()
+		resp.Value("building").IsEqual("New Building")
+		resp.Value("board_name").IsEqual("New Board")
+	})
+
+	t.Run("edit non-existing location", func(t *testing.T) {
+		e.PUT("/api/locations/00000000-0000-0000-0000-000000000000").
+			WithJSON(map[string]any{
+				"building":   "Building",
+				"board_name": "Board",
+			}).
+			Expect().
+			Status(404)
+	})
+}
+
+func TestPosterPlacement(t *testing.T) {
+	env := setup(t, common)
+	e := env.R(t)
+	fes := env.mustCreateFestival(t, "Placement Fest", "Festival for placements")
+	poster := env.mustCreatePoster(t, fes.ID, "Placement Poster", "Poster with placements")
+	loc := env.mustCreateLocation(t, "Building B", "3F", "Hall Board")
+
+	var placementID string
+
+	t.Run("place poster", func(t *testing.T) {
+		resp := e.POST(fmt.Sprintf("/api/posters/%s/placements", poster.ID.String())).
+			WithJSON(map[string]any{
+				"location_id": loc.ID.String(),
+			}).
+			Expect().
+			Status(201).
+			JSON().
+			Object()
+		resp.Value("status").IsEqual("uncollected")
+		resp.Value("location").Object().Value("board_name").IsEqual("Hall Board")
+		placementID = resp.Value("id").String().Raw()
+	})
+
+	t.Run("place poster twice", func(t *testing.T) {
+		e.POST(fmt.Sprintf("/api/posters/%s/placements", poster.ID.String())).
+			WithJSON(map[string]any{
+				"location_id": loc.ID.String(),
+			}).
+			Expect().
+			Status(409)
+	})
+
+	t.Run("place poster at non-existing location", func(t *testing.T) {
+		e.POST(fmt.Sprintf("/api/posters/%s/placements", poster.ID.String())).
+			WithJSON(map[string]any{
+				"location_id": "00000000-0000-0000-0000-000000000000",
+			}).
+			Expect().
+			Status(404)
+	})
+
+	t.Run("update placement status", func(t *testing.T) {
+		e.PATCH(fmt.Sprintf("/api/placements/%s/status", placementID)).
+			WithJSON(map[string]any{
+				"status": "collected",
+			}).
+			Expect().
+			Status(204)
+
+		placements := e.GET(fmt.Sprintf("/api/posters/%s/placements", poster.ID.String())).
+			Expect().
+			Status(200).
+			JSON().
+			Object().
+			Value("placements").Array()
+		placements.Length().IsEqual(1)
+		placements.Value(0).Object().Value("status").IsEqual("collected")
+	})
+
+	t.Run("delete location in use", func(t *testing.T) {
+		e.DELETE(fmt.Sprintf("/api/locations/%s", loc.ID.String())).
+			Expect().
+			Status(409)
+	})
+
+	t.Run("remove placement", func(t *testing.T) {
+		e.DELETE(fmt.Sprintf("/api/placements/%s", placementID)).
+			Expect().
+			Status(204)
+		e.DELETE(fmt.Sprintf("/api/locations/%s", loc.ID.String())).
+			Expect().
+			Status(204)
+	})
+}
+
+func TestCollectionRoute(t *testing.T) {
+	env := setup(t, s2)
+	e := env.R(t)
+	fes := env.mustCreateFestival(t, "Route Fest", "Festival for collection route")
+	poster1 := env.mustCreatePoster(t, fes.ID, "Poster 1", "First poster")
+	poster2 := env.mustCreatePoster(t, fes.ID, "Poster 2", "Second poster")
+	poster3 := env.mustCreatePoster(t, fes.ID, "Poster 3", "Collected poster")
+	unposted := env.mustCreatePoster(t, fes.ID, "Poster 4", "Poster not posted yet")
+	env.mustChangePosterStatus(t, poster1.ID, PosterStatusInReview, PosterStatusApproved, PosterStatusPosted)
+	env.mustChangePosterStatus(t, poster2.ID, PosterStatusInReview, PosterStatusApproved, PosterStatusPosted)
+	env.mustChangePosterStatus(t, poster3.ID, PosterStatusInReview, PosterStatusApproved, PosterStatusPosted)
+	hall := env.mustCreateLocation(t, "Building A", "1F", "Hall Board")
+	stairs := env.mustCreateLocation(t, "Building A", "2F", "Stairs Board")
+	gate := env.mustCreateLocation(t, "Building B", "1F", "Gate Board")
+
+	for _, p := range []struct{ posterID, locationID string }{
+		{poster1.ID.String(), hall.ID.String()},
+		{poster2.ID.String(), gate.ID.String()},
+		{poster2.ID.String(), stairs.ID.String()},
+		{poster3.ID.String(), hall.ID.String()},
+		{unposted.ID.String(), gate.ID.String()},
+	} {
+		e.POST(fmt.Sprintf("/api/posters/%s/placements", p.posterID)).
+			WithJSON(map[string]any{"location_id": p.locationID}).
+			Expect().
+			Status(201)
+	}
+	collected := env.mustListPlacements(t, poster3.ID)
+	e.PATCH(fmt.Sprintf("/api/placements/%s/status", collected[0].ID.String())).
+		WithJSON(map[string]any{"status": "collected"}).
+		Expect().
+		Status(204)
+
+	e.PUT("/api/locations/route").
+		WithJSON(map[string]any{
+			"location_ids": []string{gate.ID.String(), hall.ID.String(), stairs.ID.String()},
+		}).
+		Expect().
+		Status(204)
+
+	t.Run("get collection route", func(t *testing.T) {
+		resp := e.GET(fmt.Sprintf("/api/festivals/%s/collection-route", fes.ID.String())).
+			Expect().
+			Status(200).
+			JSON().
+			Object()
+		resp.Value("festival_name").IsEqual("Route Fest")
+		resp.Value("total").IsEqual(3)
+
+		areas := resp.Value("areas").Array()
+		areas.Length().IsEqual(2)
+		areas.Value(0).Object().Value("building").IsEqual("Building B")
+
+		buildingA := areas.Value(1).Object()
+		buildingA.Value("building").IsEqual("Building A")
+		stops := buildingA.Value("stops").Array()
+		stops.Length().IsEqual(2)
+		stops.Value(0).Object().Value("location").Object().Value("board_name").IsEqual("Hall Board")
+		hallPosters := stops.Value(0).Object().Value("posters").Array()
+		hallPosters.Length().IsEqual(1)
+		hallPosters.Value(0).Object().Value("name").IsEqual("Poster 1")
+		hallPosters.Value(0).Object().Value("image_url").NotNull()
+	})
+
+	t.Run("print collection route", func(t *testing.T) {
+		body := e.GET(fmt.Sprintf("/api/festivals/%s/collection-route/print", fes.ID.String())).
+			Expect().
+			Status(200).
+			ContentType("text/html").
+			Body()
+		body.Contains("Route Fest")
+		body.Contains("Gate Board")
+		body.NotContains("Poster 3")
+		body.NotContains("Poster 4")
+	})
+
+	t.Run("collected poster disappears", func(t *testing.T) {
+		e.PATCH("/api/posters/{posterID}/status", poster1.ID.String()).
+			WithJSON(map[string]any{"status": PosterStatusCollected}).
+			Expect().
+			Status(204)
+
+		resp := e.GET(fmt.Sprintf("/api/festivals/%s/collection-route", fes.ID.String())).
+			Expect().
+			Status(200).
+			JSON().
+			Object()
+		resp.Value("total").IsEqual(2)
+		areas := resp.Value("areas").Array()
+		areas.Length().IsEqual(2)
+		hallStop := areas.Value(1).Object().Value("stops").Array()
+		hallStop.Length().IsEqual(1)
+		hallStop.Value(0).Object().Value("location").Object().Value("board_name").IsEqual("Stairs Board")
+
+		placements := env.mustListPlacements(t, poster1.ID)
+		if len(placements) != 1 || placements[0].Status != "collected" {
+			t.Errorf("expected placement to be collected with the poster, got %+v", placements)
+		}
+	})
+
+	t.Run("non-existing festival", func(t *testing.T) {
+		e.GET("/api/festivals/00000000-0000-0000-0000-000000000000/collection-route").
+			Expect().
+			Status(404)
+	})
+}
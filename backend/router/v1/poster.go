@@ -1,24 +1,35 @@
 package v1
 
 import (
+	"errors"
+	"io"
 	"log/slog"
+	"time"
 
 	"github.com/Luke256/ducks/service/poster"
+	"github.com/Luke256/ducks/utils/compressor"
+	"github.com/Luke256/ducks/utils/storage"
 	"github.com/go-ozzo/ozzo-validation/v4"
 	"github.com/google/uuid"
 	"github.com/labstack/echo/v4"
 )
 
 const (
-	PosterStatusUncollected = "uncollected"
-	PosterStatusCollected   = "collected"
-	PosterStatusLost        = "lost"
+	PosterStatusSubmitted = poster.PosterStatusSubmitted
+	PosterStatusInReview  = poster.PosterStatusInReview
+	PosterStatusApproved  = poster.PosterStatusApproved
+	PosterStatusRejected  = poster.PosterStatusRejected
+	PosterStatusPosted    = poster.PosterStatusPosted
+	PosterStatusCollected = poster.PosterStatusCollected
+	PosterStatusLost      = poster.PosterStatusLost
 )
 
 type RegisterPosterRequest struct {
 	FestivalID  string `form:"festival_id" json:"festival_id"`
 	PosterName  string `form:"name" json:"name"`
 	Description string `form:"description" json:"description"`
+	// DirectUpload trueの場合は画像を送らずに作成し、後から/uploadsでストレージに直接アップロードする
+	DirectUpload bool `form:"direct_upload" json:"direct_upload"`
 }
 
 func (r RegisterPosterRequest) Validate() error {
@@ -29,6 +40,41 @@ func (r RegisterPosterRequest) Validate() error {
 	)
 }
 
+// maxListPostersLimit 一覧で一度に取得できるポスターの最大件数
+// limitを指定せずにoffsetを指定した場合もこの件数まで返します
+const maxListPostersLimit = 200
+
+type ListPostersRequest struct {
+	FestivalID string `param:"festival_id"`
+	Status     string `query:"status"`
+	Name       string `query:"name"`
+	Sort       string `query:"sort"`
+	Order      string `query:"order"`
+	Limit      int    `query:"limit"`
+	Offset     int    `query:"offset"`
+}
+
+func (r ListPostersRequest) Validate() error {
+	return validation.ValidateStruct(&r,
+		validation.Field(&r.Status,
+			validation.In(
+				PosterStatusSubmitted,
+				PosterStatusInReview,
+				PosterStatusApproved,
+				PosterStatusRejected,
+				PosterStatusPosted,
+				PosterStatusCollected,
+				PosterStatusLost,
+			),
+		),
+		validation.Field(&r.Name, validation.Length(0, 64)),
+		validation.Field(&r.Sort, validation.In(poster.SortName, poster.SortPostingStartDate, poster.SortPostingEndDate)),
+		validation.Field(&r.Order, validation.In("asc", "desc")),
+		validation.Field(&r.Limit, validation.Min(0), validation.Max(maxListPostersLimit)),
+		validation.Field(&r.Offset, validation.Min(0)),
+	)
+}
+
 type EditPosterRequest struct {
 	ID          string `param:"id"`
 	PosterName  string `form:"name" json:"name"`
@@ -46,6 +92,7 @@ func (r EditPosterRequest) Validate() error {
 type UpdatePosterStatusRequest struct {
 	ID     string `param:"id"`
 	Status string `form:"status" json:"status"`
+	Note   string `form:"note" json:"note"`
 }
 
 func (r UpdatePosterStatusRequest) Validate() error {
@@ -54,11 +101,28 @@ func (r UpdatePosterStatusRequest) Validate() error {
 		validation.Field(&r.Status, 
 			validation.Required,
 			validation.In(
-				PosterStatusUncollected, 
-				PosterStatusCollected, 
+				PosterStatusSubmitted,
+				PosterStatusInReview,
+				PosterStatusApproved,
+				PosterStatusRejected,
+				PosterStatusPosted,
+				PosterStatusCollected,
 				PosterStatusLost,
 			),
 		),
+		validation.Field(&r.Note, validation.Length(0, 1024)),
+	)
+}
+
+type UpdatePosterPeriodRequest struct {
+	ID               string     `param:"id"`
+	PostingStartDate *time.Time `json:"posting_start_date"`
+	PostingEndDate   *time.Time `json:"posting_end_date"`
+}
+
+func (r UpdatePosterPeriodRequest) Validate() error {
+	return validation.ValidateStruct(&r,
+		validation.Field(&r.ID, validation.Required),
 	)
 }
 
@@ -77,18 +141,43 @@ func (h *Handler) RegisterPoster(c echo.Context) error {
 		return c.String(404, "Festival not found")
 	}
 
-	image, err := c.FormFile("image")
-	if err != nil {
-		return c.String(400, "Invalid image file: "+err.Error())
+	var image io.Reader
+	var meta storage.Metadata
+	if req.DirectUpload {
+		if !h.uploadManager.Supported() {
+			return c.String(501, "Direct upload is not supported by this storage")
+		}
+	} else {
+		imageHeader, err := c.FormFile("image")
+		if err != nil {
+			return c.String(400, "Invalid image file: "+err.Error())
+		}
+		file, fileMeta, err := storage.OpenFileHeader(imageHeader)
+		if err != nil {
+			return c.String(400, "Invalid image file: "+err.Error())
+		}
+		defer file.Close()
+		image, meta = file, fileMeta
 	}
 
 	p, err := h.posterManager.Create(
+		c.Request().Context(),
 		req.PosterName,
 		fesID,
 		req.Description,
 		image,
+		meta,
 	)
 	if err != nil {
+		if errors.Is(err, storage.ErrUnsupportedContentType) {
+			return c.String(400, "Unsupported image type")
+		}
+		if errors.Is(err, compressor.ErrFileTooLarge) {
+			return c.String(413, "Image file too large")
+		}
+		if errors.Is(err, compressor.ErrImageTooLarge) {
+			return c.String(413, "Image dimensions too large")
+		}
 		switch err {
 		case poster.ErrNotFound:
 			return c.String(404, "Festival not found")
@@ -103,21 +192,86 @@ func (h *Handler) RegisterPoster(c echo.Context) error {
 	return c.JSON(201, p)
 }
 
-func (h *Handler) ListPostersByFestival(c echo.Context) error {
+func (h *Handler) ImportPosters(c echo.Context) error {
 	fesID, err := uuid.Parse(c.Param("festival_id"))
 	if err != nil {
 		return c.String(404, "Festival not found")
 	}
 
-	posters, err := h.posterManager.GetByFestival(fesID)
+	archiveHeader, err := c.FormFile("archive")
+	if err != nil {
+		return c.String(400, "Invalid archive file: "+err.Error())
+	}
+	archive, err := archiveHeader.Open()
+	if err != nil {
+		return c.String(400, "Invalid archive file: "+err.Error())
+	}
+	defer archive.Close()
+
+	var manifest io.Reader
+	if manifestHeader, err := c.FormFile("manifest"); err == nil {
+		manifestFile, err := manifestHeader.Open()
+		if err != nil {
+			return c.String(400, "Invalid manifest file: "+err.Error())
+		}
+		defer manifestFile.Close()
+		manifest = manifestFile
+	}
+
+	report, err := h.posterManager.Import(c.Request().Context(), fesID, archive, archiveHeader.Size, manifest)
+	if err != nil {
+		switch err {
+		case poster.ErrNotFound:
+			return c.String(404, "Festival not found")
+		case poster.ErrInvalidArchive:
+			return c.String(400, "Invalid zip archive")
+		case poster.ErrInvalidManifest:
+			return c.String(400, "Invalid manifest: file and name columns are required")
+		case poster.ErrTooManyFiles:
+			return c.String(400, "Too many files in archive")
+		default:
+			slog.Error("Failed to import posters", "error", err)
+			return c.String(500, "Failed to import posters")
+		}
+	}
+
+	return c.JSON(200, report)
+}
+
+func (h *Handler) ListPostersByFestival(c echo.Context) error {
+	var req ListPostersRequest
+	if err := c.Bind(&req); err != nil {
+		slog.Error("Failed to bind list posters request", "error", err)
+		return c.String(400, "Invalid request")
+	}
+
+	if err := req.Validate(); err != nil {
+		return c.String(400, "Validation error: "+err.Error())
+	}
+
+	fesID, err := uuid.Parse(req.FestivalID)
+	if err != nil {
+		return c.String(404, "Festival not found")
+	}
+
+	if req.Offset > 0 && req.Limit == 0 {
+		req.Limit = maxListPostersLimit
+	}
+
+	result, err := h.posterManager.Search(fesID, poster.SearchQuery{
+		Status: req.Status,
+		Name:   req.Name,
+		Sort:   req.Sort,
+		Desc:   req.Order == "desc",
+		Limit:  req.Limit,
+		Offset: req.Offset,
+	})
 	if err != nil {
 		slog.Error("Failed to list posters by festival", "error", err)
 		return c.String(500, "Failed to list posters")
 	}
 
-	return c.JSON(200, map[string]any{
-		"posters": posters,
-	})
+	return c.JSON(200, result)
 }
 
 func (h *Handler) GetPoster(c echo.Context) error {
@@ -190,6 +344,97 @@ func (h *Handler) EditPoster(c echo.Context) error {
 	return c.NoContent(204)
 }
 
+func (h *Handler) UpdatePosterPeriod(c echo.Context) error {
+	var req UpdatePosterPeriodRequest
+	if err := c.Bind(&req); err != nil {
+		return c.String(400, "Invalid request")
+	}
+
+	if err := req.Validate(); err != nil {
+		return c.String(400, "Validation error: "+err.Error())
+	}
+
+	posterID, err := uuid.Parse(req.ID)
+	if err != nil {
+		return c.String(404, "Poster not found")
+	}
+
+	err = h.posterManager.SetPostingPeriod(posterID, req.PostingStartDate, req.PostingEndDate)
+	if err != nil {
+		switch err {
+		case poster.ErrNotFound:
+			return c.String(404, "Poster not found")
+		case poster.ErrInvalidPeriod:
+			return c.String(400, "Posting end date must not be before start date")
+		default:
+			slog.Error("Failed to update poster posting period", "error", err)
+			return c.String(500, "Failed to update poster posting period")
+		}
+	}
+
+	return c.NoContent(204)
+}
+
+func (h *Handler) ListOverduePosters(c echo.Context) error {
+	var festivalID *uuid.UUID
+	if s := c.QueryParam("festival_id"); s != "" {
+		id, err := uuid.Parse(s)
+		if err != nil {
+			return c.String(400, "Invalid festival_id")
+		}
+		festivalID = &id
+	}
+
+	posters, err := h.posterManager.ListOverdue(festivalID)
+	if err != nil {
+		slog.Error("Failed to list overdue posters", "error", err)
+		return c.String(500, "Failed to list overdue posters")
+	}
+
+	return c.JSON(200, map[string]any{
+		"posters": posters,
+	})
+}
+
+func (h *Handler) UpdatePosterImage(c echo.Context) error {
+	posterID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return c.String(404, "Poster not found")
+	}
+
+	imageHeader, err := c.FormFile("image")
+	if err != nil {
+		return c.String(400, "Invalid image file: "+err.Error())
+	}
+	image, meta, err := storage.OpenFileHeader(imageHeader)
+	if err != nil {
+		return c.String(400, "Invalid image file: "+err.Error())
+	}
+	defer image.Close()
+
+	err = h.posterManager.UpdateImage(c.Request().Context(), posterID, image, meta)
+	if err != nil {
+		if errors.Is(err, storage.ErrUnsupportedContentType) {
+			return c.String(400, "Unsupported image type")
+		}
+		if errors.Is(err, compressor.ErrFileTooLarge) {
+			return c.String(413, "Image file too large")
+		}
+		if errors.Is(err, compressor.ErrImageTooLarge) {
+			return c.String(413, "Image dimensions too large")
+		}
+		switch err {
+		case poster.ErrNotFound:
+			return c.String(404, "Poster not found")
+		default:
+			slog.Error("Failed to update poster image", "error", err)
+			return c.String(500, "Failed to update poster image")
+		}
+	}
+
+	return c.NoContent(204)
+}
+
 func (h *Handler) UpdatePosterStatus(c echo.Context) error {
 	var req UpdatePosterStatusRequest
 	if err := c.Bind(&req); err != nil {
@@ -206,11 +451,15 @@ func (h *Handler) UpdatePosterStatus(c echo.Context) error {
 		return c.String(404, "Poster not found")
 	}
 
-	err = h.posterManager.ChangeStatus(posterID, req.Status)
+	err = h.posterManager.ChangeStatus(posterID, req.Status, req.Note)
 	if err != nil {
 		switch err {
 		case poster.ErrNotFound:
 			return c.String(404, "Poster not found")
+		case poster.ErrInvalidTransition:
+			return c.String(409, "Invalid status transition")
+		case poster.ErrReasonRequired:
+			return c.String(400, "Rejection reason is required")
 		default:
 			slog.Error("Failed to update poster status", "error", err)
 			return c.String(500, "Failed to update poster status")
@@ -220,6 +469,28 @@ func (h *Handler) UpdatePosterStatus(c echo.Context) error {
 	return c.NoContent(204)
 }
 
+func (h *Handler) GetPosterStatusHistory(c echo.Context) error {
+	posterID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return c.String(404, "Poster not found")
+	}
+
+	history, err := h.posterManager.GetStatusHistory(posterID)
+	if err != nil {
+		switch err {
+		case poster.ErrNotFound:
+			return c.String(404, "Poster not found")
+		default:
+			slog.Error("Failed to get poster status history", "error", err)
+			return c.String(500, "Failed to get poster status history")
+		}
+	}
+
+	return c.JSON(200, map[string]any{
+		"history": history,
+	})
+}
+
 func (h *Handler) DeletePoster(c echo.Context) error {
 	posterID, err := uuid.Parse(c.Param("id"))
 	if err != nil {
@@ -239,3 +510,25 @@ func (h *Handler) DeletePoster(c echo.Context) error {
 
 	return c.NoContent(204)
 }
+
+func (h *Handler) RestorePoster(c echo.Context) error {
+	posterID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return c.String(404, "Poster not found")
+	}
+
+	p, err := h.posterManager.Restore(posterID)
+	if err != nil {
+		switch err {
+		case poster.ErrNotFound:
+			return c.String(404, "Poster not found")
+		case poster.ErrAlreadyExists:
+			return c.String(409, "Poster already exists")
+		default:
+			slog.Error("Failed to restore poster", "error", err)
+			return c.String(500, "Failed to restore poster")
+		}
+	}
+
+	return c.JSON(200, p)
+}
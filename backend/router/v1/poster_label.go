@@ -0,0 +1,125 @@
+package v1
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+
+	"github.com/Luke256/ducks/router/utils/herror"
+	"github.com/Luke256/ducks/service/festival"
+	"github.com/Luke256/ducks/service/poster"
+	"github.com/Luke256/ducks/utils/labelsheet"
+	"github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+// PrintPosterLabelsRequest IDsには印刷するポスターのIDをカンマ区切りで指定します
+// 空の場合はイベントの全てのポスターを印刷します
+type PrintPosterLabelsRequest struct {
+	FestivalID string `param:"festival_id"`
+	Template   string `query:"template"`
+	IDs        string `query:"ids"`
+	Skip       int    `query:"skip"`
+	Outline    bool   `query:"outline"`
+}
+
+func (r PrintPosterLabelsRequest) Validate() error {
+	templates := make([]any, 0, len(labelsheet.Templates))
+	for name := range labelsheet.Templates {
+		templates = append(templates, name)
+	}
+
+	return validation.ValidateStruct(&r,
+		validation.Field(&r.Template, validation.In(templates...)),
+		validation.Field(&r.Skip, validation.Min(0)),
+	)
+}
+
+// PrintPosterLabels ポスターに貼る管理ラベルを、ラベルシートに並べたPDFで返します
+// QRコードには、ポスターを回収済みにするアクショントークンのURLを埋め込みます
+func (h *Handler) PrintPosterLabels(c echo.Context) error {
+	var req PrintPosterLabelsRequest
+	if err := c.Bind(&req); err != nil {
+		return herror.BadRequest("Invalid request")
+	}
+
+	if err := req.Validate(); err != nil {
+		return herror.BadRequest("Validation failed: " + err.Error())
+	}
+
+	fesID, err := uuid.Parse(req.FestivalID)
+	if err != nil {
+		return herror.NotFound("Festival not found")
+	}
+
+	fes, err := h.festivalManager.Get(fesID)
+	if err != nil {
+		switch err {
+		case festival.ErrNotFound:
+			return herror.NotFound("Festival not found")
+		default:
+			slog.Error("failed to get festival:", slog.String("error", err.Error()))
+			return herror.InternalServerError("Failed to get festival")
+		}
+	}
+
+	result, err := h.posterManager.Search(fes.ID, poster.SearchQuery{Sort: poster.SortName})
+	if err != nil {
+		slog.Error("failed to list posters:", slog.String("error", err.Error()))
+		return herror.InternalServerError("Failed to list posters")
+	}
+
+	posters := result.Posters
+	if req.IDs != "" {
+		byID := make(map[string]poster.Poster, len(posters))
+		for _, p := range posters {
+			byID[p.ID.String()] = p
+		}
+
+		// 指定された順に印刷する
+		posters = nil
+		for _, id := range strings.Split(req.IDs, ",") {
+			p, ok := byID[strings.TrimSpace(id)]
+			if !ok {
+				return herror.NotFound("Poster not found")
+			}
+			posters = append(posters, p)
+		}
+	}
+
+	tokens, err := h.posterManager.IssueActionTokens(posters, PosterStatusCollected, maxActionTokenTTL)
+	if err != nil {
+		slog.Error("failed to issue action tokens:", slog.String("error", err.Error()))
+		return herror.InternalServerError("Failed to issue action tokens")
+	}
+
+	labels := make([]labelsheet.Label, len(posters))
+	for i, p := range posters {
+		labels[i] = labelsheet.Label{
+			Name:     p.Name,
+			Festival: fes.Name,
+			ID:       p.ID.String(),
+			QR:       h.actionURL(tokens[i].Token),
+		}
+	}
+
+	tmpl := labelsheet.Templates[labelsheet.DefaultTemplate]
+	if req.Template != "" {
+		tmpl = labelsheet.Templates[req.Template]
+	}
+
+	var buf bytes.Buffer
+	err = labelsheet.Render(&buf, tmpl, labels, labelsheet.Options{
+		Skip:     req.Skip,
+		Outline:  req.Outline,
+		FontPath: h.config.LabelFontPath,
+	})
+	if err != nil {
+		slog.Error("failed to render poster labels:", slog.String("error", err.Error()))
+		return herror.InternalServerError("Failed to render poster labels")
+	}
+
+	c.Response().Header().Set(echo.HeaderContentDisposition, `inline; filename="poster-labels.pdf"`)
+	return c.Blob(200, "application/pdf", buf.Bytes())
+}
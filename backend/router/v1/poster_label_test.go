@@ -0,0 +1,58 @@
+package v1
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestPrintPosterLabels(t *testing.T) {
+	env := setup(t, s1)
+	e := env.R(t)
+
+	fes := env.mustCreateFestival(t, "Label Fest", "Festival for printing labels")
+	poster1 := env.mustCreatePoster(t, fes.ID, "Label Poster One", "First poster")
+	_ = env.mustCreatePoster(t, fes.ID, "Label Poster Two", "Second poster")
+
+	t.Run("print all posters", func(t *testing.T) {
+		e.GET("/api/festivals/{fesID}/posters/labels", fes.ID.String()).
+			Expect().
+			Status(200).
+			ContentType("application/pdf").
+			Body().
+			HasPrefix("%PDF-")
+	})
+
+	t.Run("print selected posters", func(t *testing.T) {
+		e.GET("/api/festivals/{fesID}/posters/labels", fes.ID.String()).
+			WithQuery("ids", poster1.ID.String()).
+			WithQuery("template", "a4-24").
+			WithQuery("skip", 3).
+			WithQuery("outline", true).
+			Expect().
+			Status(200).
+			ContentType("application/pdf")
+	})
+
+	t.Run("poster in other festival", func(t *testing.T) {
+		other := env.mustCreateFestival(t, "Other Label Fest", "Another festival")
+		otherPoster := env.mustCreatePoster(t, other.ID, "Other Label Poster", "Poster in other festival")
+
+		e.GET("/api/festivals/{fesID}/posters/labels", fes.ID.String()).
+			WithQuery("ids", strings.Join([]string{poster1.ID.String(), otherPoster.ID.String()}, ",")).
+			Expect().
+			Status(404)
+	})
+
+	t.Run("unknown template", func(t *testing.T) {
+		e.GET("/api/festivals/{fesID}/posters/labels", fes.ID.String()).
+			WithQuery("template", "letter").
+			Expect().
+			Status(400)
+	})
+
+	t.Run("non-existent festival", func(t *testing.T) {
+		e.GET("/api/festivals/{fesID}/posters/labels", "00000000-0000-0000-0000-000000000000").
+			Expect().
+			Status(404)
+	})
+}
@@ -1,8 +1,11 @@
 package v1
 
 import (
+	"archive/zip"
+	"bytes"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/google/uuid"
 )
@@ -32,7 +35,7 @@ func TestRegisterPoster(t *testing.T) {
 		resp.Value("festival").Object().Value("id").IsEqual(fes.ID.String())
 		resp.Value("name").IsEqual("Awesome Poster")
 		resp.Value("description").IsEqual("This is an awesome poster.")
-		resp.Value("status").IsEqual(PosterStatusUncollected)
+		resp.Value("status").IsEqual(PosterStatusSubmitted)
 	})
 
 	t.Run("empty name", func(t *testing.T) {
@@ -74,6 +77,23 @@ func TestRegisterPoster(t *testing.T) {
 			Status(400)
 	})
 
+	t.Run("direct upload", func(t *testing.T) {
+		// 画像は後から/uploadsで設定する
+		resp := e.POST("/api/posters").
+			WithMultipart().
+			WithForm(map[string]any{
+				"festival_id":   fes.ID.String(),
+				"name":          "Directly Uploaded Poster",
+				"description":   "Poster whose image is uploaded later.",
+				"direct_upload": true,
+			}).
+			Expect().
+			Status(201).
+			JSON().
+			Object()
+		resp.Value("image_url").IsEqual("")
+	})
+
 	t.Run("empty description", func(t *testing.T) {
 		e.POST("/api/posters").
 			WithMultipart().
@@ -144,6 +164,7 @@ func TestListPostersByFestival(t *testing.T) {
 		JSON().
 		Object()
 
+	resp.Value("total").IsEqual(2)
 	array := resp.Value("posters").Array()
 	array.Length().IsEqual(2)
 	array.ContainsOnly(
@@ -153,11 +174,17 @@ func TestListPostersByFestival(t *testing.T) {
 				"id": fes.ID.String(),
 				"name": fes.Name,
 				"description": fes.Description,
+				"start_date": nil,
+				"end_date": nil,
+				"status": "planning",
 			},
 			"name":        poster1.Name,
 			"description": poster1.Description,
 			"image_url":   poster1.ImageURL,
 			"status":      poster1.Status,
+			"posting_start_date": nil,
+			"posting_end_date":   nil,
+			"overdue":            false,
 		},
 		map[string]any{
 			"id":          poster2.ID.String(),
@@ -165,15 +192,105 @@ func TestListPostersByFestival(t *testing.T) {
 				"id": fes.ID.String(),
 				"name": fes.Name,
 				"description": fes.Description,
+				"start_date": nil,
+				"end_date": nil,
+				"status": "planning",
 			},
 			"name":        poster2.Name,
 			"description": poster2.Description,
 			"image_url":   poster2.ImageURL,
 			"status":      poster2.Status,
+			"posting_start_date": nil,
+			"posting_end_date":   nil,
+			"overdue":            false,
 		},
 	)
 }
 
+func TestSearchPostersByFestival(t *testing.T) {
+	env := setup(t, s1)
+	e := env.R(t)
+
+	fes := env.mustCreateFestival(t, "Search Poster Fest", "Festival for searching posters")
+	alpha := env.mustCreatePoster(t, fes.ID, "Alpha Live", "First poster")
+	beta := env.mustCreatePoster(t, fes.ID, "Beta Live", "Second poster")
+	gamma := env.mustCreatePoster(t, fes.ID, "Gamma Exhibition", "Third poster")
+	env.mustChangePosterStatus(t, gamma.ID, PosterStatusInReview)
+
+	t.Run("filter by status", func(t *testing.T) {
+		resp := e.GET("/api/festivals/{fesID}/posters", fes.ID.String()).
+			WithQuery("status", PosterStatusInReview).
+			Expect().
+			Status(200).
+			JSON().
+			Object()
+		resp.Value("total").IsEqual(1)
+		resp.Value("posters").Array().Value(0).Object().Value("id").IsEqual(gamma.ID.String())
+	})
+
+	t.Run("filter by name", func(t *testing.T) {
+		resp := e.GET("/api/festivals/{fesID}/posters", fes.ID.String()).
+			WithQuery("name", "Live").
+			WithQuery("order", "desc").
+			Expect().
+			Status(200).
+			JSON().
+			Object()
+		resp.Value("total").IsEqual(2)
+		array := resp.Value("posters").Array()
+		array.Length().IsEqual(2)
+		array.Value(0).Object().Value("id").IsEqual(beta.ID.String())
+		array.Value(1).Object().Value("id").IsEqual(alpha.ID.String())
+	})
+
+	t.Run("paginate", func(t *testing.T) {
+		resp := e.GET("/api/festivals/{fesID}/posters", fes.ID.String()).
+			WithQuery("sort", "name").
+			WithQuery("limit", 1).
+			WithQuery("offset", 1).
+			Expect().
+			Status(200).
+			JSON().
+			Object()
+		resp.Value("total").IsEqual(3)
+		array := resp.Value("posters").Array()
+		array.Length().IsEqual(1)
+		array.Value(0).Object().Value("id").IsEqual(beta.ID.String())
+	})
+
+	t.Run("offset without limit", func(t *testing.T) {
+		resp := e.GET("/api/festivals/{fesID}/posters", fes.ID.String()).
+			WithQuery("sort", "name").
+			WithQuery("offset", 1).
+			Expect().
+			Status(200).
+			JSON().
+			Object()
+		resp.Value("total").IsEqual(3)
+		array := resp.Value("posters").Array()
+		array.Length().IsEqual(2)
+		array.Value(0).Object().Value("id").IsEqual(beta.ID.String())
+		array.Value(1).Object().Value("id").IsEqual(gamma.ID.String())
+	})
+
+	t.Run("invalid query", func(t *testing.T) {
+		e.GET("/api/festivals/{fesID}/posters", fes.ID.String()).
+			WithQuery("status", "unknown").
+			Expect().
+			Status(400)
+
+		e.GET("/api/festivals/{fesID}/posters", fes.ID.String()).
+			WithQuery("sort", "image").
+			Expect().
+			Status(400)
+
+		e.GET("/api/festivals/{fesID}/posters", fes.ID.String()).
+			WithQuery("limit", 1000).
+			Expect().
+			Status(400)
+	})
+}
+
 func TestGetPoster(t *testing.T) {
 	env := setup(t, s1)
 	e := env.R(t)
@@ -317,12 +434,14 @@ func TestUpdatePosterStatus(t *testing.T) {
 	poster := env.mustCreatePoster(t, fes.ID, "Status Poster", "Poster to update status")
 
 	t.Run("update poster status", func(t *testing.T) {
-		e.PATCH("/api/posters/{posterID}/status", poster.ID.String()).
-			WithJSON(map[string]any{
-				"status": PosterStatusCollected,
-			}).
-			Expect().
-			Status(204)
+		for _, status := range []string{PosterStatusInReview, PosterStatusApproved, PosterStatusPosted, PosterStatusCollected} {
+			e.PATCH("/api/posters/{posterID}/status", poster.ID.String()).
+				WithJSON(map[string]any{
+					"status": status,
+				}).
+				Expect().
+				Status(204)
+		}
 
 		resp := e.GET("/api/posters/{posterID}", poster.ID.String()).
 			Expect().
@@ -337,6 +456,53 @@ func TestUpdatePosterStatus(t *testing.T) {
 		resp.Value("status").IsEqual(PosterStatusCollected)
 	})
 
+	t.Run("invalid status transition", func(t *testing.T) {
+		skipped := env.mustCreatePoster(t, fes.ID, "Skipped Poster", "Poster not yet reviewed")
+		e.PATCH("/api/posters/{posterID}/status", skipped.ID.String()).
+			WithJSON(map[string]any{
+				"status": PosterStatusPosted,
+			}).
+			Expect().
+			Status(409)
+	})
+
+	t.Run("reject poster", func(t *testing.T) {
+		rejected := env.mustCreatePoster(t, fes.ID, "Rejected Poster", "Poster to be rejected")
+		env.mustChangePosterStatus(t, rejected.ID, PosterStatusInReview)
+
+		e.PATCH("/api/posters/{posterID}/status", rejected.ID.String()).
+			WithJSON(map[string]any{
+				"status": PosterStatusRejected,
+			}).
+			Expect().
+			Status(400)
+
+		e.PATCH("/api/posters/{posterID}/status", rejected.ID.String()).
+			WithJSON(map[string]any{
+				"status": PosterStatusRejected,
+				"note":   "Contact information is missing",
+			}).
+			Expect().
+			Status(204)
+
+		resp := e.GET("/api/posters/{posterID}", rejected.ID.String()).
+			Expect().
+			Status(200).
+			JSON().
+			Object()
+		resp.Value("status").IsEqual(PosterStatusRejected)
+		resp.Value("rejection_reason").IsEqual("Contact information is missing")
+	})
+
+	t.Run("unknown status", func(t *testing.T) {
+		e.PATCH("/api/posters/{posterID}/status", poster.ID.String()).
+			WithJSON(map[string]any{
+				"status": "uncollected",
+			}).
+			Expect().
+			Status(400)
+	})
+
 	t.Run("update non-existent poster status", func(t *testing.T) {
 		e.PATCH("/api/posters/00000000-0000-0000-0000-000000000000/status").
 			WithJSON(map[string]any{
@@ -347,6 +513,269 @@ func TestUpdatePosterStatus(t *testing.T) {
 	})
 }
 
+func TestImportPosters(t *testing.T) {
+	env := setup(t, s1)
+	e := env.R(t)
+
+	fes := env.mustCreateFestival(t, "Import Poster Fest", "Festival for bulk poster import")
+	env.mustCreatePoster(t, fes.ID, "Existing Poster", "Poster registered beforehand")
+
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for _, name := range []string{"circle-a.png", "posters/circle-b.png", "Existing Poster.png", "__MACOSX/._circle-a.png"} {
+		if _, err := zw.Create(name); err != nil {
+			t.Fatalf("failed to create zip entry: %v", err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatalf("failed to close zip writer: %v", err)
+	}
+	archive := buf.Bytes()
+
+	manifest := "file,name,description\n" +
+		"circle-a.png,Circle A,Poster of circle A\n" +
+		"missing.png,Missing,Not in archive\n"
+
+	t.Run("import posters", func(t *testing.T) {
+		resp := e.POST("/api/festivals/{festivalID}/posters/import", fes.ID.String()).
+			WithMultipart().
+			WithFile("archive", "posters.zip", bytes.NewReader(archive)).
+			WithFile("manifest", "manifest.csv", strings.NewReader(manifest)).
+			Expect().
+			Status(200).
+			JSON().
+			Object()
+		resp.Value("succeeded").IsEqual(2)
+		resp.Value("failed").IsEqual(2)
+
+		results := resp.Value("results").Array()
+		results.Length().IsEqual(4)
+
+		circleA := results.Value(0).Object()
+		circleA.Value("name").IsEqual("Circle A")
+		circleA.Value("poster").Object().Value("description").IsEqual("Poster of circle A")
+		circleA.NotContainsKey("error")
+
+		results.Value(1).Object().Value("name").IsEqual("circle-b")
+		results.Value(2).Object().Value("error").IsEqual("poster already exists")
+		results.Value(3).Object().Value("file").IsEqual("missing.png")
+		results.Value(3).Object().Value("error").IsEqual("file not found in archive")
+
+		e.GET("/api/festivals/{festivalID}/posters", fes.ID.String()).
+			Expect().
+			Status(200).
+			JSON().
+			Object().
+			Value("posters").Array().
+			Length().IsEqual(3)
+	})
+
+	t.Run("duplicate file names", func(t *testing.T) {
+		var buf bytes.Buffer
+		zw := zip.NewWriter(&buf)
+		for _, name := range []string{"circle-c/poster.png", "circle-d/poster.png"} {
+			if _, err := zw.Create(name); err != nil {
+				t.Fatalf("failed to create zip entry: %v", err)
+			}
+		}
+		if err := zw.Close(); err != nil {
+			t.Fatalf("failed to close zip writer: %v", err)
+		}
+
+		resp := e.POST("/api/festivals/{festivalID}/posters/import", fes.ID.String()).
+			WithMultipart().
+			WithFile("archive", "posters.zip", bytes.NewReader(buf.Bytes())).
+			WithFile("manifest", "manifest.csv", strings.NewReader("file,name\nposter.png,Circle Poster\n")).
+			Expect().
+			Status(200).
+			JSON().
+			Object()
+		resp.Value("succeeded").IsEqual(0)
+		resp.Value("failed").IsEqual(2)
+
+		results := resp.Value("results").Array()
+		results.Length().IsEqual(2)
+		for i := range 2 {
+			results.Value(i).Object().Value("error").IsEqual("duplicate file name in archive")
+		}
+	})
+
+	t.Run("invalid archive", func(t *testing.T) {
+		e.POST("/api/festivals/{festivalID}/posters/import", fes.ID.String()).
+			WithMultipart().
+			WithFile("archive", "posters.zip", strings.NewReader("not a zip")).
+			Expect().
+			Status(400)
+	})
+
+	t.Run("invalid manifest", func(t *testing.T) {
+		e.POST("/api/festivals/{festivalID}/posters/import", fes.ID.String()).
+			WithMultipart().
+			WithFile("archive", "posters.zip", bytes.NewReader(archive)).
+			WithFile("manifest", "manifest.csv", strings.NewReader("title\nfoo\n")).
+			Expect().
+			Status(400)
+	})
+
+	t.Run("non-existent festival", func(t *testing.T) {
+		e.POST("/api/festivals/00000000-0000-0000-0000-000000000000/posters/import").
+			WithMultipart().
+			WithFile("archive", "posters.zip", bytes.NewReader(archive)).
+			Expect().
+			Status(404)
+	})
+}
+
+func TestPosterPostingPeriod(t *testing.T) {
+	env := setup(t, s1)
+	e := env.R(t)
+
+	festivalEnd := time.Now().Add(-24 * time.Hour).UTC().Truncate(time.Second)
+	fes, err := env.FM.Create("Overdue Poster Fest", "Festival already finished", nil, &festivalEnd)
+	if err != nil {
+		t.Fatalf("failed to create festival: %v", err)
+	}
+	overdue := env.mustCreatePoster(t, fes.ID, "Overdue Poster", "Poster past the deadline")
+	extended := env.mustCreatePoster(t, fes.ID, "Extended Poster", "Poster with extended period")
+	env.mustChangePosterStatus(t, overdue.ID, PosterStatusInReview, PosterStatusApproved, PosterStatusPosted)
+	env.mustChangePosterStatus(t, extended.ID, PosterStatusInReview, PosterStatusApproved, PosterStatusPosted)
+
+	t.Run("posting period defaults to festival", func(t *testing.T) {
+		resp := e.GET("/api/posters/{posterID}", overdue.ID.String()).
+			Expect().
+			Status(200).
+			JSON().
+			Object()
+		resp.Value("posting_end_date").IsEqual(festivalEnd.Format(time.RFC3339))
+		resp.Value("overdue").IsEqual(true)
+	})
+
+	t.Run("update posting period", func(t *testing.T) {
+		e.PATCH("/api/posters/{posterID}/period", extended.ID.String()).
+			WithJSON(map[string]any{
+				"posting_end_date": time.Now().Add(24 * time.Hour).UTC().Format(time.RFC3339),
+			}).
+			Expect().
+			Status(204)
+
+		e.GET("/api/posters/{posterID}", extended.ID.String()).
+			Expect().
+			Status(200).
+			JSON().
+			Object().
+			Value("overdue").IsEqual(false)
+	})
+
+	t.Run("invalid posting period", func(t *testing.T) {
+		e.PATCH("/api/posters/{posterID}/period", extended.ID.String()).
+			WithJSON(map[string]any{
+				"posting_start_date": "2026-11-10T00:00:00Z",
+				"posting_end_date":   "2026-11-01T00:00:00Z",
+			}).
+			Expect().
+			Status(400)
+	})
+
+	t.Run("list overdue posters", func(t *testing.T) {
+		posters := e.GET("/api/posters/overdue").
+			WithQuery("festival_id", fes.ID.String()).
+			Expect().
+			Status(200).
+			JSON().
+			Object().
+			Value("posters").Array()
+		posters.Length().IsEqual(1)
+		posters.Value(0).Object().Value("id").IsEqual(overdue.ID.String())
+		posters.Value(0).Object().Value("overdue").IsEqual(true)
+	})
+
+	t.Run("update non-existent poster period", func(t *testing.T) {
+		e.PATCH("/api/posters/00000000-0000-0000-0000-000000000000/period").
+			WithJSON(map[string]any{}).
+			Expect().
+			Status(404)
+	})
+}
+
+func TestUpdatePosterImage(t *testing.T) {
+	env := setup(t, s1)
+	e := env.R(t)
+
+	fes := env.mustCreateFestival(t, "Image Poster Fest", "Festival for poster image replacement")
+	poster := env.mustCreatePoster(t, fes.ID, "Image Poster", "Poster to be reprinted")
+
+	t.Run("update poster image", func(t *testing.T) {
+		e.PUT("/api/posters/{posterID}/image", poster.ID.String()).
+			WithMultipart().
+			WithFile("image", "reprinted.png", strings.NewReader("")).
+			Expect().
+			Status(204)
+	})
+
+	t.Run("missing image", func(t *testing.T) {
+		e.PUT("/api/posters/{posterID}/image", poster.ID.String()).
+			WithMultipart().
+			Expect().
+			Status(400)
+	})
+
+	t.Run("update non-existent poster image", func(t *testing.T) {
+		e.PUT("/api/posters/00000000-0000-0000-0000-000000000000/image").
+			WithMultipart().
+			WithFile("image", "reprinted.png", strings.NewReader("")).
+			Expect().
+			Status(404)
+	})
+}
+
+func TestGetPosterStatusHistory(t *testing.T) {
+	env := setup(t, s1)
+	e := env.R(t)
+
+	fes := env.mustCreateFestival(t, "History Poster Fest", "Festival for poster status history")
+	poster := env.mustCreatePoster(t, fes.ID, "History Poster", "Poster with status history")
+
+	t.Run("get poster status history", func(t *testing.T) {
+		e.PATCH("/api/posters/{posterID}/status", poster.ID.String()).
+			WithJSON(map[string]any{
+				"status": PosterStatusInReview,
+				"note":   "received at the office",
+			}).
+			Expect().
+			Status(204)
+		e.PATCH("/api/posters/{posterID}/status", poster.ID.String()).
+			WithJSON(map[string]any{
+				"status": PosterStatusApproved,
+			}).
+			Expect().
+			Status(204)
+
+		history := e.GET("/api/posters/{posterID}/history", poster.ID.String()).
+			Expect().
+			Status(200).
+			JSON().
+			Object().
+			Value("history").Array()
+		history.Length().IsEqual(2)
+
+		first := history.Value(0).Object()
+		first.Value("previous_status").IsEqual(PosterStatusSubmitted)
+		first.Value("new_status").IsEqual(PosterStatusInReview)
+		first.Value("note").IsEqual("received at the office")
+		first.Value("changed_at").NotNull()
+
+		second := history.Value(1).Object()
+		second.Value("previous_status").IsEqual(PosterStatusInReview)
+		second.Value("new_status").IsEqual(PosterStatusApproved)
+	})
+
+	t.Run("get non-existent poster status history", func(t *testing.T) {
+		e.GET("/api/posters/00000000-0000-0000-0000-000000000000/history").
+			Expect().
+			Status(404)
+	})
+}
+
 func TestDeletePoster(t *testing.T) {
 	env := setup(t, s1)
 	e := env.R(t)
@@ -4,13 +4,26 @@ import (
 	"github.com/Luke256/ducks/repository"
 	"github.com/Luke256/ducks/service/festival"
 	festivalstock "github.com/Luke256/ducks/service/festival_stock"
+	"github.com/Luke256/ducks/service/location"
 	"github.com/Luke256/ducks/service/poster"
 	"github.com/Luke256/ducks/service/sale"
 	stockitem "github.com/Luke256/ducks/service/stock_item"
+	"github.com/Luke256/ducks/service/upload"
 	"github.com/Luke256/ducks/utils/storage"
+	"github.com/Luke256/ducks/utils/transcode"
 	"github.com/labstack/echo/v4"
 )
 
+// Config 起動時に環境変数から読み込む、ハンドラーの設定
+type Config struct {
+	// AdminToken 設定されている場合、Authorization: Bearer <token> を付けたリクエストで終了したイベントのロックを無視できます
+	AdminToken string
+	// APIEndpoint QRコードに埋め込むURLの起点
+	APIEndpoint string
+	// LabelFontPath ポスターの管理ラベルの印刷に使うTrueTypeフォントのパス
+	LabelFontPath string
+}
+
 type Handler struct {
 	r                    repository.Repository
 	festivalManager      festival.Manager
@@ -18,10 +31,14 @@ type Handler struct {
 	stockItemManager     stockitem.Manager
 	festivalStockManager festivalstock.Manager
 	saleManager          sale.Manager
+	locationManager      location.Manager
+	uploadManager        upload.Manager
 	storage              storage.Storage
+	transcoder           *transcode.Cache
+	config               Config
 }
 
-func NewHandler(r repository.Repository, fm festival.Manager, pm poster.Manager, sim stockitem.Manager, fsm festivalstock.Manager, sm sale.Manager, s storage.Storage) *Handler {
+func NewHandler(r repository.Repository, fm festival.Manager, pm poster.Manager, sim stockitem.Manager, fsm festivalstock.Manager, sm sale.Manager, lm location.Manager, um upload.Manager, s storage.Storage, tc *transcode.Cache, config Config) *Handler {
 	return &Handler{
 		r:                    r,
 		festivalManager:      fm,
@@ -29,7 +46,11 @@ func NewHandler(r repository.Repository, fm festival.Manager, pm poster.Manager,
 		stockItemManager:     sim,
 		festivalStockManager: fsm,
 		saleManager:          sm,
+		locationManager:      lm,
+		uploadManager:        um,
 		storage:              s,
+		transcoder:           tc,
+		config:               config,
 	}
 }
 
@@ -40,25 +61,42 @@ func (r *Handler) Setup(g *echo.Group) {
 	stockItems := g.Group("/items")
 	festivalStocks := g.Group("/stocks")
 	sales := g.Group("/sales")
+	trash := g.Group("/trash")
+	locations := g.Group("/locations")
+	placements := g.Group("/placements")
+	actions := g.Group("/actions")
+	uploads := g.Group("/uploads")
 
 	// Images
 	images.GET("/:id", r.GetImage)
+	images.HEAD("/:id", r.GetImage)
 
 	// Festivals
 	festivals.POST("", r.CreateFestival)
 	festivals.GET("", r.ListFestivals)
 	festivals.GET("/:id", r.GetFestival)
 	festivals.PUT("/:id", r.EditFestival)
+	festivals.PATCH("/:id/status", r.UpdateFestivalStatus)
+	festivals.POST("/:id/clone", r.CloneFestival)
 	festivals.DELETE("/:id", r.DeleteFestival)
+	festivals.POST("/:id/restore", r.RestoreFestival)
 
 	// Posters
 	posters.POST("", r.RegisterPoster)
 	festivals.GET("/:festival_id/posters", r.ListPostersByFestival)
+	festivals.POST("/:festival_id/posters/import", r.ImportPosters)
+	festivals.GET("/:festival_id/posters/labels", r.PrintPosterLabels)
+	posters.GET("/overdue", r.ListOverduePosters)
 	posters.GET("/:id", r.GetPoster)
 	posters.GET("/:festival_id/:poster_name", r.GetPosterByFestivalAndName)
 	posters.PUT("/:id", r.EditPoster)
+	posters.PUT("/:id/image", r.UpdatePosterImage)
+	posters.PATCH("/:id/period", r.UpdatePosterPeriod)
 	posters.PATCH("/:id/status", r.UpdatePosterStatus)
+	posters.GET("/:id/history", r.GetPosterStatusHistory)
+	posters.POST("/:id/action-tokens", r.IssuePosterActionToken)
 	posters.DELETE("/:id", r.DeletePoster)
+	posters.POST("/:id/restore", r.RestorePoster)
 
 	// Stock Items
 	stockItems.POST("", r.RegisterStockItem)
@@ -67,13 +105,17 @@ func (r *Handler) Setup(g *echo.Group) {
 	stockItems.PUT("/:id", r.EditStockItem)
 	stockItems.PUT("/:id/image", r.UpdateStockItemImage)
 	stockItems.DELETE("/:id", r.DeleteStockItem)
+	stockItems.POST("/:id/restore", r.RestoreStockItem)
 
 	// Festival Stocks
 	festivals.POST("/:festival_id/stocks", r.RegisterFestivalStock)
 	festivals.GET("/:festival_id/stocks", r.QueryFestivalStocks)
+	festivals.PUT("/:festival_id/stocks/order", r.ReorderFestivalStocks)
 	festivalStocks.GET("/:id", r.GetFestivalStock)
 	festivalStocks.PUT("/:id", r.UpdateFestivalStock)
+	festivalStocks.PATCH("/:id/display", r.UpdateFestivalStockDisplay)
 	festivalStocks.DELETE("/:id", r.DeleteFestivalStock)
+	festivalStocks.POST("/:id/restore", r.RestoreFestivalStock)
 
 	// Sales
 	sales.POST("", r.CreateSaleRecord)
@@ -81,4 +123,32 @@ func (r *Handler) Setup(g *echo.Group) {
 	festivalStocks.GET("/:festival_stock_id/sales", r.GetSaleRecordsByStockID)
 	sales.GET("", r.QuerySaleRecords)
 	sales.DELETE("/:id", r.DeleteSaleRecord)
+
+	// Locations
+	locations.POST("", r.CreateLocation)
+	locations.GET("", r.ListLocations)
+	locations.PUT("/route", r.ReorderLocations)
+	locations.GET("/:id", r.GetLocation)
+	locations.PUT("/:id", r.EditLocation)
+	locations.DELETE("/:id", r.DeleteLocation)
+
+	// Poster Placements
+	posters.POST("/:id/placements", r.PlacePoster)
+	posters.GET("/:id/placements", r.ListPosterPlacements)
+	placements.PATCH("/:id/status", r.UpdatePlacementStatus)
+	placements.DELETE("/:id", r.DeletePlacement)
+	festivals.GET("/:festival_id/collection-route", r.GetCollectionRoute)
+	festivals.GET("/:festival_id/collection-route/print", r.PrintCollectionRoute)
+
+	// Actions
+	actions.GET("/:token", r.GetAction)
+	actions.POST("/:token", r.ApplyAction)
+
+	// Uploads
+	uploads.POST("", r.RequestUpload)
+	uploads.GET("/:id", r.GetUpload)
+	uploads.POST("/:id/complete", r.CompleteUpload)
+
+	// Trash
+	trash.GET("", r.ListTrash)
 }
@@ -1,6 +1,7 @@
 package v1
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
@@ -13,11 +14,17 @@ import (
 	gormRepo "github.com/Luke256/ducks/repository/gorm"
 	"github.com/Luke256/ducks/service/festival"
 	festivalstock "github.com/Luke256/ducks/service/festival_stock"
+	"github.com/Luke256/ducks/service/location"
 	"github.com/Luke256/ducks/service/poster"
 	"github.com/Luke256/ducks/service/sale"
 	stockitem "github.com/Luke256/ducks/service/stock_item"
+	"github.com/Luke256/ducks/service/upload"
 	"github.com/Luke256/ducks/utils"
+	"github.com/Luke256/ducks/utils/actiontoken"
+	"github.com/Luke256/ducks/utils/compressor"
+	"github.com/Luke256/ducks/utils/storage"
 	mockstorage "github.com/Luke256/ducks/utils/storage/mock_storage"
+	"github.com/Luke256/ducks/utils/transcode"
 	"github.com/gavv/httpexpect/v2"
 	"github.com/google/uuid"
 	"github.com/labstack/echo/v4"
@@ -33,6 +40,11 @@ const (
 	s1       = "s1"
 	s2       = "s2"
 	s3       = "s3"
+
+	// testAdminToken 終了したイベントのロックを無視できる管理者トークン
+	testAdminToken = "admin-token"
+	// testItemMaxBytes テストで上限を超える画像を送れるよう、物品画像の上限を小さくする
+	testItemMaxBytes = 1 << 10
 )
 
 var (
@@ -60,6 +72,15 @@ func TestMain(m *testing.M) {
 		panic(err)
 	}
 
+	transcodeDir, err := os.MkdirTemp("", "ducks-transcode-test-")
+	if err != nil {
+		panic(err)
+	}
+	transcoder, err := transcode.NewCache(transcodeDir, 1<<20)
+	if err != nil {
+		panic(err)
+	}
+
 	for _, key := range dbs {
 		env := &env{}
 		dbConfig := *config
@@ -94,10 +115,14 @@ func TestMain(m *testing.M) {
 		env.Storage = &mockstorage.MockStorage{}
 
 		env.FM = festival.NewManagerImpl(repo)
-		env.PM = poster.NewManagerImpl(repo, env.Storage)
-		env.SIM = stockitem.NewManagerImpl(repo, env.Storage)
+		env.PM = poster.NewManagerImpl(repo, env.Storage, actiontoken.NewSigner([]byte("test-secret")), compressor.PosterProfile)
+		itemProfile := compressor.ItemProfile
+		itemProfile.MaxBytes = testItemMaxBytes
+		env.SIM = stockitem.NewManagerImpl(repo, env.Storage, itemProfile)
 		env.FSM = festivalstock.NewManagerImpl(repo, env.Storage)
 		env.SM = sale.NewManagerImpl(repo)
+		env.LM = location.NewManagerImpl(repo, env.Storage)
+		env.UM = upload.NewManagerImpl(repo, env.Storage, env.PM, env.SIM)
 
 		// サーバー
 		e := echo.New()
@@ -111,7 +136,11 @@ func TestMain(m *testing.M) {
 			env.SIM,
 			env.FSM,
 			env.SM,
+			env.LM,
+			env.UM,
 			env.Storage,
+			transcoder,
+			Config{AdminToken: testAdminToken},
 		)
 		handlers.Setup(e.Group("/api"))
 		env.Server = httptest.NewServer(e)
@@ -129,6 +158,7 @@ func TestMain(m *testing.M) {
 		e.Server.Close()
 		db.Close()
 	}
+	os.RemoveAll(transcodeDir)
 
 	os.Exit(code)
 }
@@ -142,6 +172,8 @@ type env struct {
 	SIM     stockitem.Manager
 	FSM     festivalstock.Manager
 	SM      sale.Manager
+	LM      location.Manager
+	UM      upload.Manager
 	Storage *mockstorage.MockStorage
 }
 
@@ -175,7 +207,7 @@ func setup(t *testing.T, dbKey string) *env {
 
 func (e *env) mustCreateFestival(t *testing.T, name string, description string) festival.Festival {
 	t.Helper()
-	festival, err := e.FM.Create(name, description)
+	festival, err := e.FM.Create(name, description, nil, nil)
 	if err != nil {
 		t.Fatalf("failed to create festival: %v", err)
 	}
@@ -184,7 +216,7 @@ func (e *env) mustCreateFestival(t *testing.T, name string, description string)
 
 func (e *env) mustCreatePoster(t *testing.T, festivalID uuid.UUID, name string, description string) poster.Poster {
 	t.Helper()
-	poster, err := e.PM.Create(name, festivalID, description, nil)
+	poster, err := e.PM.Create(context.Background(), name, festivalID, description, nil, storage.Metadata{})
 	if err != nil {
 		t.Fatalf("failed to create poster: %v", err)
 	}
@@ -193,7 +225,7 @@ func (e *env) mustCreatePoster(t *testing.T, festivalID uuid.UUID, name string,
 
 func (e *env) mustCreateStockItem(t *testing.T, name string, description string, category string) stockitem.StockItem {
 	t.Helper()
-	item, err := e.SIM.Create(name, description, category, nil)
+	item, err := e.SIM.Create(context.Background(), name, description, category, nil, storage.Metadata{})
 	if err != nil {
 		t.Fatalf("failed to create stock item: %v", err)
 	}
@@ -202,7 +234,7 @@ func (e *env) mustCreateStockItem(t *testing.T, name string, description string,
 
 func (e *env) mustCreateFestivalStock(t *testing.T, festivalID, itemID uuid.UUID, price int, description string) festivalstock.Stock {
 	t.Helper()
-	stock, err := e.FSM.Create(festivalID, itemID, price, description)
+	stock, err := e.FSM.Create(festivalID, itemID, price, description, false)
 	if err != nil {
 		t.Fatalf("failed to create festival stock: %v", err)
 	}
@@ -211,7 +243,7 @@ func (e *env) mustCreateFestivalStock(t *testing.T, festivalID, itemID uuid.UUID
 
 func (e *env) mustCreateSaleRecord(t *testing.T, stockID uuid.UUID, quantity int) sale.SaleRecord {
 	t.Helper()
-	record, err := e.SM.Create(sale.SaleRecord{
+	record, err := e.SM.Create(false, sale.SaleRecord{
 		StockID:  stockID,
 		Quantity: quantity,
 	})
@@ -219,4 +251,32 @@ func (e *env) mustCreateSaleRecord(t *testing.T, stockID uuid.UUID, quantity int
 		t.Fatalf("failed to create sale record: %v", err)
 	}
 	return record[0]
-}
\ No newline at end of file
+}
+
+func (e *env) mustCreateLocation(t *testing.T, building, floor, boardName string) location.Location {
+	t.Helper()
+	loc, err := e.LM.Create(building, floor, boardName, nil, nil)
+	if err != nil {
+		t.Fatalf("failed to create location: %v", err)
+	}
+	return loc
+}
+
+func (e *env) mustListPlacements(t *testing.T, posterID uuid.UUID) []location.Placement {
+	t.Helper()
+	placements, err := e.LM.ListPlacements(posterID)
+	if err != nil {
+		t.Fatalf("failed to list placements: %v", err)
+	}
+	return placements
+}
+
+// mustChangePosterStatus ポスターのステータスを指定された順に変更します
+func (e *env) mustChangePosterStatus(t *testing.T, posterID uuid.UUID, statuses ...string) {
+	t.Helper()
+	for _, status := range statuses {
+		if err := e.PM.ChangeStatus(posterID, status, "note"); err != nil {
+			t.Fatalf("failed to change poster status to %s: %v", status, err)
+		}
+	}
+}
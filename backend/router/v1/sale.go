@@ -4,6 +4,7 @@ import (
 	"log/slog"
 
 	"github.com/Luke256/ducks/router/utils/herror"
+	"github.com/Luke256/ducks/service/festival"
 	festivalstock "github.com/Luke256/ducks/service/festival_stock"
 	"github.com/Luke256/ducks/service/sale"
 	"github.com/go-ozzo/ozzo-validation/v4"
@@ -48,11 +49,13 @@ func (h *Handler) CreateSaleRecord(c echo.Context) error {
 		}
 	}
 
-	record, err := h.saleManager.Create(saleItems...)
+	record, err := h.saleManager.Create(h.isAdminOverride(c), saleItems...)
 	if err != nil {
 		switch err {
 		case festivalstock.ErrNotFound:
 			return herror.NotFound("Festival stock not found")
+		case festival.ErrLocked:
+			return herror.Forbidden("Festival is closed")
 		default:
 			slog.Error("Failed to create sale record", "error", err)
 			return herror.InternalServerError("Failed to create sale record")
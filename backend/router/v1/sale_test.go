@@ -6,6 +6,59 @@ import (
 	"github.com/google/uuid"
 )
 
+func TestCreateSaleRecordLockedFestival(t *testing.T) {
+	env := setup(t, common)
+	e := env.R(t)
+
+	fes := env.mustCreateFestival(t, "Closed Festival", "Description")
+	stockItem := env.mustCreateStockItem(t, "Test Stock Item", "Category", "")
+	stock := env.mustCreateFestivalStock(t, fes.ID, stockItem.ID, 100, "")
+	if err := env.FM.ChangeStatus(fes.ID, "closed"); err != nil {
+		t.Fatalf("failed to close festival: %v", err)
+	}
+
+	payload := map[string]any{
+		"items": []map[string]any{
+			{
+				"stock_id": stock.ID.String(),
+				"quantity": 1,
+			},
+		},
+	}
+
+	t.Run("Create Sale Record in Closed Festival", func(t *testing.T) {
+		e.POST("/api/sales").
+			WithJSON(payload).
+			Expect().
+			Status(403)
+	})
+
+	t.Run("Create Sale Record with Admin Override", func(t *testing.T) {
+		e.POST("/api/sales").
+			WithHeader("Authorization", "Bearer "+testAdminToken).
+			WithJSON(payload).
+			Expect().
+			Status(201)
+	})
+
+	t.Run("Create Sale Record with Wrong Admin Token", func(t *testing.T) {
+		e.POST("/api/sales").
+			WithHeader("Authorization", "Bearer wrong-token").
+			WithJSON(payload).
+			Expect().
+			Status(403)
+	})
+
+	t.Run("Edit Festival Stock in Closed Festival", func(t *testing.T) {
+		e.PUT("/api/stocks/{festival_stock_id}", stock.ID).
+			WithJSON(map[string]any{
+				"description": "Updated",
+			}).
+			Expect().
+			Status(403)
+	})
+}
+
 func TestCreateSaleRecord(t *testing.T) {
 	env := setup(t, common)
 	e := env.R(t)
@@ -1,19 +1,25 @@
 package v1
 
 import (
+	"errors"
+	"io"
 	"log/slog"
 
 	"github.com/Luke256/ducks/router/utils/herror"
 	stockitem "github.com/Luke256/ducks/service/stock_item"
+	"github.com/Luke256/ducks/utils/compressor"
+	"github.com/Luke256/ducks/utils/storage"
 	"github.com/go-ozzo/ozzo-validation/v4"
 	"github.com/google/uuid"
 	"github.com/labstack/echo/v4"
 )
 
 type RegisterStockItemRequest struct {
-	Name        string `form:"name"`
-	Description string `form:"description"`
-	Category    string `form:"category"`
+	Name        string `form:"name" json:"name"`
+	Description string `form:"description" json:"description"`
+	Category    string `form:"category" json:"category"`
+	// DirectUpload trueの場合は画像を送らずに作成し、後から/uploadsでストレージに直接アップロードする
+	DirectUpload bool `form:"direct_upload" json:"direct_upload"`
 }
 
 func (r RegisterStockItemRequest) Validate() error {
@@ -32,13 +38,36 @@ func (h *Handler) RegisterStockItem(c echo.Context) error {
 		return herror.BadRequest("Validation failed: " + err.Error())
 	}
 
-	image, err := c.FormFile("image")
-	if err != nil {
-		return herror.BadRequest("Image is required")
+	var image io.Reader
+	var meta storage.Metadata
+	if req.DirectUpload {
+		if !h.uploadManager.Supported() {
+			return herror.HTTPError(501, "Direct upload is not supported by this storage")
+		}
+	} else {
+		imageHeader, err := c.FormFile("image")
+		if err != nil {
+			return herror.BadRequest("Image is required")
+		}
+		file, fileMeta, err := storage.OpenFileHeader(imageHeader)
+		if err != nil {
+			return herror.BadRequest("Invalid image file")
+		}
+		defer file.Close()
+		image, meta = file, fileMeta
 	}
 
-	item, err := h.stockItemManager.Create(req.Name, req.Description, req.Category, image)
+	item, err := h.stockItemManager.Create(c.Request().Context(), req.Name, req.Description, req.Category, image, meta)
 	if err != nil {
+		if errors.Is(err, storage.ErrUnsupportedContentType) {
+			return herror.BadRequest("Unsupported image type")
+		}
+		if errors.Is(err, compressor.ErrFileTooLarge) {
+			return herror.HTTPError(413, "Image file too large")
+		}
+		if errors.Is(err, compressor.ErrImageTooLarge) {
+			return herror.HTTPError(413, "Image dimensions too large")
+		}
 		slog.Error("failed to register stock item:", slog.String("error", err.Error()))
 		return c.String(500, "Failed to register stock item")
 	}
@@ -114,13 +143,27 @@ func (h *Handler) UpdateStockItemImage(c echo.Context) error {
 		return herror.NotFound("Stock item not found")
 	}
 
-	image, err := c.FormFile("image")
+	imageHeader, err := c.FormFile("image")
 	if err != nil {
 		return herror.BadRequest("Image is required")
 	}
+	image, meta, err := storage.OpenFileHeader(imageHeader)
+	if err != nil {
+		return herror.BadRequest("Invalid image file")
+	}
+	defer image.Close()
 
-	err = h.stockItemManager.UpdateImage(id, image)
+	err = h.stockItemManager.UpdateImage(c.Request().Context(), id, image, meta)
 	if err != nil {
+		if errors.Is(err, storage.ErrUnsupportedContentType) {
+			return herror.BadRequest("Unsupported image type")
+		}
+		if errors.Is(err, compressor.ErrFileTooLarge) {
+			return herror.HTTPError(413, "Image file too large")
+		}
+		if errors.Is(err, compressor.ErrImageTooLarge) {
+			return herror.HTTPError(413, "Image dimensions too large")
+		}
 		switch err {
 		case stockitem.ErrNotFound:
 			return herror.NotFound("Stock item not found")
@@ -150,4 +193,30 @@ func (h *Handler) DeleteStockItem(c echo.Context) error {
 		}
 	}
 	return c.NoContent(204)
-}
\ No newline at end of file
+}
+
+func (h *Handler) RestoreStockItem(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return herror.NotFound("Stock item not found")
+	}
+
+	err = h.stockItemManager.Restore(id)
+	if err != nil {
+		switch err {
+		case stockitem.ErrNotFound:
+			return herror.NotFound("Stock item not found")
+		default:
+			slog.Error("failed to restore stock item:", slog.String("error", err.Error()))
+			return c.String(500, "Failed to restore stock item")
+		}
+	}
+
+	item, err := h.stockItemManager.Get(id)
+	if err != nil {
+		slog.Error("failed to get stock item after restore:", slog.String("error", err.Error()))
+		return c.String(500, "Failed to get stock item")
+	}
+
+	return c.JSON(200, item)
+}
@@ -57,6 +57,23 @@ func TestRegisterStockItem(t *testing.T) {
 			Status(400)
 	})
 
+	t.Run("Direct Upload", func(t *testing.T) {
+		// 画像は後から/uploadsで設定する
+		resp := e.POST("/api/items").
+			WithMultipart().
+			WithForm(map[string]any{
+				"name":          "Directly Uploaded Stock Item",
+				"description":   "This is a stock item whose image is uploaded later.",
+				"category":      "Sample Category",
+				"direct_upload": true,
+			}).
+			Expect().
+			Status(201).
+			JSON().
+			Object()
+		resp.Value("image_url").IsEqual("")
+	})
+
 	t.Run("Empty Category", func(t *testing.T) {
 		e.POST("/api/items").
 			WithMultipart().
@@ -224,6 +241,48 @@ func TestUpdateStockItemImage(t *testing.T) {
 			Status(204)
 	})
 
+	t.Run("UpdateStockItemImage Replaces Old Image", func(t *testing.T) {
+		imageID := func() string {
+			stored, err := env.Repo.GetStockItemByID(item.ID)
+			if err != nil {
+				t.Fatalf("failed to get stock item: %v", err)
+			}
+			return stored.ImageID
+		}
+
+		e.PUT("/api/items/{id}/image", item.ID.String()).
+			WithMultipart().
+			WithFile("image", "old_image.png", strings.NewReader("")).
+			Expect().
+			Status(204)
+		oldImageID := imageID()
+		if !env.Storage.HasFile(oldImageID) {
+			t.Fatalf("expected image %s to be stored", oldImageID)
+		}
+
+		// 上限を超える画像では差し替えず、古い画像も残す
+		e.PUT("/api/items/{id}/image", item.ID.String()).
+			WithMultipart().
+			WithFile("image", "large_image.png", strings.NewReader(strings.Repeat("a", testItemMaxBytes+1))).
+			Expect().
+			Status(413)
+		if got := imageID(); got != oldImageID || !env.Storage.HasFile(oldImageID) {
+			t.Errorf("expected old image %s to be kept, got %s", oldImageID, got)
+		}
+
+		e.PUT("/api/items/{id}/image", item.ID.String()).
+			WithMultipart().
+			WithFile("image", "new_image.png", strings.NewReader("")).
+			Expect().
+			Status(204)
+		if newImageID := imageID(); newImageID == oldImageID || !env.Storage.HasFile(newImageID) {
+			t.Errorf("expected image to be replaced, got %s", newImageID)
+		}
+		if env.Storage.HasFile(oldImageID) {
+			t.Errorf("expected old image %s to be deleted", oldImageID)
+		}
+	})
+
 	t.Run("UpdateStockItemImage Not Found", func(t *testing.T) {
 		id, err := uuid.NewV7()
 		if err != nil {
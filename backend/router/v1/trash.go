@@ -0,0 +1,42 @@
+package v1
+
+import (
+	"log/slog"
+
+	"github.com/Luke256/ducks/router/utils/herror"
+	"github.com/labstack/echo/v4"
+)
+
+// ListTrash 削除済みのイベント・ポスター・アイテム・販売アイテムを一覧します
+func (h *Handler) ListTrash(c echo.Context) error {
+	festivals, err := h.festivalManager.ListDeleted()
+	if err != nil {
+		slog.Error("Failed to list deleted festivals", "error", err)
+		return herror.InternalServerError("Failed to list trash")
+	}
+
+	posters, err := h.posterManager.ListDeleted()
+	if err != nil {
+		slog.Error("Failed to list deleted posters", "error", err)
+		return herror.InternalServerError("Failed to list trash")
+	}
+
+	items, err := h.stockItemManager.ListDeleted()
+	if err != nil {
+		slog.Error("Failed to list deleted stock items", "error", err)
+		return herror.InternalServerError("Failed to list trash")
+	}
+
+	stocks, err := h.festivalStockManager.ListDeleted()
+	if err != nil {
+		slog.Error("Failed to list deleted festival stocks", "error", err)
+		return herror.InternalServerError("Failed to list trash")
+	}
+
+	return c.JSON(200, map[string]any{
+		"festivals": festivals,
+		"posters":   posters,
+		"items":     items,
+		"stocks":    stocks,
+	})
+}
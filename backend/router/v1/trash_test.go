@@ -0,0 +1,84 @@
+package v1
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestTrash(t *testing.T) {
+	env := setup(t, s3)
+	e := env.R(t)
+
+	fes := env.mustCreateFestival(t, "Trash Festival", "Description")
+	item := env.mustCreateStockItem(t, "Trash Item", "Description", "Category")
+	stock := env.mustCreateFestivalStock(t, fes.ID, item.ID, 100, "Description")
+	p := env.mustCreatePoster(t, fes.ID, "Trash Poster", "Description")
+	env.mustCreateSaleRecord(t, stock.ID, 2)
+
+	e.DELETE("/api/stocks/{id}", stock.ID).Expect().Status(204)
+	e.DELETE("/api/posters/{id}", p.ID).Expect().Status(204)
+	e.DELETE("/api/items/{id}", item.ID).Expect().Status(204)
+	e.DELETE("/api/festivals/{id}", fes.ID).Expect().Status(204)
+
+	t.Run("List Trash", func(t *testing.T) {
+		res := e.GET("/api/trash").
+			Expect().
+			Status(200).
+			JSON().
+			Object()
+
+		res.Value("festivals").Array().Length().IsEqual(1)
+		res.Value("festivals").Array().Value(0).Object().Value("id").IsEqual(fes.ID.String())
+		res.Value("festivals").Array().Value(0).Object().Value("deleted_at").NotNull()
+		res.Value("posters").Array().Length().IsEqual(1)
+		res.Value("items").Array().Length().IsEqual(1)
+		res.Value("stocks").Array().Length().IsEqual(1)
+	})
+
+	t.Run("Sales Are Kept", func(t *testing.T) {
+		e.GET("/api/sales").
+			WithQuery("festival_id", fes.ID.String()).
+			Expect().
+			Status(200).
+			JSON().
+			Object().
+			Value("sales").Array().Length().IsEqual(1)
+	})
+
+	t.Run("Restore All", func(t *testing.T) {
+		e.POST("/api/festivals/{id}/restore", fes.ID).
+			Expect().
+			Status(200).
+			JSON().
+			Object().
+			Value("id").IsEqual(fes.ID.String())
+		e.POST("/api/items/{id}/restore", item.ID).Expect().Status(200)
+		e.POST("/api/stocks/{id}/restore", stock.ID).Expect().Status(200)
+		e.POST("/api/posters/{id}/restore", p.ID).Expect().Status(200)
+
+		res := e.GET("/api/trash").
+			Expect().
+			Status(200).
+			JSON().
+			Object()
+		res.Value("festivals").Array().IsEmpty()
+		res.Value("posters").Array().IsEmpty()
+		res.Value("items").Array().IsEmpty()
+		res.Value("stocks").Array().IsEmpty()
+
+		e.GET("/api/stocks/{id}/sales", stock.ID).
+			Expect().
+			Status(200).
+			JSON().
+			Object().
+			Value("sales").Array().Length().IsEqual(1)
+	})
+
+	t.Run("Restore Not Deleted", func(t *testing.T) {
+		e.POST("/api/festivals/{id}/restore", fes.ID).Expect().Status(404)
+		e.POST("/api/posters/{id}/restore", uuid.New()).Expect().Status(404)
+		e.POST("/api/items/{id}/restore", "invalid-uuid").Expect().Status(404)
+		e.POST("/api/stocks/{id}/restore", uuid.Nil).Expect().Status(404)
+	})
+}
@@ -0,0 +1,111 @@
+package v1
+
+import (
+	"errors"
+	"log/slog"
+
+	"github.com/Luke256/ducks/router/utils/herror"
+	"github.com/Luke256/ducks/service/upload"
+	"github.com/Luke256/ducks/utils/storage"
+	"github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+type RequestUploadRequest struct {
+	TargetType  string `json:"target_type"`
+	TargetID    string `json:"target_id"`
+	ContentType string `json:"content_type"`
+}
+
+func (r RequestUploadRequest) Validate() error {
+	return validation.ValidateStruct(&r,
+		validation.Field(&r.TargetType, validation.Required, validation.In(upload.TargetPoster, upload.TargetItem)),
+		validation.Field(&r.TargetID, validation.Required),
+		validation.Field(&r.ContentType, validation.Length(0, 255)),
+	)
+}
+
+// RequestUpload ポスターやアイテムの画像をストレージに直接アップロードするためのURLを発行します
+// クライアントはURLにファイルを送信した後、CompleteUploadを呼び出します
+func (h *Handler) RequestUpload(c echo.Context) error {
+	var req RequestUploadRequest
+	if err := c.Bind(&req); err != nil {
+		return herror.BadRequest("Invalid request")
+	}
+	if err := req.Validate(); err != nil {
+		return herror.BadRequest("Validation failed: " + err.Error())
+	}
+
+	targetID, err := uuid.Parse(req.TargetID)
+	if err != nil {
+		return herror.NotFound("Upload target not found")
+	}
+
+	u, err := h.uploadManager.Request(c.Request().Context(), req.TargetType, targetID, req.ContentType)
+	if err != nil {
+		switch {
+		case errors.Is(err, upload.ErrNotSupported):
+			return herror.HTTPError(501, "Direct upload is not supported by this storage")
+		case errors.Is(err, upload.ErrTargetNotFound):
+			return herror.NotFound("Upload target not found")
+		case errors.Is(err, upload.ErrInvalidTarget):
+			return herror.BadRequest("Invalid upload target")
+		case errors.Is(err, storage.ErrUnsupportedContentType):
+			return herror.BadRequest("Unsupported image type")
+		default:
+			slog.Error("failed to request upload:", slog.String("error", err.Error()))
+			return herror.InternalServerError("Failed to request upload")
+		}
+	}
+
+	return c.JSON(201, u)
+}
+
+// CompleteUpload アップロードの完了を受け付け、画像の処理を開始します
+// 処理の結果はGetUploadで確認できます
+func (h *Handler) CompleteUpload(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return herror.NotFound("Upload not found")
+	}
+
+	u, err := h.uploadManager.Confirm(c.Request().Context(), id)
+	if err != nil {
+		switch {
+		case errors.Is(err, upload.ErrNotSupported):
+			return herror.HTTPError(501, "Direct upload is not supported by this storage")
+		case errors.Is(err, upload.ErrNotFound):
+			return herror.NotFound("Upload not found")
+		case errors.Is(err, upload.ErrNotUploaded):
+			return herror.Conflict("File has not been uploaded")
+		case errors.Is(err, upload.ErrAlreadyConfirmed):
+			return herror.Conflict("Upload already completed")
+		default:
+			slog.Error("failed to complete upload:", slog.String("error", err.Error()))
+			return herror.InternalServerError("Failed to complete upload")
+		}
+	}
+
+	return c.JSON(202, u)
+}
+
+func (h *Handler) GetUpload(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return herror.NotFound("Upload not found")
+	}
+
+	u, err := h.uploadManager.Get(id)
+	if err != nil {
+		switch {
+		case errors.Is(err, upload.ErrNotFound):
+			return herror.NotFound("Upload not found")
+		default:
+			slog.Error("failed to get upload:", slog.String("error", err.Error()))
+			return herror.InternalServerError("Failed to get upload")
+		}
+	}
+
+	return c.JSON(200, u)
+}
@@ -0,0 +1,209 @@
+package v1
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Luke256/ducks/service/upload"
+	"github.com/gavv/httpexpect/v2"
+	"github.com/google/uuid"
+)
+
+// waitUploadStatus 非同期の処理が終わるまで、アップロードの状態を取得し続けます
+func waitUploadStatus(t *testing.T, e *httpexpect.Expect, id string) *httpexpect.Object {
+	t.Helper()
+	for range 100 {
+		resp := e.GET("/api/uploads/{id}", id).
+			Expect().
+			Status(200).
+			JSON().
+			Object()
+		if resp.Value("status").String().Raw() != upload.StatusProcessing {
+			return resp
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+	t.Fatalf("upload %s did not finish processing", id)
+	return nil
+}
+
+func TestDirectUpload(t *testing.T) {
+	env := setup(t, s2)
+	e := env.R(t)
+
+	fes := env.mustCreateFestival(t, "Direct Upload Fest", "Festival for direct uploads")
+	poster := env.mustCreatePoster(t, fes.ID, "Direct Upload Poster", "Poster uploaded directly")
+
+	before, err := env.Repo.GetPosterByID(poster.ID)
+	if err != nil {
+		t.Fatalf("failed to get poster: %v", err)
+	}
+
+	var uploadID string
+
+	t.Run("request upload", func(t *testing.T) {
+		resp := e.POST("/api/uploads").
+			WithJSON(map[string]any{
+				"target_type":  upload.TargetPoster,
+				"target_id":    poster.ID.String(),
+				"content_type": "image/png",
+			}).
+			Expect().
+			Status(201).
+			JSON().
+			Object()
+		resp.Value("status").IsEqual(upload.StatusWaiting)
+		resp.Value("method").IsEqual("PUT")
+		resp.Value("url").String().NotEmpty()
+		resp.Value("headers").Object().Value("Content-Type").IsEqual("image/png")
+
+		uploadID = resp.Value("id").String().Raw()
+	})
+
+	t.Run("complete before upload", func(t *testing.T) {
+		e.POST("/api/uploads/{id}/complete", uploadID).
+			Expect().
+			Status(409)
+	})
+
+	t.Run("complete upload", func(t *testing.T) {
+		u, err := env.Repo.GetImageUploadByID(uuid.MustParse(uploadID))
+		if err != nil {
+			t.Fatalf("failed to get image upload: %v", err)
+		}
+		env.Storage.PutUpload(u.ObjectKey, []byte("image"), "image/png")
+
+		e.POST("/api/uploads/{id}/complete", uploadID).
+			Expect().
+			Status(202).
+			JSON().
+			Object().
+			Value("status").IsEqual(upload.StatusProcessing)
+
+		waitUploadStatus(t, e, uploadID).Value("status").IsEqual(upload.StatusCompleted)
+
+		after, err := env.Repo.GetPosterByID(poster.ID)
+		if err != nil {
+			t.Fatalf("failed to get poster: %v", err)
+		}
+		if after.ImageID == before.ImageID {
+			t.Error("expected poster image to be replaced")
+		}
+
+		// 処理後は元のファイルを削除する
+		if _, _, err := env.Storage.OpenUpload(t.Context(), u.ObjectKey); err == nil {
+			t.Error("expected uploaded file to be deleted")
+		}
+	})
+
+	t.Run("complete twice", func(t *testing.T) {
+		e.POST("/api/uploads/{id}/complete", uploadID).
+			Expect().
+			Status(409)
+	})
+}
+
+func TestDirectUpload_CreatePoster(t *testing.T) {
+	env := setup(t, s2)
+	e := env.R(t)
+
+	fes := env.mustCreateFestival(t, "Direct Create Fest", "Festival for creating posters with direct uploads")
+
+	// 画像を送らずにポスターを登録し、画像はストレージに直接アップロードする
+	posterID := e.POST("/api/posters").
+		WithJSON(map[string]any{
+			"festival_id":   fes.ID.String(),
+			"name":          "Direct Create Poster",
+			"description":   "Poster created without a multipart body",
+			"direct_upload": true,
+		}).
+		Expect().
+		Status(201).
+		JSON().
+		Object().
+		Value("id").String().Raw()
+
+	e.GET("/api/posters/{posterID}", posterID).
+		Expect().
+		Status(200).
+		JSON().
+		Object().
+		Value("image_url").IsEqual("")
+
+	uploadID := e.POST("/api/uploads").
+		WithJSON(map[string]any{
+			"target_type":  upload.TargetPoster,
+			"target_id":    posterID,
+			"content_type": "image/png",
+		}).
+		Expect().
+		Status(201).
+		JSON().
+		Object().
+		Value("id").String().Raw()
+
+	u, err := env.Repo.GetImageUploadByID(uuid.MustParse(uploadID))
+	if err != nil {
+		t.Fatalf("failed to get image upload: %v", err)
+	}
+	env.Storage.PutUpload(u.ObjectKey, []byte("image"), "image/png")
+
+	e.POST("/api/uploads/{id}/complete", uploadID).
+		Expect().
+		Status(202)
+	waitUploadStatus(t, e, uploadID).Value("status").IsEqual(upload.StatusCompleted)
+
+	e.GET("/api/posters/{posterID}", posterID).
+		Expect().
+		Status(200).
+		JSON().
+		Object().
+		Value("image_url").String().NotEmpty()
+}
+
+func TestDirectUpload_InvalidRequest(t *testing.T) {
+	env := setup(t, s2)
+	e := env.R(t)
+
+	item := env.mustCreateStockItem(t, "Direct Upload Item", "Item uploaded directly", "Category")
+
+	t.Run("unknown target type", func(t *testing.T) {
+		e.POST("/api/uploads").
+			WithJSON(map[string]any{
+				"target_type": "festival",
+				"target_id":   item.ID.String(),
+			}).
+			Expect().
+			Status(400)
+	})
+
+	t.Run("target not found", func(t *testing.T) {
+		e.POST("/api/uploads").
+			WithJSON(map[string]any{
+				"target_type": upload.TargetPoster,
+				"target_id":   item.ID.String(),
+			}).
+			Expect().
+			Status(404)
+	})
+
+	t.Run("unsupported content type", func(t *testing.T) {
+		e.POST("/api/uploads").
+			WithJSON(map[string]any{
+				"target_type":  upload.TargetItem,
+				"target_id":    item.ID.String(),
+				"content_type": "text/plain",
+			}).
+			Expect().
+			Status(400)
+	})
+
+	t.Run("upload not found", func(t *testing.T) {
+		e.GET("/api/uploads/{id}", uuid.NewString()).
+			Expect().
+			Status(404)
+		e.POST("/api/uploads/{id}/complete", uuid.NewString()).
+			Expect().
+			Status(404)
+	})
+}
@@ -2,23 +2,42 @@ package festival
 
 import (
 	"errors"
+	"time"
 
 	"github.com/google/uuid"
 )
 
+const (
+	FestivalStatusPlanning = "planning"
+	FestivalStatusOpen     = "open"
+	FestivalStatusClosed   = "closed"
+	FestivalStatusArchived = "archived"
+)
+
 var (
-	ErrNotFound	  = errors.New("not found")
+	ErrNotFound      = errors.New("not found")
+	ErrInvalidPeriod = errors.New("end date is before start date")
+	ErrLocked        = errors.New("festival is closed or archived")
 )
 
 type Festival struct {
-	ID          uuid.UUID `json:"id"`
-	Name        string    `json:"name"`
-	Description string    `json:"description"`
+	ID          uuid.UUID  `json:"id"`
+	Name        string     `json:"name"`
+	Description string     `json:"description"`
+	StartDate   *time.Time `json:"start_date"`
+	EndDate     *time.Time `json:"end_date"`
+	Status      string     `json:"status"`
+	DeletedAt   *time.Time `json:"deleted_at,omitempty"`
+}
+
+// IsLocked 指定されたステータスのイベントが、販売やアイテムの変更を受け付けないかを返します
+func IsLocked(status string) bool {
+	return status == FestivalStatusClosed || status == FestivalStatusArchived
 }
 
 type Manager interface {
 	// Create イベントを作成します
-	Create(name, description string) (Festival, error)
+	Create(name, description string, startDate, endDate *time.Time) (Festival, error)
 
 	// Get 指定されたIDのイベントを取得します
 	Get(id uuid.UUID) (Festival, error)
@@ -27,8 +46,22 @@ type Manager interface {
 	List() ([]Festival, error)
 
 	// Edit 指定されたIDのイベント情報を更新します
-	Edit(id uuid.UUID, name, description string) error
+	Edit(id uuid.UUID, name, description string, startDate, endDate *time.Time) error
+
+	// ChangeStatus 指定されたIDのイベントのステータスを変更します
+	ChangeStatus(id uuid.UUID, status string) error
+
+	// Clone 指定されたIDのイベントをもとに新しいイベントを作成します
+	// 販売するアイテムの価格にはpriceDeltaが加算され、withPostersがtrueの場合はポスター情報も複製します
+	Clone(sourceID uuid.UUID, name, description string, priceDelta int, withPosters bool) (Festival, error)
 
 	// Delete 指定されたIDのイベントを削除します
+	// 削除したイベントはRestoreで復元できます
 	Delete(id uuid.UUID) error
+
+	// ListDeleted 削除済みのイベントを取得します
+	ListDeleted() ([]Festival, error)
+
+	// Restore 指定されたIDの削除済みのイベントを復元します
+	Restore(id uuid.UUID) error
 }
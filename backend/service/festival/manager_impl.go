@@ -2,7 +2,9 @@ package festival
 
 import (
 	"fmt"
+	"time"
 
+	"github.com/Luke256/ducks/model"
 	"github.com/Luke256/ducks/repository"
 	"github.com/google/uuid"
 )
@@ -17,17 +19,34 @@ func NewManagerImpl(repo repository.FestivalRepository) *ManagerImpl {
 	}
 }
 
-func (f *ManagerImpl) Create(name, description string) (Festival, error) {
-	festival, err := f.repo.RegisterFestival(name, description)
-	if err != nil {
-		return Festival{}, err
+func (f *ManagerImpl) toFestivalType(festival model.Festival) Festival {
+	var deletedAt *time.Time
+	if festival.DeletedAt.Valid {
+		deletedAt = &festival.DeletedAt.Time
 	}
 
 	return Festival{
 		ID:          festival.ID,
 		Name:        festival.Name,
 		Description: festival.Description,
-	}, nil
+		StartDate:   festival.StartDate,
+		EndDate:     festival.EndDate,
+		Status:      festival.Status,
+		DeletedAt:   deletedAt,
+	}
+}
+
+func (f *ManagerImpl) Create(name, description string, startDate, endDate *time.Time) (Festival, error) {
+	if startDate != nil && endDate != nil && endDate.Before(*startDate) {
+		return Festival{}, ErrInvalidPeriod
+	}
+
+	festival, err := f.repo.RegisterFestival(name, description, startDate, endDate)
+	if err != nil {
+		return Festival{}, err
+	}
+
+	return f.toFestivalType(festival), nil
 }
 
 func (f *ManagerImpl) Get(id uuid.UUID) (Festival, error) {
@@ -41,11 +60,7 @@ func (f *ManagerImpl) Get(id uuid.UUID) (Festival, error) {
 		}
 	}
 
-	return Festival{
-		ID:          festival.ID,
-		Name:        festival.Name,
-		Description: festival.Description,
-	}, nil
+	return f.toFestivalType(festival), nil
 }
 
 func (f *ManagerImpl) List() ([]Festival, error) {
@@ -57,18 +72,18 @@ func (f *ManagerImpl) List() ([]Festival, error) {
 	result := make([]Festival, len(festivals))
 
 	for i, festival := range festivals {
-		result[i] = Festival{
-			ID:          festival.ID,
-			Name:        festival.Name,
-			Description: festival.Description,
-		}
+		result[i] = f.toFestivalType(festival)
 	}
 
 	return result, nil
 }
 
-func (f *ManagerImpl) Edit(id uuid.UUID, name, description string) error {
-	err := f.repo.UpdateFestival(id, name, description)
+func (f *ManagerImpl) Edit(id uuid.UUID, name, description string, startDate, endDate *time.Time) error {
+	if startDate != nil && endDate != nil && endDate.Before(*startDate) {
+		return ErrInvalidPeriod
+	}
+
+	err := f.repo.UpdateFestival(id, name, description, startDate, endDate)
 
 	if err != nil {
 		switch err {
@@ -82,6 +97,34 @@ func (f *ManagerImpl) Edit(id uuid.UUID, name, description string) error {
 	return nil
 }
 
+func (f *ManagerImpl) ChangeStatus(id uuid.UUID, status string) error {
+	err := f.repo.UpdateFestivalStatus(id, status)
+	if err != nil {
+		switch err {
+		case repository.ErrNotFound:
+			return ErrNotFound
+		default:
+			return fmt.Errorf("failed to update festival status: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func (f *ManagerImpl) Clone(sourceID uuid.UUID, name, description string, priceDelta int, withPosters bool) (Festival, error) {
+	festival, err := f.repo.CloneFestival(sourceID, name, description, priceDelta, withPosters)
+	if err != nil {
+		switch err {
+		case repository.ErrNotFound:
+			return Festival{}, ErrNotFound
+		default:
+			return Festival{}, fmt.Errorf("failed to clone festival: %w", err)
+		}
+	}
+
+	return f.toFestivalType(festival), nil
+}
+
 func (f *ManagerImpl) Delete(id uuid.UUID) error {
 	err := f.repo.DeleteFestival(id)
 	if err != nil {
@@ -95,3 +138,31 @@ func (f *ManagerImpl) Delete(id uuid.UUID) error {
 
 	return nil
 }
+
+func (f *ManagerImpl) ListDeleted() ([]Festival, error) {
+	festivals, err := f.repo.GetDeletedFestivals()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get deleted festivals: %w", err)
+	}
+
+	result := make([]Festival, len(festivals))
+	for i, festival := range festivals {
+		result[i] = f.toFestivalType(festival)
+	}
+
+	return result, nil
+}
+
+func (f *ManagerImpl) Restore(id uuid.UUID) error {
+	err := f.repo.RestoreFestival(id)
+	if err != nil {
+		switch err {
+		case repository.ErrNotFound:
+			return ErrNotFound
+		default:
+			return fmt.Errorf("failed to restore festival: %w", err)
+		}
+	}
+
+	return nil
+}
@@ -2,6 +2,7 @@ package festivalstock
 
 import (
 	"errors"
+	"time"
 
 	stockitem "github.com/Luke256/ducks/service/stock_item"
 	"github.com/google/uuid"
@@ -17,22 +18,43 @@ type Stock struct {
 	FestivalID  uuid.UUID           `json:"festival_id"`
 	Price       int                 `json:"price"`
 	Description string              `json:"description"`
+	Position    int                 `json:"position"`
+	Hidden      bool                `json:"hidden"`
+	Color       string              `json:"color"`
+	DeletedAt   *time.Time          `json:"deleted_at,omitempty"`
 }
 
 type Manager interface {
 	// Create イベントで販売するアイテムを登録します
-	Create(festivalID, itemID uuid.UUID, price int, description string) (Stock, error)
+	//
+	// 以下の変更系の操作は、イベントが終了(closed)またはアーカイブ(archived)されている場合、
+	// overrideがtrueでない限りfestival.ErrLockedを返します
+	Create(festivalID, itemID uuid.UUID, price int, description string, override bool) (Stock, error)
 
 	// Get 指定されたIDのイベントで販売するアイテムを取得します
 	Get(id uuid.UUID) (Stock, error)
 
 	// Query イベントIDやカテゴリで販売するアイテムを検索します
 	// festivalID, categoryが空文字の場合、全てのカテゴリを対象とします
-	Query(festivalID uuid.UUID, category string) ([]Stock, error)
+	// 結果は表示順に並び、visibleOnlyがtrueの場合は非表示のアイテムを除外します
+	Query(festivalID uuid.UUID, category string, visibleOnly bool) ([]Stock, error)
 
 	// Update 指定されたIDのイベントで販売するアイテムの説明を更新します
-	Update(id uuid.UUID, description string) error
+	Update(id uuid.UUID, description string, override bool) error
+
+	// UpdateDisplay 指定されたIDのイベントで販売するアイテムの表示状態とボタン色を更新します
+	UpdateDisplay(id uuid.UUID, hidden bool, color string, override bool) error
+
+	// Reorder 指定されたイベントで販売するアイテムを、指定された順に並び替えます
+	// 指定されなかったアイテムは、現在の順序を保ったまま末尾に並びます
+	Reorder(festivalID uuid.UUID, ids []uuid.UUID, override bool) error
 
 	// Delete 指定されたIDのイベントで販売するアイテムを削除します
-	Delete(id uuid.UUID) error
+	Delete(id uuid.UUID, override bool) error
+
+	// ListDeleted 削除済みのイベントで販売するアイテムを取得します
+	ListDeleted() ([]Stock, error)
+
+	// Restore 指定されたIDの削除済みのイベントで販売するアイテムを復元します
+	Restore(id uuid.UUID) error
 }
@@ -1,6 +1,8 @@
 package festivalstock
 
 import (
+	"time"
+
 	"github.com/Luke256/ducks/model"
 	"github.com/Luke256/ducks/repository"
 	"github.com/Luke256/ducks/service/festival"
@@ -22,6 +24,11 @@ func NewManagerImpl(repo repository.Repository, storage storage.Storage) *Manage
 }
 
 func (fm *ManagerImpl) toStockType(fs model.FestivalStock) Stock {
+	var deletedAt *time.Time
+	if fs.DeletedAt.Valid {
+		deletedAt = &fs.DeletedAt.Time
+	}
+
 	return Stock{
 		ID: fs.ID,
 		Item: stockitem.StockItem{
@@ -29,17 +36,45 @@ func (fm *ManagerImpl) toStockType(fs model.FestivalStock) Stock {
 			Name:        fs.StockItem.Name,
 			Description: fs.StockItem.Description,
 			Category:    fs.StockItem.Category,
-			ImageURL:    fm.storage.GetFileURL(fs.StockItem.ImageID),
+			ImageURL:    storage.FileURL(fm.storage, fs.StockItem.ImageID),
 		},
-		FestivalID: fs.FestivalID,
-		Price:      fs.Price,
+		FestivalID:  fs.FestivalID,
+		Price:       fs.Price,
 		Description: fs.Description,
+		Position:    fs.Position,
+		Hidden:      fs.Hidden,
+		Color:       fs.Color,
+		DeletedAt:   deletedAt,
+	}
+}
+
+// checkEditable イベントが変更を受け付ける状態かを確認します
+// 削除されたイベントは読み込まれずゼロ値になるため、終了したイベントと同様に扱います
+func checkEditable(fes model.Festival, override bool) error {
+	if (fes.ID == uuid.Nil || festival.IsLocked(fes.Status)) && !override {
+		return festival.ErrLocked
 	}
+	return nil
 }
 
-func (fm *ManagerImpl) Create(festivalID, itemID uuid.UUID, price int, description string) (Stock, error) {
+// checkStockEditable イベントで販売するアイテムが変更可能かを確認します
+func (fm *ManagerImpl) checkStockEditable(id uuid.UUID, override bool) error {
+	fesStock, err := fm.repo.GetFestivalStockByID(id)
+	if err != nil {
+		switch err {
+		case repository.ErrNotFound:
+			return ErrNotFound
+		default:
+			return err
+		}
+	}
+
+	return checkEditable(fesStock.Festival, override)
+}
+
+func (fm *ManagerImpl) Create(festivalID, itemID uuid.UUID, price int, description string, override bool) (Stock, error) {
 	// festival exists
-	_, err := fm.repo.GetFestivalByID(festivalID)
+	fes, err := fm.repo.GetFestivalByID(festivalID)
 	if err != nil {
 		switch err {
 		case repository.ErrNotFound:
@@ -49,6 +84,10 @@ func (fm *ManagerImpl) Create(festivalID, itemID uuid.UUID, price int, descripti
 		}
 	}
 
+	if err := checkEditable(fes, override); err != nil {
+		return Stock{}, err
+	}
+
 	// item exists
 	_, err = fm.repo.GetStockItemByID(itemID)
 	if err != nil {
@@ -82,8 +121,8 @@ func (fm *ManagerImpl) Get(id uuid.UUID) (Stock, error) {
 	return fm.toStockType(fesStock), nil
 }
 
-func (fm *ManagerImpl) Query(festivalID uuid.UUID, category string) ([]Stock, error) {
-	fesStocks, err := fm.repo.QueryFestivalStocks(festivalID, category)
+func (fm *ManagerImpl) Query(festivalID uuid.UUID, category string, visibleOnly bool) ([]Stock, error) {
+	fesStocks, err := fm.repo.QueryFestivalStocks(festivalID, category, visibleOnly)
 	if err != nil {
 		return nil, err
 	}
@@ -96,7 +135,11 @@ func (fm *ManagerImpl) Query(festivalID uuid.UUID, category string) ([]Stock, er
 	return result, nil
 }
 
-func (fm *ManagerImpl) Update(id uuid.UUID, description string) error {
+func (fm *ManagerImpl) Update(id uuid.UUID, description string, override bool) error {
+	if err := fm.checkStockEditable(id, override); err != nil {
+		return err
+	}
+
 	err := fm.repo.UpdateFestivalStock(id, description)
 	switch err {
 	case nil:
@@ -108,7 +151,54 @@ func (fm *ManagerImpl) Update(id uuid.UUID, description string) error {
 	}
 }
 
-func (fm *ManagerImpl) Delete(id uuid.UUID) error {
+func (fm *ManagerImpl) UpdateDisplay(id uuid.UUID, hidden bool, color string, override bool) error {
+	if err := fm.checkStockEditable(id, override); err != nil {
+		return err
+	}
+
+	err := fm.repo.UpdateFestivalStockDisplay(id, hidden, color)
+	switch err {
+	case nil:
+		return nil
+	case repository.ErrNotFound:
+		return ErrNotFound
+	default:
+		return err
+	}
+}
+
+func (fm *ManagerImpl) Reorder(festivalID uuid.UUID, ids []uuid.UUID, override bool) error {
+	// festival exists
+	fes, err := fm.repo.GetFestivalByID(festivalID)
+	if err != nil {
+		switch err {
+		case repository.ErrNotFound:
+			return festival.ErrNotFound
+		default:
+			return err
+		}
+	}
+
+	if err := checkEditable(fes, override); err != nil {
+		return err
+	}
+
+	err = fm.repo.ReorderFestivalStocks(festivalID, ids)
+	switch err {
+	case nil:
+		return nil
+	case repository.ErrNotFound:
+		return ErrNotFound
+	default:
+		return err
+	}
+}
+
+func (fm *ManagerImpl) Delete(id uuid.UUID, override bool) error {
+	if err := fm.checkStockEditable(id, override); err != nil {
+		return err
+	}
+
 	err := fm.repo.DeleteFestivalStock(id)
 	switch err {
 	case nil:
@@ -119,3 +209,29 @@ func (fm *ManagerImpl) Delete(id uuid.UUID) error {
 		return err
 	}
 }
+
+func (fm *ManagerImpl) ListDeleted() ([]Stock, error) {
+	fesStocks, err := fm.repo.GetDeletedFestivalStocks()
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]Stock, len(fesStocks))
+	for i, fs := range fesStocks {
+		result[i] = fm.toStockType(fs)
+	}
+
+	return result, nil
+}
+
+func (fm *ManagerImpl) Restore(id uuid.UUID) error {
+	err := fm.repo.RestoreFestivalStock(id)
+	switch err {
+	case nil:
+		return nil
+	case repository.ErrNotFound:
+		return ErrNotFound
+	default:
+		return err
+	}
+}
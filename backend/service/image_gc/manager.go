@@ -0,0 +1,50 @@
+package imagegc
+
+import (
+	"context"
+	"time"
+)
+
+// Options 画像の削除の設定
+type Options struct {
+	// GracePeriod この期間内に保存された画像は、参照されていなくても削除しません
+	// アップロードしてからポスターやアイテムを登録するまでの間に削除されないようにするためのものです
+	GracePeriod time.Duration
+	// DryRun trueの場合は削除せず、削除する画像を報告するだけにします
+	DryRun bool
+}
+
+// OrphanedImage どこからも参照されていない画像
+type OrphanedImage struct {
+	FileName string    `json:"file_name"`
+	Files    []string  `json:"files"`
+	Size     int64     `json:"size"`
+	ModTime  time.Time `json:"mod_time"`
+	Error    string    `json:"error,omitempty"`
+}
+
+// Report 画像の削除の結果
+type Report struct {
+	DryRun bool `json:"dry_run"`
+	// Scanned ストレージにあった画像の数
+	// 縮小画像は元の画像とまとめて1つと数えます
+	Scanned int `json:"scanned"`
+	// Referenced ポスターやアイテムから参照されている画像の数
+	Referenced int `json:"referenced"`
+	// Recent 参照されていないが、猶予期間内のため削除しなかった画像の数
+	Recent int `json:"recent"`
+	// Ignored アップロードした画像の命名規則に合わないため無視したファイルの数
+	Ignored  int             `json:"ignored"`
+	Orphaned []OrphanedImage `json:"orphaned"`
+	// Deleted 削除した画像の数
+	Deleted    int   `json:"deleted"`
+	Failed     int   `json:"failed"`
+	FreedBytes int64 `json:"freed_bytes"`
+}
+
+type Manager interface {
+	// Collect ポスターやアイテムから参照されていない画像を、縮小画像とともにストレージから削除します
+	// 削除済みのポスターやアイテムが参照している画像は、復元できるよう削除しません
+	// 途中でキャンセルされた場合は、それまでの結果とエラーを返します
+	Collect(ctx context.Context, opts Options) (Report, error)
+}
@@ -0,0 +1,120 @@
+package imagegc
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"maps"
+	"path"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/Luke256/ducks/repository"
+	"github.com/Luke256/ducks/utils/compressor"
+	"github.com/Luke256/ducks/utils/storage"
+	"github.com/google/uuid"
+)
+
+type ManagerImpl struct {
+	repo    repository.Repository
+	storage storage.Storage
+}
+
+func NewManagerImpl(repo repository.Repository, storage storage.Storage) *ManagerImpl {
+	return &ManagerImpl{
+		repo:    repo,
+		storage: storage,
+	}
+}
+
+func (m *ManagerImpl) Collect(ctx context.Context, opts Options) (Report, error) {
+	// 一覧を取得してから参照を確認することで、その間に登録された画像を削除しないようにする
+	files, err := m.storage.ListFiles(ctx)
+	if err != nil {
+		return Report{}, fmt.Errorf("failed to list files: %w", err)
+	}
+
+	imageIDs, err := m.repo.ListImageIDs()
+	if err != nil {
+		return Report{}, fmt.Errorf("failed to list image ids: %w", err)
+	}
+	referenced := map[string]bool{}
+	for _, id := range imageIDs {
+		referenced[id] = true
+	}
+
+	report := Report{DryRun: opts.DryRun, Orphaned: []OrphanedImage{}}
+
+	images := map[string]*OrphanedImage{}
+	for _, file := range files {
+		fileName, ok := imageFileName(file.Name)
+		if !ok {
+			report.Ignored++
+			continue
+		}
+
+		image, ok := images[fileName]
+		if !ok {
+			image = &OrphanedImage{FileName: fileName}
+			images[fileName] = image
+		}
+		image.Files = append(image.Files, file.Name)
+		image.Size += file.Size
+		if file.ModTime.After(image.ModTime) {
+			image.ModTime = file.ModTime
+		}
+	}
+	report.Scanned = len(images)
+
+	deadline := time.Now().Add(-opts.GracePeriod)
+	for _, fileName := range slices.Sorted(maps.Keys(images)) {
+		image := images[fileName]
+		switch {
+		case referenced[fileName]:
+			report.Referenced++
+			continue
+		case image.ModTime.After(deadline):
+			report.Recent++
+			continue
+		}
+
+		slices.Sort(image.Files)
+		if !opts.DryRun {
+			if err := ctx.Err(); err != nil {
+				return report, err
+			}
+
+			if err := m.storage.DeleteFile(ctx, fileName); err != nil {
+				slog.Warn("failed to delete orphaned image", "file_name", fileName, "error", err)
+				image.Error = err.Error()
+				report.Failed++
+			} else {
+				report.Deleted++
+				report.FreedBytes += image.Size
+			}
+		}
+		report.Orphaned = append(report.Orphaned, *image)
+	}
+
+	return report, nil
+}
+
+// imageFileName アップロード時に付けたファイル名から、元の画像のファイル名を返します
+// 縮小画像の場合は元の画像のファイル名を返します
+// アップロードした画像の命名規則に合わない場合はfalseを返します
+func imageFileName(fileName string) (string, bool) {
+	ext := path.Ext(fileName)
+	base := strings.TrimSuffix(fileName, ext)
+	for _, r := range compressor.Renditions {
+		if trimmed, ok := strings.CutSuffix(base, "_"+r.Name); ok {
+			base = trimmed
+			break
+		}
+	}
+
+	if _, err := uuid.Parse(base); err != nil {
+		return "", false
+	}
+	return base + ext, true
+}
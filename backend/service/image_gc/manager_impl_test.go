@@ -0,0 +1,143 @@
+package imagegc
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/Luke256/ducks/repository"
+	"github.com/Luke256/ducks/utils/storage/local"
+)
+
+type stubRepository struct {
+	repository.Repository
+	imageIDs []string
+}
+
+func (r *stubRepository) ListImageIDs() ([]string, error) {
+	return r.imageIDs, nil
+}
+
+const (
+	referencedImage = "0190c5a4-0000-7000-8000-000000000001.webp"
+	orphanedImage   = "0190c5a4-0000-7000-8000-000000000002.webp"
+	recentImage     = "0190c5a4-0000-7000-8000-000000000003.webp"
+)
+
+func setupStorage(t *testing.T) (*local.LocalStorage, string) {
+	t.Helper()
+	root := t.TempDir()
+
+	old := time.Now().Add(-48 * time.Hour)
+	files := map[string]time.Time{
+		referencedImage: old,
+		"0190c5a4-0000-7000-8000-000000000001_thumb.webp": old,
+		orphanedImage: old,
+		"0190c5a4-0000-7000-8000-000000000002_thumb.webp":  old,
+		"0190c5a4-0000-7000-8000-000000000002_medium.webp": old,
+		recentImage:  time.Now(),
+		"README.txt": old,
+	}
+	for name, modTime := range files {
+		path := filepath.Join(root, name)
+		if err := os.WriteFile(path, []byte("image"), 0o644); err != nil {
+			t.Fatalf("failed to write %s: %v", name, err)
+		}
+		if err := os.Chtimes(path, modTime, modTime); err != nil {
+			t.Fatalf("failed to set mtime of %s: %v", name, err)
+		}
+	}
+
+	s, err := local.NewLocalStorage(root)
+	if err != nil {
+		t.Fatalf("NewLocalStorage failed: %v", err)
+	}
+	return s, root
+}
+
+func listRoot(t *testing.T, root string) []string {
+	t.Helper()
+	entries, err := os.ReadDir(root)
+	if err != nil {
+		t.Fatalf("failed to read root: %v", err)
+	}
+	var names []string
+	for _, e := range entries {
+		names = append(names, e.Name())
+	}
+	return names
+}
+
+func TestCollect(t *testing.T) {
+	s, root := setupStorage(t)
+	m := NewManagerImpl(&stubRepository{imageIDs: []string{referencedImage}}, s)
+
+	report, err := m.Collect(context.Background(), Options{GracePeriod: 24 * time.Hour})
+	if err != nil {
+		t.Fatalf("Collect failed: %v", err)
+	}
+
+	if report.Scanned != 3 || report.Referenced != 1 || report.Recent != 1 || report.Ignored != 1 {
+		t.Errorf("unexpected counts: %+v", report)
+	}
+	if report.Deleted != 1 || report.Failed != 0 || report.FreedBytes != 15 {
+		t.Errorf("unexpected deletion result: %+v", report)
+	}
+	if len(report.Orphaned) != 1 || report.Orphaned[0].FileName != orphanedImage || len(report.Orphaned[0].Files) != 3 {
+		t.Fatalf("unexpected orphaned images: %+v", report.Orphaned)
+	}
+
+	remaining := listRoot(t, root)
+	for _, name := range report.Orphaned[0].Files {
+		if slices.Contains(remaining, name) {
+			t.Errorf("expected %s to be deleted", name)
+		}
+	}
+	for _, name := range []string{referencedImage, recentImage, "README.txt"} {
+		if !slices.Contains(remaining, name) {
+			t.Errorf("expected %s to be kept", name)
+		}
+	}
+}
+
+func TestCollect_DryRun(t *testing.T) {
+	s, root := setupStorage(t)
+	m := NewManagerImpl(&stubRepository{imageIDs: []string{referencedImage}}, s)
+
+	before := listRoot(t, root)
+	report, err := m.Collect(context.Background(), Options{GracePeriod: 24 * time.Hour, DryRun: true})
+	if err != nil {
+		t.Fatalf("Collect failed: %v", err)
+	}
+
+	if !report.DryRun || report.Deleted != 0 || len(report.Orphaned) != 1 {
+		t.Errorf("unexpected dry-run report: %+v", report)
+	}
+	if after := listRoot(t, root); !slices.Equal(before, after) {
+		t.Errorf("expected no files to be deleted, before %v, after %v", before, after)
+	}
+}
+
+func TestImageFileName(t *testing.T) {
+	tests := []struct {
+		fileName string
+		want     string
+		ok       bool
+	}{
+		{orphanedImage, orphanedImage, true},
+		{"0190c5a4-0000-7000-8000-000000000002_thumb.webp", orphanedImage, true},
+		{"0190c5a4-0000-7000-8000-000000000002_medium.webp", orphanedImage, true},
+		{"0190c5a4-0000-7000-8000-000000000002_large.webp", "", false},
+		{"README.txt", "", false},
+	}
+
+	for _, tt := range tests {
+		got, ok := imageFileName(tt.fileName)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("imageFileName(%q) = (%q, %v), want (%q, %v)", tt.fileName, got, ok, tt.want, tt.ok)
+		}
+	}
+}
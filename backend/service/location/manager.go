@@ -0,0 +1,105 @@
+package location
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	PlacementStatusUncollected = "uncollected"
+	PlacementStatusCollected   = "collected"
+	PlacementStatusLost        = "lost"
+)
+
+var (
+	ErrNotFound      = errors.New("not found")
+	ErrAlreadyExists = errors.New("already exists")
+	ErrInUse         = errors.New("location is in use")
+)
+
+type Location struct {
+	ID         uuid.UUID `json:"id"`
+	Building   string    `json:"building"`
+	Floor      string    `json:"floor"`
+	BoardName  string    `json:"board_name"`
+	Latitude   *float64  `json:"latitude"`
+	Longitude  *float64  `json:"longitude"`
+	RouteOrder int       `json:"route_order"`
+}
+
+type Placement struct {
+	ID        uuid.UUID `json:"id"`
+	PosterID  uuid.UUID `json:"poster_id"`
+	Location  Location  `json:"location"`
+	Status    string    `json:"status"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// RoutePoster 回収ルート上で回収するポスター
+type RoutePoster struct {
+	PlacementID uuid.UUID `json:"placement_id"`
+	PosterID    uuid.UUID `json:"poster_id"`
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	ImageURL    string    `json:"image_url"`
+}
+
+// RouteStop 回収ルート上の掲示場所
+type RouteStop struct {
+	Location Location      `json:"location"`
+	Posters  []RoutePoster `json:"posters"`
+}
+
+// RouteArea 回収ルート上の建物ごとのまとまり
+type RouteArea struct {
+	Building string      `json:"building"`
+	Stops    []RouteStop `json:"stops"`
+}
+
+// CollectionRoute イベントのポスター回収ルート
+type CollectionRoute struct {
+	FestivalID   uuid.UUID   `json:"festival_id"`
+	FestivalName string      `json:"festival_name"`
+	Total        int         `json:"total"`
+	Areas        []RouteArea `json:"areas"`
+}
+
+type Manager interface {
+	// Create 掲示場所を作成します
+	Create(building, floor, boardName string, latitude, longitude *float64) (Location, error)
+
+	// Get 指定されたIDの掲示場所を取得します
+	Get(id uuid.UUID) (Location, error)
+
+	// List 全ての掲示場所を取得します
+	List() ([]Location, error)
+
+	// Edit 指定されたIDの掲示場所を更新します
+	Edit(id uuid.UUID, building, floor, boardName string, latitude, longitude *float64) error
+
+	// Delete 指定されたIDの掲示場所を削除します
+	// ポスターが掲示されている場合はErrInUseを返します
+	Delete(id uuid.UUID) error
+
+	// Reorder 掲示場所の巡回順を指定された順に並び替えます
+	// 指定されなかった掲示場所は、元の順序を保って末尾に並べます
+	Reorder(locationIDs []uuid.UUID) error
+
+	// Place ポスターを掲示場所に掲示します
+	// 同じ掲示場所に既に掲示されている場合はErrAlreadyExistsを返します
+	Place(posterID, locationID uuid.UUID) (Placement, error)
+
+	// ListPlacements 指定されたポスターの掲示場所一覧を取得します
+	ListPlacements(posterID uuid.UUID) ([]Placement, error)
+
+	// ChangePlacementStatus 掲示場所ごとのステータスを変更します
+	ChangePlacementStatus(placementID uuid.UUID, status string) error
+
+	// RemovePlacement ポスターを掲示場所から外します
+	RemovePlacement(placementID uuid.UUID) error
+
+	// CollectionRoute 指定されたイベントの掲示中のポスターのうち未回収の掲示を、掲示場所の巡回順に建物ごとにまとめて取得します
+	CollectionRoute(festivalID uuid.UUID) (CollectionRoute, error)
+}
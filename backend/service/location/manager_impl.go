@@ -0,0 +1,264 @@
+package location
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+
+	"github.com/Luke256/ducks/model"
+	"github.com/Luke256/ducks/repository"
+	"github.com/Luke256/ducks/service/poster"
+	"github.com/Luke256/ducks/utils/storage"
+	"github.com/google/uuid"
+)
+
+type ManagerImpl struct {
+	repo    repository.Repository
+	storage storage.Storage
+}
+
+func NewManagerImpl(repo repository.Repository, storage storage.Storage) *ManagerImpl {
+	return &ManagerImpl{
+		repo:    repo,
+		storage: storage,
+	}
+}
+
+func toLocationType(location model.Location) Location {
+	return Location{
+		ID:         location.ID,
+		Building:   location.Building,
+		Floor:      location.Floor,
+		BoardName:  location.BoardName,
+		Latitude:   location.Latitude,
+		Longitude:  location.Longitude,
+		RouteOrder: location.RouteOrder,
+	}
+}
+
+func toPlacementType(placement model.PosterPlacement) Placement {
+	return Placement{
+		ID:        placement.ID,
+		PosterID:  placement.PosterID,
+		Location:  toLocationType(placement.Location),
+		Status:    placement.Status,
+		UpdatedAt: placement.UpdatedAt,
+	}
+}
+
+func (m *ManagerImpl) Create(building, floor, boardName string, latitude, longitude *float64) (Location, error) {
+	location, err := m.repo.RegisterLocation(building, floor, boardName, latitude, longitude)
+	if err != nil {
+		return Location{}, fmt.Errorf("failed to register location: %w", err)
+	}
+
+	return toLocationType(location), nil
+}
+
+func (m *ManagerImpl) Get(id uuid.UUID) (Location, error) {
+	location, err := m.repo.GetLocationByID(id)
+	if err != nil {
+		switch err {
+		case repository.ErrNotFound:
+			return Location{}, ErrNotFound
+		default:
+			return Location{}, fmt.Errorf("failed to get location: %w", err)
+		}
+	}
+
+	return toLocationType(location), nil
+}
+
+func (m *ManagerImpl) List() ([]Location, error) {
+	locations, err := m.repo.GetLocations()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get locations: %w", err)
+	}
+
+	result := make([]Location, len(locations))
+	for i, location := range locations {
+		result[i] = toLocationType(location)
+	}
+
+	return result, nil
+}
+
+func (m *ManagerImpl) Edit(id uuid.UUID, building, floor, boardName string, latitude, longitude *float64) error {
+	err := m.repo.UpdateLocation(id, building, floor, boardName, latitude, longitude)
+	if err != nil {
+		switch err {
+		case repository.ErrNotFound:
+			return ErrNotFound
+		default:
+			return fmt.Errorf("failed to update location: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func (m *ManagerImpl) Delete(id uuid.UUID) error {
+	err := m.repo.DeleteLocation(id)
+	if err != nil {
+		switch err {
+		case repository.ErrNotFound:
+			return ErrNotFound
+		case repository.ErrForeignKey:
+			return ErrInUse
+		default:
+			return fmt.Errorf("failed to delete location: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func (m *ManagerImpl) Reorder(locationIDs []uuid.UUID) error {
+	err := m.repo.ReorderLocations(locationIDs)
+	if err != nil {
+		switch err {
+		case repository.ErrNotFound:
+			return ErrNotFound
+		default:
+			return fmt.Errorf("failed to reorder locations: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func (m *ManagerImpl) Place(posterID, locationID uuid.UUID) (Placement, error) {
+	placement, err := m.repo.RegisterPosterPlacement(posterID, locationID, PlacementStatusUncollected)
+	if err != nil {
+		switch err {
+		case repository.ErrNotFound:
+			return Placement{}, ErrNotFound
+		case repository.ErrAlreadyExists:
+			return Placement{}, ErrAlreadyExists
+		default:
+			return Placement{}, fmt.Errorf("failed to register poster placement: %w", err)
+		}
+	}
+
+	return toPlacementType(placement), nil
+}
+
+func (m *ManagerImpl) ListPlacements(posterID uuid.UUID) ([]Placement, error) {
+	placements, err := m.repo.GetPosterPlacementsByPosterID(posterID)
+	if err != nil {
+		switch err {
+		case repository.ErrNotFound:
+			return nil, ErrNotFound
+		default:
+			return nil, fmt.Errorf("failed to get poster placements: %w", err)
+		}
+	}
+
+	result := make([]Placement, len(placements))
+	for i, placement := range placements {
+		result[i] = toPlacementType(placement)
+	}
+
+	return result, nil
+}
+
+func (m *ManagerImpl) ChangePlacementStatus(placementID uuid.UUID, status string) error {
+	err := m.repo.UpdatePosterPlacementStatus(placementID, status)
+	if err != nil {
+		switch err {
+		case repository.ErrNotFound:
+			return ErrNotFound
+		default:
+			return fmt.Errorf("failed to update poster placement status: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func (m *ManagerImpl) RemovePlacement(placementID uuid.UUID) error {
+	err := m.repo.DeletePosterPlacement(placementID)
+	if err != nil {
+		switch err {
+		case repository.ErrNotFound:
+			return ErrNotFound
+		default:
+			return fmt.Errorf("failed to delete poster placement: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func (m *ManagerImpl) CollectionRoute(festivalID uuid.UUID) (CollectionRoute, error) {
+	festival, err := m.repo.GetFestivalByID(festivalID)
+	if err != nil {
+		switch err {
+		case repository.ErrNotFound:
+			return CollectionRoute{}, ErrNotFound
+		default:
+			return CollectionRoute{}, fmt.Errorf("failed to get festival: %w", err)
+		}
+	}
+
+	// 掲示中でないポスターは、回収済みか、まだ掲示されていない
+	placements, err := m.repo.GetPosterPlacementsByFestivalID(festivalID, poster.PosterStatusPosted, PlacementStatusUncollected)
+	if err != nil {
+		return CollectionRoute{}, fmt.Errorf("failed to get poster placements: %w", err)
+	}
+
+	// 巡回順、建物・階・掲示板名、ポスター名の順に並べる
+	slices.SortStableFunc(placements, func(a, b model.PosterPlacement) int {
+		return cmp.Or(
+			cmp.Compare(a.Location.RouteOrder, b.Location.RouteOrder),
+			cmp.Compare(a.Location.Building, b.Location.Building),
+			cmp.Compare(a.Location.Floor, b.Location.Floor),
+			cmp.Compare(a.Location.BoardName, b.Location.BoardName),
+			cmp.Compare(a.Poster.PosterName, b.Poster.PosterName),
+		)
+	})
+
+	route := CollectionRoute{
+		FestivalID:   festival.ID,
+		FestivalName: festival.Name,
+		Total:        len(placements),
+		Areas:        []RouteArea{},
+	}
+
+	// 同じ建物は最初に訪れる位置にまとめる
+	areaIndex := make(map[string]int)
+	stopIndex := make(map[uuid.UUID]int)
+	for _, placement := range placements {
+		i, ok := areaIndex[placement.Location.Building]
+		if !ok {
+			i = len(route.Areas)
+			areaIndex[placement.Location.Building] = i
+			route.Areas = append(route.Areas, RouteArea{
+				Building: placement.Location.Building,
+				Stops:    []RouteStop{},
+			})
+		}
+		area := &route.Areas[i]
+
+		j, ok := stopIndex[placement.LocationID]
+		if !ok {
+			j = len(area.Stops)
+			stopIndex[placement.LocationID] = j
+			area.Stops = append(area.Stops, RouteStop{
+				Location: toLocationType(placement.Location),
+				Posters:  []RoutePoster{},
+			})
+		}
+		stop := &area.Stops[j]
+
+		stop.Posters = append(stop.Posters, RoutePoster{
+			PlacementID: placement.ID,
+			PosterID:    placement.PosterID,
+			Name:        placement.Poster.PosterName,
+			Description: placement.Poster.Description,
+			ImageURL:    storage.FileURL(m.storage, placement.Poster.ImageID),
+		})
+	}
+
+	return route, nil
+}
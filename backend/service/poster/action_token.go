@@ -0,0 +1,108 @@
+package poster
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/Luke256/ducks/repository"
+	"github.com/Luke256/ducks/utils/actiontoken"
+	"github.com/google/uuid"
+)
+
+// actionTokenNote アクショントークンでステータスを変更した際に履歴へ記録するメモ
+const actionTokenNote = "changed via action token"
+
+func (m *ManagerImpl) IssueActionToken(id uuid.UUID, status string, ttl time.Duration) (ActionToken, error) {
+	// 差し戻しには理由が必要なため、トークンでは許可しない
+	if status == PosterStatusRejected {
+		return ActionToken{}, ErrReasonRequired
+	}
+
+	_, err := m.repo.GetPosterByID(id)
+	if err != nil {
+		switch err {
+		case repository.ErrNotFound:
+			return ActionToken{}, ErrNotFound
+		default:
+			return ActionToken{}, fmt.Errorf("failed to get poster by ID: %w", err)
+		}
+	}
+
+	return m.signActionToken(id, status, ttl), nil
+}
+
+func (m *ManagerImpl) IssueActionTokens(posters []Poster, status string, ttl time.Duration) ([]ActionToken, error) {
+	if status == PosterStatusRejected {
+		return nil, ErrReasonRequired
+	}
+
+	tokens := make([]ActionToken, len(posters))
+	for i, p := range posters {
+		tokens[i] = m.signActionToken(p.ID, status, ttl)
+	}
+	return tokens, nil
+}
+
+// signActionToken ポスターの存在を確かめずにトークンを発行します
+func (m *ManagerImpl) signActionToken(id uuid.UUID, status string, ttl time.Duration) ActionToken {
+	// 秒未満はトークンに含めないため切り捨てる
+	expiresAt := time.Now().Add(ttl).Truncate(time.Second)
+	token := m.signer.Sign(actiontoken.Claims{
+		PosterID:  id,
+		Status:    status,
+		ExpiresAt: expiresAt,
+	})
+
+	return ActionToken{
+		Token:     token,
+		PosterID:  id,
+		Status:    status,
+		ExpiresAt: expiresAt,
+	}
+}
+
+func (m *ManagerImpl) InspectActionToken(token string) (ActionTarget, error) {
+	claims, err := m.verifyActionToken(token)
+	if err != nil {
+		return ActionTarget{}, err
+	}
+
+	poster, err := m.Get(claims.PosterID)
+	if err != nil {
+		return ActionTarget{}, err
+	}
+
+	return ActionTarget{
+		Poster:    poster,
+		Status:    claims.Status,
+		ExpiresAt: claims.ExpiresAt,
+		Available: CanTransition(poster.Status, claims.Status),
+	}, nil
+}
+
+func (m *ManagerImpl) ApplyActionToken(token string) (Poster, error) {
+	claims, err := m.verifyActionToken(token)
+	if err != nil {
+		return Poster{}, err
+	}
+
+	// 適用後は遷移元のステータスではなくなるため、同じトークンを再度適用するとErrInvalidTransitionになる
+	if err := m.ChangeStatus(claims.PosterID, claims.Status, actionTokenNote); err != nil {
+		return Poster{}, err
+	}
+
+	return m.Get(claims.PosterID)
+}
+
+func (m *ManagerImpl) verifyActionToken(token string) (actiontoken.Claims, error) {
+	claims, err := m.signer.Verify(token, time.Now())
+	if err != nil {
+		switch err {
+		case actiontoken.ErrExpired:
+			return actiontoken.Claims{}, ErrTokenExpired
+		default:
+			return actiontoken.Claims{}, ErrInvalidToken
+		}
+	}
+	return claims, nil
+}
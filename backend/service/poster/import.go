@@ -0,0 +1,250 @@
+package poster
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"path"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/Luke256/ducks/repository"
+	"github.com/Luke256/ducks/utils/compressor"
+	"github.com/Luke256/ducks/utils/storage"
+	"github.com/google/uuid"
+)
+
+const (
+	// maxImportFiles 一度に取り込めるファイル数の上限
+	maxImportFiles = 500
+	// maxImportFileSize 取り込む画像1つあたりの展開後のサイズの上限
+	maxImportFileSize = 32 << 20
+	// maxPosterNameLength ポスター名の最大文字数
+	maxPosterNameLength = 64
+)
+
+var (
+	errInvalidPosterName = errors.New("invalid poster name")
+	errFileTooLarge      = errors.New("file too large")
+	errDuplicateFileName = errors.New("duplicate file name")
+)
+
+type manifestEntry struct {
+	name        string
+	description string
+}
+
+func (m *ManagerImpl) Import(ctx context.Context, festivalID uuid.UUID, archive io.ReaderAt, size int64, manifest io.Reader) (ImportReport, error) {
+	if _, err := m.repo.GetFestivalByID(festivalID); err != nil {
+		switch err {
+		case repository.ErrNotFound:
+			return ImportReport{}, ErrNotFound
+		default:
+			return ImportReport{}, fmt.Errorf("failed to check festival existence: %w", err)
+		}
+	}
+
+	zr, err := zip.NewReader(archive, size)
+	if err != nil {
+		return ImportReport{}, ErrInvalidArchive
+	}
+
+	entries := map[string]manifestEntry{}
+	var order []string
+	if manifest != nil {
+		entries, order, err = parseManifest(manifest)
+		if err != nil {
+			return ImportReport{}, err
+		}
+	}
+
+	var files []*zip.File
+	for _, f := range zr.File {
+		if isIgnoredArchiveEntry(f) {
+			continue
+		}
+		files = append(files, f)
+	}
+	if len(files) > maxImportFiles {
+		return ImportReport{}, ErrTooManyFiles
+	}
+
+	// 別のフォルダにある同じファイル名の画像は、マニフェストのどの行に対応するか決められない
+	fileNames := map[string]int{}
+	for _, f := range files {
+		fileNames[path.Base(f.Name)]++
+	}
+
+	report := ImportReport{Results: []ImportResult{}}
+	found := map[string]bool{}
+	for _, f := range files {
+		// 中断された場合は、それまでに登録した結果を返す
+		if err := ctx.Err(); err != nil {
+			return report, err
+		}
+
+		fileName := path.Base(f.Name)
+		found[fileName] = true
+
+		name := strings.TrimSuffix(fileName, path.Ext(fileName))
+		description := ""
+		if entry, ok := entries[fileName]; ok {
+			name = entry.name
+			description = entry.description
+		}
+
+		result := ImportResult{File: f.Name, Name: name}
+		var p Poster
+		var err error
+		if fileNames[fileName] > 1 {
+			err = errDuplicateFileName
+		} else {
+			p, err = m.importFile(ctx, festivalID, name, description, f)
+		}
+		if err != nil {
+			result.Error = importErrorMessage(err)
+			report.Failed++
+		} else {
+			result.Poster = &p
+			report.Succeeded++
+		}
+		report.Results = append(report.Results, result)
+	}
+
+	// マニフェストに記載されているがアーカイブに含まれないファイル
+	for _, fileName := range order {
+		if found[fileName] {
+			continue
+		}
+		report.Results = append(report.Results, ImportResult{
+			File:  fileName,
+			Name:  entries[fileName].name,
+			Error: "file not found in archive",
+		})
+		report.Failed++
+	}
+
+	return report, nil
+}
+
+func (m *ManagerImpl) importFile(ctx context.Context, festivalID uuid.UUID, name, description string, f *zip.File) (Poster, error) {
+	if name == "" || utf8.RuneCountInString(name) > maxPosterNameLength {
+		return Poster{}, errInvalidPosterName
+	}
+	if f.UncompressedSize64 > maxImportFileSize {
+		return Poster{}, errFileTooLarge
+	}
+
+	rc, err := f.Open()
+	if err != nil {
+		return Poster{}, err
+	}
+	defer rc.Close()
+
+	// 申告されたサイズが偽られていても上限を超えて展開しない
+	data, err := io.ReadAll(io.LimitReader(rc, maxImportFileSize+1))
+	if err != nil {
+		return Poster{}, err
+	}
+	if len(data) > maxImportFileSize {
+		return Poster{}, errFileTooLarge
+	}
+
+	return m.create(ctx, festivalID, name, description, func() (string, error) {
+		return m.storage.UploadFile(ctx, bytes.NewReader(data), storage.Metadata{Size: int64(len(data))}, m.profile)
+	})
+}
+
+func importErrorMessage(err error) string {
+	switch {
+	case errors.Is(err, ErrAlreadyExists):
+		return "poster already exists"
+	case errors.Is(err, ErrNotFound):
+		return "festival not found"
+	case errors.Is(err, errInvalidPosterName):
+		return fmt.Sprintf("name must be 1 to %d characters", maxPosterNameLength)
+	case errors.Is(err, errDuplicateFileName):
+		return "duplicate file name in archive"
+	case errors.Is(err, errFileTooLarge), errors.Is(err, compressor.ErrFileTooLarge):
+		return "file too large"
+	case errors.Is(err, compressor.ErrImageTooLarge):
+		return "image dimensions too large"
+	case errors.Is(err, compressor.ErrInvalidImage), errors.Is(err, storage.ErrUnsupportedContentType):
+		return "invalid image format"
+	default:
+		slog.Warn("failed to import poster", "error", err)
+		return "failed to import poster"
+	}
+}
+
+// isIgnoredArchiveEntry ディレクトリやOSが自動で作成するファイルかを返します
+func isIgnoredArchiveEntry(f *zip.File) bool {
+	if f.FileInfo().IsDir() {
+		return true
+	}
+	if strings.HasPrefix(f.Name, "__MACOSX/") {
+		return true
+	}
+	return strings.HasPrefix(path.Base(f.Name), ".")
+}
+
+// parseManifest file, name, description列を持つCSVを読み込みます
+// description列は省略できます
+func parseManifest(r io.Reader) (map[string]manifestEntry, []string, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	cr.TrimLeadingSpace = true
+
+	header, err := cr.Read()
+	if err != nil {
+		return nil, nil, ErrInvalidManifest
+	}
+
+	columns := map[string]int{}
+	for i, col := range header {
+		col = strings.TrimPrefix(col, "\ufeff")
+		columns[strings.ToLower(strings.TrimSpace(col))] = i
+	}
+	fileCol, ok := columns["file"]
+	if !ok {
+		return nil, nil, ErrInvalidManifest
+	}
+	nameCol, ok := columns["name"]
+	if !ok {
+		return nil, nil, ErrInvalidManifest
+	}
+	descCol, hasDesc := columns["description"]
+
+	entries := map[string]manifestEntry{}
+	var order []string
+	for {
+		record, err := cr.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, nil, ErrInvalidManifest
+		}
+
+		if fileCol >= len(record) || nameCol >= len(record) {
+			return nil, nil, ErrInvalidManifest
+		}
+		fileName := path.Base(strings.TrimSpace(record[fileCol]))
+		if _, ok := entries[fileName]; !ok {
+			order = append(order, fileName)
+		}
+
+		entry := manifestEntry{name: strings.TrimSpace(record[nameCol])}
+		if hasDesc && descCol < len(record) {
+			entry.description = record[descCol]
+		}
+		entries[fileName] = entry
+	}
+
+	return entries, order, nil
+}
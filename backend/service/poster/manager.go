@@ -1,36 +1,135 @@
 package poster
 
 import (
+	"context"
 	"errors"
-	"mime/multipart"
+	"io"
+	"time"
 
+	"github.com/Luke256/ducks/repository"
 	"github.com/Luke256/ducks/service/festival"
+	"github.com/Luke256/ducks/utils/storage"
 	"github.com/google/uuid"
 )
 
 const (
-	PosterStatusUnCollected = "uncollected"
-	PosterStatusCollected   = "collected"
-	PosterStatusLost        = "lost"
+	PosterStatusSubmitted = "submitted"
+	PosterStatusInReview  = "in_review"
+	PosterStatusApproved  = "approved"
+	PosterStatusRejected  = "rejected"
+	PosterStatusPosted    = "posted"
+	PosterStatusCollected = "collected"
+	PosterStatusLost      = "lost"
 )
 
+// placementStatusUncollected 掲示場所ごとの未回収のステータス
+// 回収済み・紛失の掲示はポスターと同じ値のステータスを使う
+const placementStatusUncollected = "uncollected"
+
 var (
 	ErrNotFound      = errors.New("not found")
 	ErrAlreadyExists = errors.New("already exists")
+	ErrInvalidPeriod = errors.New("end date is before start date")
+
+	ErrInvalidTransition = errors.New("invalid status transition")
+	ErrReasonRequired    = errors.New("rejection reason is required")
+
+	ErrInvalidToken = errors.New("invalid action token")
+	ErrTokenExpired = errors.New("action token has expired")
+
+	ErrInvalidArchive  = errors.New("invalid zip archive")
+	ErrInvalidManifest = errors.New("invalid manifest")
+	ErrTooManyFiles    = errors.New("too many files in archive")
 )
 
 type Poster struct {
-	ID          uuid.UUID         `json:"id"`
-	Name        string            `json:"name"`
-	Description string            `json:"description"`
-	ImageURL    string            `json:"image_url"`
-	Status      string            `json:"status"`
-	Festival    festival.Festival `json:"festival"`
+	ID               uuid.UUID         `json:"id"`
+	Name             string            `json:"name"`
+	Description      string            `json:"description"`
+	ImageURL         string            `json:"image_url"`
+	Status           string            `json:"status"`
+	RejectionReason  string            `json:"rejection_reason,omitempty"`
+	PostingStartDate *time.Time        `json:"posting_start_date"`
+	PostingEndDate   *time.Time        `json:"posting_end_date"`
+	Overdue          bool              `json:"overdue"`
+	Festival         festival.Festival `json:"festival"`
+	DeletedAt        *time.Time        `json:"deleted_at,omitempty"`
+}
+
+type StatusChange struct {
+	PreviousStatus string    `json:"previous_status"`
+	NewStatus      string    `json:"new_status"`
+	Note           string    `json:"note"`
+	ChangedAt      time.Time `json:"changed_at"`
+}
+
+// ImportResult 一括登録したファイルごとの結果
+type ImportResult struct {
+	File   string  `json:"file"`
+	Name   string  `json:"name"`
+	Poster *Poster `json:"poster,omitempty"`
+	Error  string  `json:"error,omitempty"`
+}
+
+// ImportReport 一括登録の結果
+type ImportReport struct {
+	Succeeded int            `json:"succeeded"`
+	Failed    int            `json:"failed"`
+	Results   []ImportResult `json:"results"`
+}
+
+const (
+	SortName             = repository.PosterSortName
+	SortPostingStartDate = repository.PosterSortPostingStartDate
+	SortPostingEndDate   = repository.PosterSortPostingEndDate
+)
+
+// SearchQuery ポスターの検索条件
+// 空文字やゼロ値を指定した条件では絞り込みません
+type SearchQuery struct {
+	Status string
+	Name   string
+	Sort   string
+	Desc   bool
+	Limit  int
+	Offset int
+}
+
+// SearchResult ポスターの検索結果
+// Totalはページ分割する前の件数です
+type SearchResult struct {
+	Posters []Poster `json:"posters"`
+	Total   int64    `json:"total"`
+}
+
+// ActionToken ログインせずにポスターのステータスを変更するための署名付きトークン
+type ActionToken struct {
+	Token     string    `json:"token"`
+	PosterID  uuid.UUID `json:"poster_id"`
+	Status    string    `json:"status"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// ActionTarget アクショントークンが許可する操作
+// Availableは現在のステータスから操作を適用できるかを表します
+type ActionTarget struct {
+	Poster    Poster    `json:"poster"`
+	Status    string    `json:"status"`
+	ExpiresAt time.Time `json:"expires_at"`
+	Available bool      `json:"available"`
 }
 
 type Manager interface {
 	// Create ポスターを作成します
-	Create(name string, festivalID uuid.UUID, description string, image *multipart.FileHeader) (Poster, error)
+	// imageがnilの場合は画像を設定せずに作成し、後からUpdateImageで設定します
+	Create(ctx context.Context, name string, festivalID uuid.UUID, description string, image io.Reader, meta storage.Metadata) (Poster, error)
+
+	// Import ZIPアーカイブに含まれる画像からポスターを一括で作成します
+	// manifestにCSVを指定した場合、ファイルごとのポスター名と説明を指定できます
+	// マニフェストとはファイル名で対応付けるため、同じファイル名の画像は登録せずにエラーとします
+	// 一部のファイルの登録に失敗しても処理を続け、ファイルごとの結果を返します
+	// ctxがキャンセルされた場合は、それまでの結果とともにエラーを返します
+	Import(ctx context.Context, festivalID uuid.UUID, archive io.ReaderAt, size int64, manifest io.Reader) (ImportReport, error)
 
 	// Get 指定されたIDのポスターを取得します
 	Get(id uuid.UUID) (Poster, error)
@@ -38,15 +137,59 @@ type Manager interface {
 	// GetByFestival 指定されたイベントIDのポスターを取得します
 	GetByFestival(festivalID uuid.UUID) ([]Poster, error)
 
+	// Search 指定されたイベントのポスターを条件で絞り込み、並び替えて取得します
+	Search(festivalID uuid.UUID, query SearchQuery) (SearchResult, error)
+
 	// GetByName 指定されたイベントの、ポスター名でポスターを取得します
 	GetByName(festivalID uuid.UUID, name string) (Poster, error)
 
 	// Edit 指定されたIDのポスター情報を更新します
 	Edit(id uuid.UUID, name, description string) error
 
-	// ChangeStatus 指定されたIDのポスターのステータスを変更します
-	ChangeStatus(id uuid.UUID, status string) error
+	// SetPostingPeriod 指定されたIDのポスターの掲示期間を設定します
+	// nilを指定した日時はイベントの開催期間に従います
+	SetPostingPeriod(id uuid.UUID, startDate, endDate *time.Time) error
+
+	// ListOverdue 掲示期間を過ぎても回収されていないポスターを、終了日時の古い順に取得します
+	// festivalIDがnilの場合、全てのイベントのポスターを対象にします
+	ListOverdue(festivalID *uuid.UUID) ([]Poster, error)
+
+	// UpdateImage 指定されたIDのポスターの画像を差し替えます
+	// 差し替え前の画像は、他のポスターから参照されていなければストレージから削除します
+	UpdateImage(ctx context.Context, id uuid.UUID, image io.Reader, meta storage.Metadata) error
+
+	// ChangeStatus 指定されたIDのポスターのステータスを変更し、変更履歴に記録します
+	// 許可されていない遷移の場合はErrInvalidTransitionを返します
+	// 差し戻す場合はnoteに理由を指定する必要があり、指定されていない場合はErrReasonRequiredを返します
+	ChangeStatus(id uuid.UUID, status, note string) error
+
+	// IssueActionToken 指定されたIDのポスターのステータスをstatusに変更できる、ttlの間有効なトークンを発行します
+	// 差し戻しは理由が必要なため、statusに指定するとErrReasonRequiredを返します
+	IssueActionToken(id uuid.UUID, status string, ttl time.Duration) (ActionToken, error)
+
+	// IssueActionTokens 取得済みのポスターそれぞれについて、IssueActionTokenと同じトークンを発行します
+	// ポスターを取得し直さないため、一覧から多数のトークンを発行する場合に使います
+	IssueActionTokens(posters []Poster, status string, ttl time.Duration) ([]ActionToken, error)
+
+	// InspectActionToken トークンを検証し、ステータスを変更せずに対象のポスターと操作を返します
+	// 改ざんされたトークンの場合はErrInvalidToken、有効期限を過ぎている場合はErrTokenExpiredを返します
+	InspectActionToken(token string) (ActionTarget, error)
+
+	// ApplyActionToken トークンを検証し、許可されたステータスへの変更を適用します
+	// 現在のステータスから遷移できない場合はErrInvalidTransitionを返すため、同じトークンは一度しか適用できません
+	ApplyActionToken(token string) (Poster, error)
+
+	// GetStatusHistory 指定されたIDのポスターのステータス変更履歴を古い順に取得します
+	GetStatusHistory(id uuid.UUID) ([]StatusChange, error)
 
 	// Delete 指定されたIDのポスターを削除します
+	// 削除したポスターはRestoreで復元できます
 	Delete(id uuid.UUID) error
+
+	// ListDeleted 削除済みのポスターを取得します
+	ListDeleted() ([]Poster, error)
+
+	// Restore 指定されたIDの削除済みのポスターを復元します
+	// 同じイベントに同名のポスターが存在する場合はErrAlreadyExistsを返します
+	Restore(id uuid.UUID) (Poster, error)
 }
@@ -1,11 +1,18 @@
 package poster
 
 import (
+	"context"
 	"fmt"
-	"mime/multipart"
+	"io"
+	"log/slog"
+	"strings"
+	"time"
 
+	"github.com/Luke256/ducks/model"
 	"github.com/Luke256/ducks/repository"
 	"github.com/Luke256/ducks/service/festival"
+	"github.com/Luke256/ducks/utils/actiontoken"
+	"github.com/Luke256/ducks/utils/compressor"
 	"github.com/Luke256/ducks/utils/storage"
 	"github.com/google/uuid"
 )
@@ -13,23 +20,73 @@ import (
 type ManagerImpl struct {
 	repo    repository.Repository
 	storage storage.Storage
+	signer  *actiontoken.Signer
+	profile compressor.Profile
 }
 
-func NewManagerImpl(repo repository.Repository, storage storage.Storage) *ManagerImpl {
-	return &ManagerImpl{repo: repo, storage: storage}
+// NewManagerImpl ポスターの画像はprofileに従って圧縮します
+func NewManagerImpl(repo repository.Repository, storage storage.Storage, signer *actiontoken.Signer, profile compressor.Profile) *ManagerImpl {
+	return &ManagerImpl{repo: repo, storage: storage, signer: signer, profile: profile}
 }
 
-func (m *ManagerImpl) Create(name string, festivalID uuid.UUID, description string, image *multipart.FileHeader) (_ Poster, err error) {
-	imageID, err := m.storage.UploadFile(image)
-	if err != nil {
-		return Poster{}, fmt.Errorf("failed to upload image: %w", err)
+func toFestivalType(fes model.Festival) festival.Festival {
+	return festival.Festival{
+		ID:          fes.ID,
+		Name:        fes.Name,
+		Description: fes.Description,
+		StartDate:   fes.StartDate,
+		EndDate:     fes.EndDate,
+		Status:      fes.Status,
 	}
-	defer func() {
-		if err != nil {
-			_ = m.storage.DeleteFile(imageID)
+}
+
+func (m *ManagerImpl) toPosterType(p model.Poster) Poster {
+	var deletedAt *time.Time
+	if p.DeletedAt.Valid {
+		deletedAt = &p.DeletedAt.Time
+	}
+
+	// 掲示期間が設定されていない場合はイベントの開催期間に従う
+	startDate := p.PostingStartDate
+	if startDate == nil {
+		startDate = p.Festival.StartDate
+	}
+	endDate := p.PostingEndDate
+	if endDate == nil {
+		endDate = p.Festival.EndDate
+	}
+
+	return Poster{
+		ID:               p.ID,
+		Name:             p.PosterName,
+		Description:      p.Description,
+		ImageURL:         storage.FileURL(m.storage, p.ImageID),
+		Status:           p.Status,
+		RejectionReason:  p.RejectionReason,
+		PostingStartDate: startDate,
+		PostingEndDate:   endDate,
+		Overdue:          isOverdue(p.Status, endDate, time.Now()),
+		Festival:         toFestivalType(p.Festival),
+		DeletedAt:        deletedAt,
+	}
+}
+
+// isOverdue 掲示期間を過ぎても回収されていないかを返します
+func isOverdue(status string, endDate *time.Time, now time.Time) bool {
+	return status == PosterStatusPosted && endDate != nil && endDate.Before(now)
+}
+
+func (m *ManagerImpl) Create(ctx context.Context, name string, festivalID uuid.UUID, description string, image io.Reader, meta storage.Metadata) (Poster, error) {
+	return m.create(ctx, festivalID, name, description, func() (string, error) {
+		if image == nil {
+			return "", nil
 		}
-	}()
+		return m.storage.UploadFile(ctx, image, meta, m.profile)
+	})
+}
 
+// create 重複とイベントの存在を確認したうえで画像をアップロードし、ポスターを登録します
+func (m *ManagerImpl) create(ctx context.Context, festivalID uuid.UUID, name, description string, upload func() (string, error)) (_ Poster, err error) {
 	// duplicate check
 	_, err = m.repo.GetPosterByFestivalIDAndPosterName(festivalID, name)
 	if err == nil {
@@ -48,19 +105,24 @@ func (m *ManagerImpl) Create(name string, festivalID uuid.UUID, description stri
 		return Poster{}, fmt.Errorf("failed to check festival existence: %w", err)
 	}
 
+	imageID, err := upload()
+	if err != nil {
+		return Poster{}, fmt.Errorf("failed to upload image: %w", err)
+	}
+	defer func() {
+		if err != nil && imageID != "" {
+			// 中断された場合もアップロードした画像は削除する
+			_ = m.storage.DeleteFile(context.WithoutCancel(ctx), imageID)
+		}
+	}()
+
 	poster, err := m.repo.RegisterPoster(festivalID, name, description, imageID)
 	if err != nil {
 		return Poster{}, fmt.Errorf("failed to register poster: %w", err)
 	}
 
-	return Poster{
-		ID:          poster.ID,
-		Name:        poster.PosterName,
-		Description: poster.Description,
-		ImageURL:    m.storage.GetFileURL(poster.ImageID),
-		Status:      poster.Status,
-		Festival:    festival.Festival{ID: fes.ID, Name: fes.Name, Description: fes.Description},
-	}, nil
+	poster.Festival = fes
+	return m.toPosterType(poster), nil
 }
 
 func (m *ManagerImpl) Get(id uuid.UUID) (Poster, error) {
@@ -74,14 +136,7 @@ func (m *ManagerImpl) Get(id uuid.UUID) (Poster, error) {
 		}
 	}
 
-	return Poster{
-		ID:          poster.ID,
-		Name:        poster.PosterName,
-		Description: poster.Description,
-		ImageURL:    m.storage.GetFileURL(poster.ImageID),
-		Status:      poster.Status,
-		Festival:    festival.Festival{ID: poster.Festival.ID, Name: poster.Festival.Name, Description: poster.Festival.Description},
-	}, nil
+	return m.toPosterType(poster), nil
 }
 
 func (m *ManagerImpl) GetByFestival(festivalID uuid.UUID) ([]Poster, error) {
@@ -97,19 +152,34 @@ func (m *ManagerImpl) GetByFestival(festivalID uuid.UUID) ([]Poster, error) {
 
 	result := make([]Poster, len(posters))
 	for i, p := range posters {
-		result[i] = Poster{
-			ID:          p.ID,
-			Name:        p.PosterName,
-			Description: p.Description,
-			ImageURL:    m.storage.GetFileURL(p.ImageID),
-			Status:      p.Status,
-			Festival:    festival.Festival{ID: p.Festival.ID, Name: p.Festival.Name, Description: p.Festival.Description},
-		}
+		result[i] = m.toPosterType(p)
 	}
 
 	return result, nil
 }
 
+func (m *ManagerImpl) Search(festivalID uuid.UUID, query SearchQuery) (SearchResult, error) {
+	posters, total, err := m.repo.QueryPosters(repository.PosterQuery{
+		FestivalID: festivalID,
+		Status:     query.Status,
+		Name:       query.Name,
+		Sort:       query.Sort,
+		Desc:       query.Desc,
+		Limit:      query.Limit,
+		Offset:     query.Offset,
+	})
+	if err != nil {
+		return SearchResult{}, fmt.Errorf("failed to query posters: %w", err)
+	}
+
+	result := make([]Poster, len(posters))
+	for i, p := range posters {
+		result[i] = m.toPosterType(p)
+	}
+
+	return SearchResult{Posters: result, Total: total}, nil
+}
+
 func (m *ManagerImpl) GetByName(festivalID uuid.UUID, name string) (Poster, error) {
 	poster, err := m.repo.GetPosterByFestivalIDAndPosterName(festivalID, name)
 	if err != nil {
@@ -121,14 +191,7 @@ func (m *ManagerImpl) GetByName(festivalID uuid.UUID, name string) (Poster, erro
 		}
 	}
 
-	return Poster{
-		ID:          poster.ID,
-		Name:        poster.PosterName,
-		Description: poster.Description,
-		ImageURL:    m.storage.GetFileURL(poster.ImageID),
-		Status:      poster.Status,
-		Festival:    festival.Festival{ID: poster.Festival.ID, Name: poster.Festival.Name, Description: poster.Festival.Description},
-	}, nil
+	return m.toPosterType(poster), nil
 }
 
 func (m *ManagerImpl) Edit(id uuid.UUID, name, description string) error {
@@ -144,12 +207,47 @@ func (m *ManagerImpl) Edit(id uuid.UUID, name, description string) error {
 	return nil
 }
 
-func (m *ManagerImpl) ChangeStatus(id uuid.UUID, status string) error {
-	err := m.repo.UpdatePosterStatus(id, status)
+func (m *ManagerImpl) ChangeStatus(id uuid.UUID, status, note string) error {
+	if status == PosterStatusRejected && strings.TrimSpace(note) == "" {
+		return ErrReasonRequired
+	}
+
+	poster, err := m.repo.GetPosterByID(id)
 	if err != nil {
 		switch err {
 		case repository.ErrNotFound:
 			return ErrNotFound
+		default:
+			return fmt.Errorf("failed to get poster by ID: %w", err)
+		}
+	}
+
+	if !CanTransition(poster.Status, status) {
+		return ErrInvalidTransition
+	}
+
+	change := repository.PosterStatusChange{
+		From: poster.Status,
+		To:   status,
+		Note: note,
+	}
+	if status == PosterStatusRejected {
+		change.RejectionReason = note
+	}
+	// ポスターごと回収・紛失した場合は、未回収の掲示もあわせて回収・紛失とする
+	if status == PosterStatusCollected || status == PosterStatusLost {
+		change.PlacementFrom = placementStatusUncollected
+		change.PlacementTo = status
+	}
+
+	err = m.repo.UpdatePosterStatus(id, change)
+	if err != nil {
+		switch err {
+		case repository.ErrNotFound:
+			return ErrNotFound
+		case repository.ErrConflict:
+			// 確認後に他の操作でステータスが変わった
+			return ErrInvalidTransition
 		default:
 			return fmt.Errorf("failed to update poster status: %w", err)
 		}
@@ -157,10 +255,64 @@ func (m *ManagerImpl) ChangeStatus(id uuid.UUID, status string) error {
 	return nil
 }
 
-func (m *ManagerImpl) Delete(id uuid.UUID) error {
-	// delete poster image from storage
-	poster, err := m.repo.GetPosterByID(id)
+func (m *ManagerImpl) GetStatusHistory(id uuid.UUID) ([]StatusChange, error) {
+	histories, err := m.repo.GetPosterStatusHistory(id)
+	if err != nil {
+		switch err {
+		case repository.ErrNotFound:
+			return nil, ErrNotFound
+		default:
+			return nil, fmt.Errorf("failed to get poster status history: %w", err)
+		}
+	}
+
+	result := make([]StatusChange, len(histories))
+	for i, h := range histories {
+		result[i] = StatusChange{
+			PreviousStatus: h.PreviousStatus,
+			NewStatus:      h.NewStatus,
+			Note:           h.Note,
+			ChangedAt:      h.CreatedAt,
+		}
+	}
+
+	return result, nil
+}
+
+func (m *ManagerImpl) SetPostingPeriod(id uuid.UUID, startDate, endDate *time.Time) error {
+	if startDate != nil && endDate != nil && endDate.Before(*startDate) {
+		return ErrInvalidPeriod
+	}
+
+	err := m.repo.UpdatePosterPostingPeriod(id, startDate, endDate)
 	if err != nil {
+		switch err {
+		case repository.ErrNotFound:
+			return ErrNotFound
+		default:
+			return fmt.Errorf("failed to update poster posting period: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func (m *ManagerImpl) ListOverdue(festivalID *uuid.UUID) ([]Poster, error) {
+	posters, err := m.repo.GetOverduePosters(festivalID, PosterStatusPosted, time.Now())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get overdue posters: %w", err)
+	}
+
+	result := make([]Poster, len(posters))
+	for i, p := range posters {
+		result[i] = m.toPosterType(p)
+	}
+
+	return result, nil
+}
+
+func (m *ManagerImpl) UpdateImage(ctx context.Context, id uuid.UUID, image io.Reader, meta storage.Metadata) (err error) {
+	if _, err := m.repo.GetPosterByID(id); err != nil {
 		switch err {
 		case repository.ErrNotFound:
 			return ErrNotFound
@@ -169,12 +321,53 @@ func (m *ManagerImpl) Delete(id uuid.UUID) error {
 		}
 	}
 
-	err = m.storage.DeleteFile(poster.ImageID)
+	imageID, err := m.storage.UploadFile(ctx, image, meta, m.profile)
+	if err != nil {
+		return fmt.Errorf("failed to upload new image: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			_ = m.storage.DeleteFile(context.WithoutCancel(ctx), imageID)
+		}
+	}()
+
+	oldImageID, err := m.repo.UpdatePosterImage(id, imageID)
+	if err != nil {
+		switch err {
+		case repository.ErrNotFound:
+			return ErrNotFound
+		default:
+			return fmt.Errorf("failed to update poster image: %w", err)
+		}
+	}
+
+	// 差し替え後は新しい画像が参照されているため、古い画像の削除に失敗しても処理は失敗させない
+	if oldImageID != "" {
+		if err := m.deleteUnusedImage(context.WithoutCancel(ctx), oldImageID); err != nil {
+			slog.Warn("failed to delete old poster image", "image_id", oldImageID, "error", err)
+		}
+	}
+
+	return nil
+}
+
+// deleteUnusedImage どのポスターからも参照されていない画像をストレージから削除します
+// 複製したポスターや削除済みのポスターが同じ画像を参照している場合は残しておきます
+func (m *ManagerImpl) deleteUnusedImage(ctx context.Context, imageID string) error {
+	count, err := m.repo.CountPostersByImageID(imageID)
 	if err != nil {
-		return fmt.Errorf("failed to delete poster image from storage: %w", err)
+		return fmt.Errorf("failed to count posters referencing image: %w", err)
+	}
+	if count > 0 {
+		return nil
 	}
 
-	err = m.repo.DeletePoster(id)
+	return m.storage.DeleteFile(ctx, imageID)
+}
+
+func (m *ManagerImpl) Delete(id uuid.UUID) error {
+	// ポスターは論理削除されるため、復元できるよう画像は残しておく
+	err := m.repo.DeletePoster(id)
 	if err != nil {
 		switch err {
 		case repository.ErrNotFound:
@@ -185,3 +378,33 @@ func (m *ManagerImpl) Delete(id uuid.UUID) error {
 	}
 	return nil
 }
+
+func (m *ManagerImpl) ListDeleted() ([]Poster, error) {
+	posters, err := m.repo.GetDeletedPosters()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get deleted posters: %w", err)
+	}
+
+	result := make([]Poster, len(posters))
+	for i, p := range posters {
+		result[i] = m.toPosterType(p)
+	}
+
+	return result, nil
+}
+
+func (m *ManagerImpl) Restore(id uuid.UUID) (Poster, error) {
+	poster, err := m.repo.RestorePoster(id)
+	if err != nil {
+		switch err {
+		case repository.ErrNotFound:
+			return Poster{}, ErrNotFound
+		case repository.ErrAlreadyExists:
+			return Poster{}, ErrAlreadyExists
+		default:
+			return Poster{}, fmt.Errorf("failed to restore poster: %w", err)
+		}
+	}
+
+	return m.toPosterType(poster), nil
+}
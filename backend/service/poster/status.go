@@ -0,0 +1,33 @@
+package poster
+
+import "slices"
+
+// transitions ステータスごとの遷移可能なステータス
+//
+// 提出 → 審査中 → 承認/差し戻し → 掲示中 → 回収済み の順に遷移する
+// 差し戻されたポスターは再提出でき、紛失したポスターが見つかった場合は回収済みにできる
+var transitions = map[string][]string{
+	PosterStatusSubmitted: {PosterStatusInReview},
+	PosterStatusInReview:  {PosterStatusApproved, PosterStatusRejected},
+	PosterStatusApproved:  {PosterStatusPosted},
+	PosterStatusRejected:  {PosterStatusSubmitted},
+	PosterStatusPosted:    {PosterStatusCollected, PosterStatusLost},
+	PosterStatusLost:      {PosterStatusCollected},
+	PosterStatusCollected: {},
+}
+
+// Statuses ポスターの全てのステータス
+var Statuses = []string{
+	PosterStatusSubmitted,
+	PosterStatusInReview,
+	PosterStatusApproved,
+	PosterStatusRejected,
+	PosterStatusPosted,
+	PosterStatusCollected,
+	PosterStatusLost,
+}
+
+// CanTransition fromからtoへステータスを変更できるかを返します
+func CanTransition(from, to string) bool {
+	return slices.Contains(transitions[from], to)
+}
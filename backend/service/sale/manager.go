@@ -20,7 +20,9 @@ var (
 
 type Manager interface {
 	// Create 購入記録を作成します
-	Create(saleData ...SaleRecord) ([]SaleRecord, error)
+	// イベントが終了(closed)またはアーカイブ(archived)されている場合、
+	// overrideがtrueでない限りfestival.ErrLockedを返します
+	Create(override bool, saleData ...SaleRecord) ([]SaleRecord, error)
 	
 	// Get 購入記録をIDで取得します
 	Get(id uuid.UUID) (SaleRecord, error)
@@ -3,6 +3,7 @@ package sale
 import (
 	"github.com/Luke256/ducks/model"
 	"github.com/Luke256/ducks/repository"
+	"github.com/Luke256/ducks/service/festival"
 	festivalstock "github.com/Luke256/ducks/service/festival_stock"
 
 	"github.com/google/uuid"
@@ -25,9 +26,24 @@ func (m *ManagerImpl) toSaleRecordType(record model.SaleRecord) SaleRecord {
 	}
 }
 
-func (m *ManagerImpl) Create(saleData ...SaleRecord) ([]SaleRecord, error) {
+func (m *ManagerImpl) Create(override bool, saleData ...SaleRecord) ([]SaleRecord, error) {
 	repoSaleData := make([]repository.SaleData, len(saleData))
 	for i, data := range saleData {
+		// 削除済みのアイテムは外部キー制約では検出できないため、ここで確認する
+		stock, err := m.repo.GetFestivalStockByID(data.StockID)
+		if err != nil {
+			switch err {
+			case repository.ErrNotFound:
+				return nil, festivalstock.ErrNotFound
+			default:
+				return nil, err
+			}
+		}
+		// 削除されたイベントは読み込まれずゼロ値になるため、終了したイベントと同様に扱う
+		if (stock.Festival.ID == uuid.Nil || festival.IsLocked(stock.Festival.Status)) && !override {
+			return nil, festival.ErrLocked
+		}
+
 		repoSaleData[i] = repository.SaleData{
 			FestivalStockID: data.StockID,
 			Quantity:        data.Quantity,
@@ -1,27 +1,32 @@
 package stockitem
 
 import (
+	"context"
 	"errors"
-	"mime/multipart"
+	"io"
+	"time"
 
+	"github.com/Luke256/ducks/utils/storage"
 	"github.com/google/uuid"
 )
 
 type StockItem struct {
-	ID          uuid.UUID `json:"id"`
-	Name        string    `json:"name"`
-	Description string    `json:"description"`
-	Category    string    `json:"category"`
-	ImageURL    string    `json:"image_url"`
+	ID          uuid.UUID  `json:"id"`
+	Name        string     `json:"name"`
+	Description string     `json:"description"`
+	Category    string     `json:"category"`
+	ImageURL    string     `json:"image_url"`
+	DeletedAt   *time.Time `json:"deleted_at,omitempty"`
 }
 
 var (
-	ErrNotFound      = errors.New("not found")
+	ErrNotFound = errors.New("not found")
 )
 
 type Manager interface {
 	// Create アイテムを作成します
-	Create(name string, description string, category string, image *multipart.FileHeader) (StockItem, error)
+	// imageがnilの場合は画像を設定せずに作成し、後からUpdateImageで設定します
+	Create(ctx context.Context, name string, description string, category string, image io.Reader, meta storage.Metadata) (StockItem, error)
 
 	// Get 指定されたIDのアイテムを取得します
 	Get(id uuid.UUID) (StockItem, error)
@@ -34,8 +39,15 @@ type Manager interface {
 	Edit(id uuid.UUID, name string, description string, category string) error
 
 	// UpdateImage 指定されたIDのアイテムの画像を更新します
-	UpdateImage(id uuid.UUID, image *multipart.FileHeader) error
+	UpdateImage(ctx context.Context, id uuid.UUID, image io.Reader, meta storage.Metadata) error
 
 	// Delete 指定されたIDのアイテムを削除します
+	// 削除したアイテムはRestoreで復元できます
 	Delete(id uuid.UUID) error
-}
\ No newline at end of file
+
+	// ListDeleted 削除済みのアイテムを取得します
+	ListDeleted() ([]StockItem, error)
+
+	// Restore 指定されたIDの削除済みのアイテムを復元します
+	Restore(id uuid.UUID) error
+}
@@ -1,11 +1,15 @@
 package stockitem
 
 import (
+	"context"
 	"fmt"
-	"mime/multipart"
+	"io"
+	"log/slog"
+	"time"
 
 	"github.com/Luke256/ducks/model"
 	"github.com/Luke256/ducks/repository"
+	"github.com/Luke256/ducks/utils/compressor"
 	"github.com/Luke256/ducks/utils/storage"
 
 	"github.com/google/uuid"
@@ -14,32 +18,43 @@ import (
 type ManagerImpl struct {
 	repo    repository.Repository
 	storage storage.Storage
+	profile compressor.Profile
 }
 
-func NewManagerImpl(repo repository.Repository, storage storage.Storage) *ManagerImpl {
-	return &ManagerImpl{repo: repo, storage: storage}
+// NewManagerImpl アイテムの画像はprofileに従って圧縮します
+func NewManagerImpl(repo repository.Repository, storage storage.Storage, profile compressor.Profile) *ManagerImpl {
+	return &ManagerImpl{repo: repo, storage: storage, profile: profile}
 }
 
 func (m *ManagerImpl) toStockItemType(item model.StockItem) StockItem {
+	var deletedAt *time.Time
+	if item.DeletedAt.Valid {
+		deletedAt = &item.DeletedAt.Time
+	}
+
 	return StockItem{
 		ID:          item.ID,
 		Name:        item.Name,
 		Description: item.Description,
 		Category:    item.Category,
-		ImageURL:    m.storage.GetFileURL(item.ImageID),
+		ImageURL:    storage.FileURL(m.storage, item.ImageID),
+		DeletedAt:   deletedAt,
 	}
 }
 
-func (m *ManagerImpl) Create(name string, description string, category string, image *multipart.FileHeader) (_ StockItem, err error) {
-	imageID, err := m.storage.UploadFile(image)
-	if err != nil {
-		return StockItem{}, fmt.Errorf("failed to upload image: %w", err)
-	}
-	defer func() {
+func (m *ManagerImpl) Create(ctx context.Context, name string, description string, category string, image io.Reader, meta storage.Metadata) (_ StockItem, err error) {
+	var imageID string
+	if image != nil {
+		imageID, err = m.storage.UploadFile(ctx, image, meta, m.profile)
 		if err != nil {
-			_ = m.storage.DeleteFile(imageID)
+			return StockItem{}, fmt.Errorf("failed to upload image: %w", err)
 		}
-	}()
+		defer func() {
+			if err != nil {
+				_ = m.storage.DeleteFile(context.WithoutCancel(ctx), imageID)
+			}
+		}()
+	}
 
 	item, err := m.repo.RegisterStockItem(name, description, category, imageID)
 	if err != nil {
@@ -96,7 +111,7 @@ func (m *ManagerImpl) Edit(id uuid.UUID, name string, description string, catego
 	return nil
 }
 
-func (m *ManagerImpl) UpdateImage(id uuid.UUID, image *multipart.FileHeader) (err error) {
+func (m *ManagerImpl) UpdateImage(ctx context.Context, id uuid.UUID, image io.Reader, meta storage.Metadata) (err error) {
 	item, err := m.repo.GetStockItemByID(id)
 	if err != nil {
 		switch err {
@@ -107,17 +122,13 @@ func (m *ManagerImpl) UpdateImage(id uuid.UUID, image *multipart.FileHeader) (er
 		}
 	}
 
-	if err := m.storage.DeleteFile(item.ImageID); err != nil {
-		return fmt.Errorf("failed to delete old image from storage: %w", err)
-	}
-
-	imageID, err := m.storage.UploadFile(image)
+	imageID, err := m.storage.UploadFile(ctx, image, meta, m.profile)
 	if err != nil {
 		return fmt.Errorf("failed to upload new image: %w", err)
 	}
 	defer func() {
 		if err != nil {
-			_ = m.storage.DeleteFile(imageID)
+			_ = m.storage.DeleteFile(context.WithoutCancel(ctx), imageID)
 		}
 	}()
 
@@ -126,34 +137,55 @@ func (m *ManagerImpl) UpdateImage(id uuid.UUID, image *multipart.FileHeader) (er
 		return fmt.Errorf("failed to update stock item image: %w", err)
 	}
 
+	// 差し替え後は新しい画像が参照されているため、古い画像の削除に失敗しても処理は失敗させない
+	if item.ImageID != "" {
+		if err := m.storage.DeleteFile(context.WithoutCancel(ctx), item.ImageID); err != nil {
+			slog.Warn("failed to delete old stock item image", "image_id", item.ImageID, "error", err)
+		}
+	}
+
 	return nil
 }
 
 func (m *ManagerImpl) Delete(id uuid.UUID) error {
-	item, err := m.repo.GetStockItemByID(id)
+	// アイテムは論理削除されるため、復元できるよう画像は残しておく
+	err := m.repo.DeleteStockItem(id)
 	if err != nil {
 		switch err {
 		case repository.ErrNotFound:
 			return ErrNotFound
 		default:
-			return fmt.Errorf("failed to get stock item: %w", err)
+			return fmt.Errorf("failed to delete stock item: %w", err)
 		}
 	}
 
-	err = m.storage.DeleteFile(item.ImageID)
+	return nil
+}
+
+func (m *ManagerImpl) ListDeleted() ([]StockItem, error) {
+	items, err := m.repo.GetDeletedStockItems()
 	if err != nil {
-		return fmt.Errorf("failed to delete image from storage: %w", err)
+		return nil, fmt.Errorf("failed to get deleted stock items: %w", err)
+	}
+
+	result := make([]StockItem, len(items))
+	for i, item := range items {
+		result[i] = m.toStockItemType(item)
 	}
 
-	err = m.repo.DeleteStockItem(id)
+	return result, nil
+}
+
+func (m *ManagerImpl) Restore(id uuid.UUID) error {
+	err := m.repo.RestoreStockItem(id)
 	if err != nil {
 		switch err {
 		case repository.ErrNotFound:
 			return ErrNotFound
 		default:
-			return fmt.Errorf("failed to delete stock item: %w", err)
+			return fmt.Errorf("failed to restore stock item: %w", err)
 		}
 	}
 
 	return nil
-}
\ No newline at end of file
+}
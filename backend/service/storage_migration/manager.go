@@ -0,0 +1,78 @@
+package storagemigration
+
+import (
+	"context"
+	"errors"
+)
+
+var (
+	// ErrVerificationFailed 移行先に保存したファイルの内容が移行元と一致しない
+	ErrVerificationFailed = errors.New("verification failed")
+)
+
+// ファイルごとの移行の結果
+const (
+	// StatusCopied 移行先にコピーして内容を確かめた
+	StatusCopied = "copied"
+	// StatusSkipped 移行先にすでに同じファイルがあるため、コピーしなかった
+	StatusSkipped = "skipped"
+	// StatusPending DryRunのため、コピーしなかった
+	StatusPending = "pending"
+	// StatusMissing 参照されている画像が移行元にない
+	StatusMissing = "missing"
+	// StatusFailed コピーか内容の確認に失敗した
+	StatusFailed = "failed"
+)
+
+// Options 移行の設定
+type Options struct {
+	// DryRun trueの場合はコピーせず、コピーするファイルを報告するだけにします
+	DryRun bool
+	// VerifyExisting trueの場合は移行先にすでにあるファイルも内容を比較し、異なればコピーし直します
+	// falseの場合はサイズが同じであれば移行済みとみなします
+	VerifyExisting bool
+	// Progress 設定されている場合、ファイルを1つ処理するごとに呼び出します
+	Progress func(Progress)
+}
+
+// FileResult ファイルごとの移行の結果
+type FileResult struct {
+	FileName string `json:"file_name"`
+	Size     int64  `json:"size"`
+	Status   string `json:"status"`
+	Error    string `json:"error,omitempty"`
+}
+
+// Progress 移行の進み具合
+type Progress struct {
+	Done  int        `json:"done"`
+	Total int        `json:"total"`
+	File  FileResult `json:"file"`
+}
+
+// Report 移行の結果
+type Report struct {
+	DryRun bool `json:"dry_run"`
+	// Images ポスターやアイテムから参照されている画像の数
+	Images int `json:"images"`
+	// Files 移行の対象となったファイルの数
+	// 縮小画像も含みます
+	Files   int `json:"files"`
+	Copied  int `json:"copied"`
+	Skipped int `json:"skipped"`
+	Pending int `json:"pending"`
+	Missing int `json:"missing"`
+	Failed  int `json:"failed"`
+	// CopiedBytes コピーしたファイルの合計サイズ
+	CopiedBytes int64 `json:"copied_bytes"`
+	// Problems 移行元にないか、移行に失敗したファイル
+	Problems []FileResult `json:"problems"`
+}
+
+type Manager interface {
+	// Migrate ポスターやアイテムから参照されている画像を、縮小画像とともに移行元から移行先のストレージにコピーします
+	// コピーしたファイルは移行先から読み直して内容を確かめます
+	// 移行先にすでにあるファイルはコピーしないため、中断しても再実行すれば続きから移行できます
+	// 途中でキャンセルされた場合は、それまでの結果とエラーを返します
+	Migrate(ctx context.Context, opts Options) (Report, error)
+}
@@ -0,0 +1,199 @@
+package storagemigration
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"errors"
+	"io"
+	"log/slog"
+
+	"github.com/Luke256/ducks/repository"
+	"github.com/Luke256/ducks/utils/compressor"
+	"github.com/Luke256/ducks/utils/storage"
+)
+
+type ManagerImpl struct {
+	repo repository.Repository
+	src  storage.Storage
+	dst  storage.Storage
+}
+
+func NewManagerImpl(repo repository.Repository, src storage.Storage, dst storage.Storage) *ManagerImpl {
+	return &ManagerImpl{
+		repo: repo,
+		src:  src,
+		dst:  dst,
+	}
+}
+
+func (m *ManagerImpl) Migrate(ctx context.Context, opts Options) (Report, error) {
+	report := Report{DryRun: opts.DryRun}
+
+	imageIDs, err := m.repo.ListImageIDs()
+	if err != nil {
+		return report, err
+	}
+	report.Images = len(imageIDs)
+
+	srcFiles, err := listFiles(ctx, m.src)
+	if err != nil {
+		return report, err
+	}
+	dstFiles, err := listFiles(ctx, m.dst)
+	if err != nil {
+		return report, err
+	}
+
+	// 元の画像がない場合は移行できないため報告する
+	// 縮小画像は小さな画像では作られないため、なくても報告しない
+	var files []FileResult
+	for _, imageID := range imageIDs {
+		info, ok := srcFiles[imageID]
+		if !ok {
+			files = append(files, FileResult{FileName: imageID, Status: StatusMissing})
+			continue
+		}
+		files = append(files, FileResult{FileName: imageID, Size: info.Size})
+
+		for _, r := range compressor.Renditions {
+			if info, ok := srcFiles[storage.RenditionFileName(imageID, r.Name)]; ok {
+				files = append(files, FileResult{FileName: info.Name, Size: info.Size})
+			}
+		}
+	}
+	report.Files = len(files)
+
+	for i, file := range files {
+		if err := ctx.Err(); err != nil {
+			return report, err
+		}
+
+		if file.Status == "" {
+			file.Status, err = m.migrateFile(ctx, file, dstFiles, opts)
+			if err != nil {
+				if ctxErr := ctx.Err(); ctxErr != nil {
+					return report, ctxErr
+				}
+				slog.Warn("failed to migrate file", "file_name", file.FileName, "error", err)
+				file.Status = StatusFailed
+				file.Error = err.Error()
+			}
+		}
+
+		switch file.Status {
+		case StatusCopied:
+			report.Copied++
+			report.CopiedBytes += file.Size
+		case StatusSkipped:
+			report.Skipped++
+		case StatusPending:
+			report.Pending++
+		case StatusMissing:
+			report.Missing++
+			report.Problems = append(report.Problems, file)
+		case StatusFailed:
+			report.Failed++
+			report.Problems = append(report.Problems, file)
+		}
+
+		if opts.Progress != nil {
+			opts.Progress(Progress{Done: i + 1, Total: len(files), File: file})
+		}
+	}
+
+	return report, nil
+}
+
+// migrateFile ファイルを移行先にコピーし、その結果を返します
+func (m *ManagerImpl) migrateFile(ctx context.Context, file FileResult, dstFiles map[string]storage.FileInfo, opts Options) (string, error) {
+	if existing, ok := dstFiles[file.FileName]; ok && existing.Size == file.Size {
+		if !opts.VerifyExisting {
+			return StatusSkipped, nil
+		}
+		srcSum, _, err := checksum(ctx, m.src, file.FileName)
+		if err != nil {
+			return "", err
+		}
+		dstSum, _, err := checksum(ctx, m.dst, file.FileName)
+		if err != nil && !errors.Is(err, storage.ErrFileNotFound) {
+			return "", err
+		}
+		if err == nil && bytes.Equal(srcSum, dstSum) {
+			return StatusSkipped, nil
+		}
+	}
+
+	if opts.DryRun {
+		return StatusPending, nil
+	}
+
+	if err := m.copyFile(ctx, file.FileName); err != nil {
+		return "", err
+	}
+	return StatusCopied, nil
+}
+
+// copyFile ファイルを移行元から移行先にコピーし、移行先から読み直して内容が一致するか確かめます
+func (m *ManagerImpl) copyFile(ctx context.Context, fileName string) error {
+	src, meta, err := m.src.DownloadFile(ctx, fileName)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	hash := sha256.New()
+	size := &countingWriter{}
+	if err := m.dst.PutFile(ctx, fileName, io.TeeReader(src, io.MultiWriter(hash, size)), meta); err != nil {
+		return err
+	}
+
+	sum, n, err := checksum(ctx, m.dst, fileName)
+	if err != nil {
+		return err
+	}
+	if n != size.n || !bytes.Equal(sum, hash.Sum(nil)) {
+		return ErrVerificationFailed
+	}
+	return nil
+}
+
+// checksum ストレージにあるファイルのSHA-256とサイズを返します
+func checksum(ctx context.Context, s storage.Storage, fileName string) ([]byte, int64, error) {
+	file, _, err := s.DownloadFile(ctx, fileName)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer file.Close()
+
+	hash := sha256.New()
+	n, err := io.Copy(hash, file)
+	if err != nil {
+		return nil, 0, err
+	}
+	return hash.Sum(nil), n, nil
+}
+
+// listFiles ストレージにあるファイルをファイル名で引けるようにして返します
+func listFiles(ctx context.Context, s storage.Storage) (map[string]storage.FileInfo, error) {
+	files, err := s.ListFiles(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	byName := make(map[string]storage.FileInfo, len(files))
+	for _, f := range files {
+		byName[f.Name] = f
+	}
+	return byName, nil
+}
+
+// countingWriter 書き込まれたバイト数を数えます
+type countingWriter struct {
+	n int64
+}
+
+func (w *countingWriter) Write(p []byte) (int, error) {
+	w.n += int64(len(p))
+	return len(p), nil
+}
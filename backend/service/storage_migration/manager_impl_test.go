@@ -0,0 +1,222 @@
+package storagemigration
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/Luke256/ducks/repository"
+	"github.com/Luke256/ducks/utils/storage/local"
+)
+
+type stubRepository struct {
+	repository.Repository
+	imageIDs []string
+}
+
+func (r *stubRepository) ListImageIDs() ([]string, error) {
+	return r.imageIDs, nil
+}
+
+const (
+	posterImage  = "0190c5a4-0000-7000-8000-000000000001.webp"
+	itemImage    = "0190c5a4-0000-7000-8000-000000000002.webp"
+	missingImage = "0190c5a4-0000-7000-8000-000000000003.webp"
+)
+
+func setupStorages(t *testing.T) (*local.LocalStorage, string, *local.LocalStorage, string) {
+	t.Helper()
+	srcRoot := t.TempDir()
+	dstRoot := t.TempDir()
+
+	files := map[string]string{
+		posterImage: "poster",
+		"0190c5a4-0000-7000-8000-000000000001_thumb.webp":  "poster thumb",
+		"0190c5a4-0000-7000-8000-000000000001_medium.webp": "poster medium",
+		itemImage: "item",
+		"0190c5a4-0000-7000-8000-000000000009.webp": "unreferenced",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(srcRoot, name), []byte(content), 0o644); err != nil {
+			t.Fatalf("failed to write %s: %v", name, err)
+		}
+	}
+
+	src, err := local.NewLocalStorage(srcRoot)
+	if err != nil {
+		t.Fatalf("NewLocalStorage failed: %v", err)
+	}
+	dst, err := local.NewLocalStorage(dstRoot)
+	if err != nil {
+		t.Fatalf("NewLocalStorage failed: %v", err)
+	}
+	return src, srcRoot, dst, dstRoot
+}
+
+// listRoot 保存されているファイルの一覧を返します
+// 内容のハッシュの記録は含みません
+func listRoot(t *testing.T, root string) []string {
+	t.Helper()
+	entries, err := os.ReadDir(root)
+	if err != nil {
+		t.Fatalf("failed to read root: %v", err)
+	}
+	var names []string
+	for _, e := range entries {
+		if strings.HasPrefix(e.Name(), ".sha256-") {
+			continue
+		}
+		names = append(names, e.Name())
+	}
+	return names
+}
+
+func TestMigrate(t *testing.T) {
+	src, _, dst, dstRoot := setupStorages(t)
+	m := NewManagerImpl(&stubRepository{imageIDs: []string{posterImage, itemImage, missingImage}}, src, dst)
+
+	var progress []Progress
+	report, err := m.Migrate(context.Background(), Options{
+		Progress: func(p Progress) { progress = append(progress, p) },
+	})
+	if err != nil {
+		t.Fatalf("Migrate failed: %v", err)
+	}
+
+	if report.Images != 3 || report.Files != 5 || report.Copied != 4 || report.Missing != 1 || report.Failed != 0 {
+		t.Errorf("unexpected report: %+v", report)
+	}
+	if report.CopiedBytes != int64(len("poster")+len("poster thumb")+len("poster medium")+len("item")) {
+		t.Errorf("unexpected copied bytes: %d", report.CopiedBytes)
+	}
+	if len(report.Problems) != 1 || report.Problems[0].FileName != missingImage || report.Problems[0].Status != StatusMissing {
+		t.Errorf("expected %s to be reported as missing, got %+v", missingImage, report.Problems)
+	}
+
+	if len(progress) != 5 || progress[4].Done != 5 || progress[4].Total != 5 {
+		t.Errorf("unexpected progress: %+v", progress)
+	}
+
+	want := []string{
+		"0190c5a4-0000-7000-8000-000000000001_medium.webp",
+		"0190c5a4-0000-7000-8000-000000000001_thumb.webp",
+		posterImage,
+		itemImage,
+	}
+	slices.Sort(want)
+	if got := listRoot(t, dstRoot); !slices.Equal(got, want) {
+		t.Errorf("expected %v in destination, got %v", want, got)
+	}
+
+	data, err := os.ReadFile(filepath.Join(dstRoot, posterImage))
+	if err != nil || string(data) != "poster" {
+		t.Errorf("expected copied content, got %q (%v)", data, err)
+	}
+}
+
+func TestMigrate_Resume(t *testing.T) {
+	src, _, dst, dstRoot := setupStorages(t)
+	m := NewManagerImpl(&stubRepository{imageIDs: []string{posterImage, itemImage}}, src, dst)
+
+	// 前回の移行でコピー済みのファイルと、途中で中断して内容が異なるファイル
+	if err := os.WriteFile(filepath.Join(dstRoot, posterImage), []byte("poster"), 0o644); err != nil {
+		t.Fatalf("failed to write: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dstRoot, itemImage), []byte("it"), 0o644); err != nil {
+		t.Fatalf("failed to write: %v", err)
+	}
+
+	report, err := m.Migrate(context.Background(), Options{})
+	if err != nil {
+		t.Fatalf("Migrate failed: %v", err)
+	}
+	if report.Skipped != 1 || report.Copied != 3 {
+		t.Errorf("unexpected report: %+v", report)
+	}
+
+	data, err := os.ReadFile(filepath.Join(dstRoot, itemImage))
+	if err != nil || string(data) != "item" {
+		t.Errorf("expected partially copied file to be replaced, got %q (%v)", data, err)
+	}
+
+	report, err = m.Migrate(context.Background(), Options{})
+	if err != nil {
+		t.Fatalf("Migrate failed: %v", err)
+	}
+	if report.Skipped != 4 || report.Copied != 0 {
+		t.Errorf("expected all files to be skipped on second run, got %+v", report)
+	}
+}
+
+func TestMigrate_VerifyExisting(t *testing.T) {
+	src, _, dst, dstRoot := setupStorages(t)
+	m := NewManagerImpl(&stubRepository{imageIDs: []string{itemImage}}, src, dst)
+
+	// サイズは同じだが内容が異なる
+	if err := os.WriteFile(filepath.Join(dstRoot, itemImage), []byte("iTEM"), 0o644); err != nil {
+		t.Fatalf("failed to write: %v", err)
+	}
+
+	report, err := m.Migrate(context.Background(), Options{})
+	if err != nil {
+		t.Fatalf("Migrate failed: %v", err)
+	}
+	if report.Skipped != 1 {
+		t.Errorf("expected file to be skipped by size, got %+v", report)
+	}
+
+	report, err = m.Migrate(context.Background(), Options{VerifyExisting: true})
+	if err != nil {
+		t.Fatalf("Migrate failed: %v", err)
+	}
+	if report.Copied != 1 {
+		t.Errorf("expected file to be copied again, got %+v", report)
+	}
+
+	data, err := os.ReadFile(filepath.Join(dstRoot, itemImage))
+	if err != nil || string(data) != "item" {
+		t.Errorf("expected file to be replaced, got %q (%v)", data, err)
+	}
+}
+
+func TestMigrate_DryRun(t *testing.T) {
+	src, _, dst, dstRoot := setupStorages(t)
+	m := NewManagerImpl(&stubRepository{imageIDs: []string{posterImage, itemImage}}, src, dst)
+
+	report, err := m.Migrate(context.Background(), Options{DryRun: true})
+	if err != nil {
+		t.Fatalf("Migrate failed: %v", err)
+	}
+	if !report.DryRun || report.Pending != 4 || report.Copied != 0 {
+		t.Errorf("unexpected report: %+v", report)
+	}
+	if got := listRoot(t, dstRoot); len(got) != 0 {
+		t.Errorf("expected no files to be copied, got %v", got)
+	}
+}
+
+func TestMigrate_Canceled(t *testing.T) {
+	src, _, dst, dstRoot := setupStorages(t)
+	m := NewManagerImpl(&stubRepository{imageIDs: []string{posterImage, itemImage}}, src, dst)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	report, err := m.Migrate(ctx, Options{
+		Progress: func(p Progress) {
+			if p.Done == 1 {
+				cancel()
+			}
+		},
+	})
+	if err != context.Canceled {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+	if report.Copied != 1 {
+		t.Errorf("expected one file to be copied before cancel, got %+v", report)
+	}
+	if got := listRoot(t, dstRoot); len(got) != 1 {
+		t.Errorf("expected one file in destination, got %v", got)
+	}
+}
@@ -0,0 +1,74 @@
+package upload
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// TargetPoster ポスターの画像
+	TargetPoster = "poster"
+	// TargetItem アイテムの画像
+	TargetItem = "item"
+)
+
+const (
+	// StatusWaiting URLを発行し、クライアントからのアップロードを待っている
+	StatusWaiting = "waiting"
+	// StatusProcessing アップロードが完了し、画像を圧縮している
+	StatusProcessing = "processing"
+	// StatusCompleted 圧縮した画像をポスターやアイテムに設定した
+	StatusCompleted = "completed"
+	// StatusFailed 画像を設定できなかった
+	StatusFailed = "failed"
+)
+
+var (
+	ErrNotFound         = errors.New("upload not found")
+	ErrNotSupported     = errors.New("direct upload not supported")
+	ErrInvalidTarget    = errors.New("invalid upload target")
+	ErrTargetNotFound   = errors.New("upload target not found")
+	ErrNotUploaded      = errors.New("file has not been uploaded")
+	ErrAlreadyConfirmed = errors.New("upload already confirmed")
+)
+
+type Upload struct {
+	ID         uuid.UUID `json:"id"`
+	TargetType string    `json:"target_type"`
+	TargetID   uuid.UUID `json:"target_id"`
+	Status     string    `json:"status"`
+	Error      string    `json:"error,omitempty"`
+	ExpiresAt  time.Time `json:"expires_at"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// PresignedUpload アップロードと、クライアントがファイルを送信するための署名付きリクエスト
+type PresignedUpload struct {
+	Upload
+	URL     string            `json:"url"`
+	Method  string            `json:"method"`
+	Headers map[string]string `json:"headers"`
+}
+
+type Manager interface {
+	// Request ポスターやアイテムの画像を、ストレージに直接アップロードするためのURLを発行します
+	// ストレージが直接アップロードに対応していない場合はErrNotSupportedを返します
+	Request(ctx context.Context, targetType string, targetID uuid.UUID, contentType string) (PresignedUpload, error)
+
+	// Confirm アップロードの完了を受け付け、画像の圧縮と設定を非同期に開始します
+	// ファイルがアップロードされていない場合はErrNotUploadedを、既に受け付けている場合はErrAlreadyConfirmedを返します
+	Confirm(ctx context.Context, id uuid.UUID) (Upload, error)
+
+	// Get アップロードの状態を取得します
+	Get(id uuid.UUID) (Upload, error)
+
+	// Resume 再起動などで中断された画像の処理を再開します
+	Resume(ctx context.Context) error
+
+	// Supported ストレージが直接アップロードに対応しているかを返します
+	Supported() bool
+}
@@ -0,0 +1,273 @@
+package upload
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/Luke256/ducks/model"
+	"github.com/Luke256/ducks/repository"
+	"github.com/Luke256/ducks/service/poster"
+	stockitem "github.com/Luke256/ducks/service/stock_item"
+	"github.com/Luke256/ducks/utils/compressor"
+	"github.com/Luke256/ducks/utils/storage"
+	"github.com/google/uuid"
+)
+
+const (
+	// uploadURLExpiry 発行したURLの有効期間
+	uploadURLExpiry = 15 * time.Minute
+	// uploadKeyPrefix 直接アップロードされたファイルを置く場所
+	// 処理後に削除するが、放置されたものはストレージのライフサイクル設定で削除する
+	uploadKeyPrefix = "uploads/"
+	// maxConcurrentProcessing 同時に圧縮する画像の数
+	maxConcurrentProcessing = 2
+)
+
+type ManagerImpl struct {
+	repo             repository.Repository
+	uploader         storage.DirectUploader
+	posterManager    poster.Manager
+	stockItemManager stockitem.Manager
+	sem              chan struct{}
+}
+
+// NewManagerImpl strgがstorage.DirectUploaderを実装していない場合、直接アップロードは使えません
+func NewManagerImpl(repo repository.Repository, strg storage.Storage, pm poster.Manager, sim stockitem.Manager) *ManagerImpl {
+	uploader, _ := strg.(storage.DirectUploader)
+	return &ManagerImpl{
+		repo:             repo,
+		uploader:         uploader,
+		posterManager:    pm,
+		stockItemManager: sim,
+		sem:              make(chan struct{}, maxConcurrentProcessing),
+	}
+}
+
+func toUploadType(u model.ImageUpload) Upload {
+	return Upload{
+		ID:         u.ID,
+		TargetType: u.TargetType,
+		TargetID:   u.TargetID,
+		Status:     u.Status,
+		Error:      u.Error,
+		ExpiresAt:  u.ExpiresAt,
+		CreatedAt:  u.CreatedAt,
+		UpdatedAt:  u.UpdatedAt,
+	}
+}
+
+func (m *ManagerImpl) Request(ctx context.Context, targetType string, targetID uuid.UUID, contentType string) (PresignedUpload, error) {
+	if m.uploader == nil {
+		return PresignedUpload{}, ErrNotSupported
+	}
+	if err := (storage.Metadata{ContentType: contentType}).Validate(); err != nil {
+		return PresignedUpload{}, err
+	}
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+
+	if err := m.checkTarget(targetType, targetID); err != nil {
+		return PresignedUpload{}, err
+	}
+
+	key := uploadKeyPrefix + uuid.NewString()
+	expiresAt := time.Now().Add(uploadURLExpiry)
+	req, err := m.uploader.PresignUpload(ctx, key, contentType, uploadURLExpiry)
+	if err != nil {
+		return PresignedUpload{}, fmt.Errorf("failed to presign upload: %w", err)
+	}
+
+	u, err := m.repo.CreateImageUpload(targetType, targetID, key, contentType, StatusWaiting, expiresAt)
+	if err != nil {
+		return PresignedUpload{}, fmt.Errorf("failed to create image upload: %w", err)
+	}
+
+	return PresignedUpload{
+		Upload:  toUploadType(u),
+		URL:     req.URL,
+		Method:  req.Method,
+		Headers: req.Headers,
+	}, nil
+}
+
+// checkTarget 画像を設定するポスターやアイテムが存在するか確認します
+func (m *ManagerImpl) checkTarget(targetType string, targetID uuid.UUID) error {
+	var err error
+	switch targetType {
+	case TargetPoster:
+		_, err = m.posterManager.Get(targetID)
+	case TargetItem:
+		_, err = m.stockItemManager.Get(targetID)
+	default:
+		return ErrInvalidTarget
+	}
+
+	switch {
+	case err == nil:
+		return nil
+	case errors.Is(err, poster.ErrNotFound), errors.Is(err, stockitem.ErrNotFound):
+		return ErrTargetNotFound
+	default:
+		return fmt.Errorf("failed to get upload target: %w", err)
+	}
+}
+
+func (m *ManagerImpl) Confirm(ctx context.Context, id uuid.UUID) (Upload, error) {
+	if m.uploader == nil {
+		return Upload{}, ErrNotSupported
+	}
+
+	u, err := m.getImageUpload(id)
+	if err != nil {
+		return Upload{}, err
+	}
+	if u.Status != StatusWaiting {
+		return Upload{}, ErrAlreadyConfirmed
+	}
+
+	file, _, err := m.uploader.OpenUpload(ctx, u.ObjectKey)
+	if err != nil {
+		if errors.Is(err, storage.ErrFileNotFound) {
+			return Upload{}, ErrNotUploaded
+		}
+		return Upload{}, fmt.Errorf("failed to open uploaded file: %w", err)
+	}
+	file.Close()
+
+	if err := m.repo.UpdateImageUploadStatus(id, StatusWaiting, StatusProcessing, ""); err != nil {
+		switch err {
+		case repository.ErrNotFound:
+			return Upload{}, ErrNotFound
+		case repository.ErrConflict:
+			return Upload{}, ErrAlreadyConfirmed
+		default:
+			return Upload{}, fmt.Errorf("failed to update image upload status: %w", err)
+		}
+	}
+
+	// リクエストが終わっても処理を続ける
+	m.enqueue(context.WithoutCancel(ctx), u)
+
+	u.Status = StatusProcessing
+	return toUploadType(u), nil
+}
+
+func (m *ManagerImpl) Get(id uuid.UUID) (Upload, error) {
+	u, err := m.getImageUpload(id)
+	if err != nil {
+		return Upload{}, err
+	}
+	return toUploadType(u), nil
+}
+
+func (m *ManagerImpl) getImageUpload(id uuid.UUID) (model.ImageUpload, error) {
+	u, err := m.repo.GetImageUploadByID(id)
+	if err != nil {
+		switch err {
+		case repository.ErrNotFound:
+			return model.ImageUpload{}, ErrNotFound
+		default:
+			return model.ImageUpload{}, fmt.Errorf("failed to get image upload: %w", err)
+		}
+	}
+	return u, nil
+}
+
+func (m *ManagerImpl) Resume(ctx context.Context) error {
+	if m.uploader == nil {
+		return nil
+	}
+
+	uploads, err := m.repo.GetImageUploadsByStatus(StatusProcessing)
+	if err != nil {
+		return fmt.Errorf("failed to get processing image uploads: %w", err)
+	}
+	for _, u := range uploads {
+		m.enqueue(ctx, u)
+	}
+	if len(uploads) > 0 {
+		slog.Info("Resumed image uploads", "count", len(uploads))
+	}
+	return nil
+}
+
+// enqueue 同時に処理する数を制限しながら、バックグラウンドで画像を処理します
+func (m *ManagerImpl) enqueue(ctx context.Context, u model.ImageUpload) {
+	go func() {
+		m.sem <- struct{}{}
+		defer func() { <-m.sem }()
+
+		m.process(ctx, u)
+	}()
+}
+
+// process アップロードされたファイルを圧縮してポスターやアイテムに設定し、結果を記録します
+// 成功しても失敗しても、アップロードされた元のファイルは削除します
+func (m *ManagerImpl) process(ctx context.Context, u model.ImageUpload) {
+	status, message := StatusCompleted, ""
+	if err := m.attach(ctx, u); err != nil {
+		status, message = StatusFailed, processErrorMessage(err)
+		if message == "" {
+			slog.Error("failed to process image upload", "upload_id", u.ID, "error", err)
+			message = "failed to process image"
+		}
+	}
+
+	if err := m.repo.UpdateImageUploadStatus(u.ID, StatusProcessing, status, message); err != nil {
+		slog.Error("failed to update image upload status", "upload_id", u.ID, "error", err)
+	}
+
+	if err := m.uploader.DeleteUpload(ctx, u.ObjectKey); err != nil {
+		slog.Warn("failed to delete uploaded file", "upload_id", u.ID, "key", u.ObjectKey, "error", err)
+	}
+}
+
+func (m *ManagerImpl) attach(ctx context.Context, u model.ImageUpload) error {
+	file, meta, err := m.uploader.OpenUpload(ctx, u.ObjectKey)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	if meta.ContentType == "" {
+		meta.ContentType = u.ContentType
+	}
+
+	switch u.TargetType {
+	case TargetPoster:
+		return m.posterManager.UpdateImage(ctx, u.TargetID, file, meta)
+	case TargetItem:
+		return m.stockItemManager.UpdateImage(ctx, u.TargetID, file, meta)
+	default:
+		return ErrInvalidTarget
+	}
+}
+
+// processErrorMessage 処理に失敗した理由を、クライアントに返すメッセージにします
+// 想定していないエラーの場合は空文字を返します
+func processErrorMessage(err error) string {
+	switch {
+	case errors.Is(err, storage.ErrFileNotFound):
+		return "uploaded file not found"
+	case errors.Is(err, poster.ErrNotFound), errors.Is(err, stockitem.ErrNotFound):
+		return "upload target not found"
+	case errors.Is(err, compressor.ErrInvalidImage):
+		return "invalid image format"
+	case errors.Is(err, storage.ErrUnsupportedContentType):
+		return "unsupported image type"
+	case errors.Is(err, compressor.ErrFileTooLarge):
+		return "image file too large"
+	case errors.Is(err, compressor.ErrImageTooLarge):
+		return "image dimensions too large"
+	default:
+		return ""
+	}
+}
+
+func (m *ManagerImpl) Supported() bool {
+	return m.uploader != nil
+}
@@ -0,0 +1,93 @@
+package actiontoken
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	version = 1
+	// ヘッダー: バージョン(1) + ポスターID(16) + 有効期限(8)
+	headerSize = 1 + 16 + 8
+	macSize    = sha256.Size
+	// 署名の用途を区別するための接頭辞
+	domain = "ducks/poster-action"
+)
+
+var (
+	ErrInvalidToken = errors.New("invalid action token")
+	ErrExpired      = errors.New("action token has expired")
+)
+
+// Claims トークンで許可する操作
+type Claims struct {
+	PosterID  uuid.UUID
+	Status    string
+	ExpiresAt time.Time
+}
+
+// Signer HMAC-SHA256でトークンの署名と検証を行います
+type Signer struct {
+	secret []byte
+}
+
+func NewSigner(secret []byte) *Signer {
+	return &Signer{secret: secret}
+}
+
+// Sign 操作内容に署名したトークンを返します
+// トークンはURLにそのまま含められる文字列です
+func (s *Signer) Sign(claims Claims) string {
+	payload := make([]byte, headerSize, headerSize+len(claims.Status)+macSize)
+	payload[0] = version
+	copy(payload[1:17], claims.PosterID[:])
+	binary.BigEndian.PutUint64(payload[17:headerSize], uint64(claims.ExpiresAt.Unix()))
+	payload = append(payload, claims.Status...)
+
+	return base64.RawURLEncoding.EncodeToString(append(payload, s.mac(payload)...))
+}
+
+// Verify トークンの署名と有効期限を検証し、許可された操作を返します
+func (s *Signer) Verify(token string, now time.Time) (Claims, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil || len(raw) <= headerSize+macSize {
+		return Claims{}, ErrInvalidToken
+	}
+
+	payload, sig := raw[:len(raw)-macSize], raw[len(raw)-macSize:]
+	if !hmac.Equal(sig, s.mac(payload)) {
+		return Claims{}, ErrInvalidToken
+	}
+	if payload[0] != version {
+		return Claims{}, ErrInvalidToken
+	}
+
+	posterID, err := uuid.FromBytes(payload[1:17])
+	if err != nil {
+		return Claims{}, ErrInvalidToken
+	}
+	claims := Claims{
+		PosterID:  posterID,
+		Status:    string(payload[headerSize:]),
+		ExpiresAt: time.Unix(int64(binary.BigEndian.Uint64(payload[17:headerSize])), 0),
+	}
+
+	if !now.Before(claims.ExpiresAt) {
+		return Claims{}, ErrExpired
+	}
+
+	return claims, nil
+}
+
+func (s *Signer) mac(payload []byte) []byte {
+	h := hmac.New(sha256.New, s.secret)
+	h.Write([]byte(domain))
+	h.Write(payload)
+	return h.Sum(nil)
+}
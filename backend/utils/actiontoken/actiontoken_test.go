@@ -0,0 +1,83 @@
+package actiontoken
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestSignAndVerify(t *testing.T) {
+	signer := NewSigner([]byte("secret"))
+	now := time.Date(2026, 11, 1, 12, 0, 0, 0, time.UTC)
+	claims := Claims{
+		PosterID:  uuid.New(),
+		Status:    "collected",
+		ExpiresAt: now.Add(time.Hour),
+	}
+
+	token := signer.Sign(claims)
+	if strings.ContainsAny(token, "+/=") {
+		t.Errorf("token is not URL safe: %q", token)
+	}
+
+	got, err := signer.Verify(token, now)
+	if err != nil {
+		t.Fatalf("Verify failed: %v", err)
+	}
+	if got.PosterID != claims.PosterID {
+		t.Errorf("expected poster ID %s, got %s", claims.PosterID, got.PosterID)
+	}
+	if got.Status != claims.Status {
+		t.Errorf("expected status %q, got %q", claims.Status, got.Status)
+	}
+	if !got.ExpiresAt.Equal(claims.ExpiresAt) {
+		t.Errorf("expected expiry %v, got %v", claims.ExpiresAt, got.ExpiresAt)
+	}
+}
+
+func TestVerify_Expired(t *testing.T) {
+	signer := NewSigner([]byte("secret"))
+	now := time.Date(2026, 11, 1, 12, 0, 0, 0, time.UTC)
+	token := signer.Sign(Claims{
+		PosterID:  uuid.New(),
+		Status:    "collected",
+		ExpiresAt: now,
+	})
+
+	if _, err := signer.Verify(token, now); err != ErrExpired {
+		t.Errorf("expected ErrExpired, got %v", err)
+	}
+}
+
+func TestVerify_Invalid(t *testing.T) {
+	signer := NewSigner([]byte("secret"))
+	now := time.Date(2026, 11, 1, 12, 0, 0, 0, time.UTC)
+	token := signer.Sign(Claims{
+		PosterID:  uuid.New(),
+		Status:    "collected",
+		ExpiresAt: now.Add(time.Hour),
+	})
+
+	// 別のシークレットで署名されたトークン
+	if _, err := NewSigner([]byte("other")).Verify(token, now); err != ErrInvalidToken {
+		t.Errorf("expected ErrInvalidToken for other secret, got %v", err)
+	}
+
+	// 改ざんされたトークン
+	raw := []byte(token)
+	raw[5] ^= 1
+	if raw[5] == '+' || raw[5] == '/' {
+		raw[5] = 'A'
+	}
+	if _, err := signer.Verify(string(raw), now); err != ErrInvalidToken {
+		t.Errorf("expected ErrInvalidToken for tampered token, got %v", err)
+	}
+
+	for _, token := range []string{"", "not a token", "AAAA"} {
+		if _, err := signer.Verify(token, now); err != ErrInvalidToken {
+			t.Errorf("expected ErrInvalidToken for %q, got %v", token, err)
+		}
+	}
+}
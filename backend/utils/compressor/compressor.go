@@ -1,6 +1,7 @@
 package compressor
 
 import (
+	"bytes"
 	"fmt"
 	"image"
 	_ "image/gif"
@@ -15,33 +16,97 @@ import (
 
 var ErrInvalidImage = fmt.Errorf("invalid image format")
 
-// CompressImage 画像を圧縮し、webp形式でファイルを返します
-func CompressImage(src io.ReadSeekCloser) (*os.File, string, error) {
-	srcImage, _, err := image.Decode(src)
+// decode 画像を読み込み、Exifの向きを反映した画像を返します
+// 位置情報などのメタデータは読み込んだ画素から作り直す際に失われるため、圧縮後の画像には含まれません
+// profileの上限を超える場合はErrFileTooLargeかErrImageTooLargeを返し、MaxEdgeを超える画像は縮小します
+func decode(src io.Reader, profile Profile) (image.Image, error) {
+	data, err := readAll(src, profile.MaxBytes)
+	if err != nil {
+		return nil, err
+	}
+	orientation := jpegOrientation(data)
+
+	// 巨大な画像を展開してメモリを使い果たさないよう、先に大きさだけを確認する
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		slog.Error("Failed to decode image config", "error", err)
+		if err == image.ErrFormat {
+			return nil, ErrInvalidImage
+		}
+		return nil, err
+	}
+	width, height := config.Width, config.Height
+	if orientation >= 5 {
+		width, height = height, width
+	}
+	if (profile.MaxWidth > 0 && width > profile.MaxWidth) || (profile.MaxHeight > 0 && height > profile.MaxHeight) {
+		return nil, ErrImageTooLarge
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(data))
 	if err != nil {
 		slog.Error("Failed to decode image", "error", err)
 		if err == image.ErrFormat {
-			return nil, "", ErrInvalidImage
+			return nil, ErrInvalidImage
+		}
+		return nil, err
+	}
+	img = applyOrientation(img, orientation)
+
+	if profile.MaxEdge > 0 {
+		if resized, ok := resize(img, profile.MaxEdge); ok {
+			img = resized
 		}
-		return nil, "", err
 	}
 
+	return img, nil
+}
+
+// readAll maxBytesを上限として読み込みます
+// 上限を超える場合はErrFileTooLargeを返します
+func readAll(src io.Reader, maxBytes int64) ([]byte, error) {
+	if maxBytes <= 0 {
+		return io.ReadAll(src)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(src, maxBytes+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) > maxBytes {
+		return nil, ErrFileTooLarge
+	}
+	return data, nil
+}
+
+// encode 画像をwebp形式で一時ファイルに書き込み、先頭までシークしたファイルを返します
+func encode(img image.Image, profile Profile) (_ *os.File, err error) {
 	tmpFile, err := os.CreateTemp("", "")
 	if err != nil {
-		return nil, "", err
+		return nil, err
 	}
+	defer func() {
+		if err != nil {
+			tmpFile.Close()
+			os.Remove(tmpFile.Name())
+		}
+	}()
 
+	quality := profile.Quality
+	if quality <= 0 {
+		quality = defaultQuality
+	}
 	options := webp.Options{
-		Lossless: false,
-		Quality:  75,
+		Lossless: profile.Lossless,
+		Quality:  min(quality, 100),
 	}
-	if err := webp.Encode(tmpFile, srcImage, options); err != nil {
-		return nil, "", err
+	if err := webp.Encode(tmpFile, img, options); err != nil {
+		return nil, err
 	}
 
 	if _, err := tmpFile.Seek(0, io.SeekStart); err != nil {
-		return nil, "", err
+		return nil, err
 	}
 
-	return tmpFile, "webp", nil
+	return tmpFile, nil
 }
@@ -5,52 +5,33 @@ import (
 	"testing"
 )
 
-func TestCompressImage_PNG(t *testing.T) {
-	f, err := os.Open("../../test/test.png")
-	if err != nil {
-		t.Fatalf("failed to open test.png: %v", err)
-	}
-	defer f.Close()
-
-	out, ext, err := CompressImage(f)
-	if err != nil {
-		t.Fatalf("CompressImage failed: %v", err)
-	}
-	defer os.Remove(out.Name())
-	defer out.Close()
-
-	if ext != "webp" {
-		t.Errorf("expected ext \"webp\", got %q", ext)
-	}
+func TestCompressImageSet_PNG(t *testing.T) {
+	testCompressImageSet(t, "../../test/test.png")
+}
 
-	info, err := out.Stat()
-	if err != nil {
-		t.Fatalf("failed to stat output: %v", err)
-	}
-	if info.Size() == 0 {
-		t.Error("output file is empty")
-	}
+func TestCompressImageSet_JPEG(t *testing.T) {
+	testCompressImageSet(t, "../../test/test.jpg")
 }
 
-func TestCompressImage_JPEG(t *testing.T) {
-	f, err := os.Open("../../test/test.jpg")
+func testCompressImageSet(t *testing.T, path string) {
+	t.Helper()
+	f, err := os.Open(path)
 	if err != nil {
-		t.Fatalf("failed to open test.jpg: %v", err)
+		t.Fatalf("failed to open %s: %v", path, err)
 	}
 	defer f.Close()
 
-	out, ext, err := CompressImage(f)
+	set, err := CompressImageSet(f, Profile{})
 	if err != nil {
-		t.Fatalf("CompressImage failed: %v", err)
+		t.Fatalf("CompressImageSet failed: %v", err)
 	}
-	defer os.Remove(out.Name())
-	defer out.Close()
+	defer set.Close()
 
-	if ext != "webp" {
-		t.Errorf("expected ext \"webp\", got %q", ext)
+	if set.Format != "webp" {
+		t.Errorf("expected format \"webp\", got %q", set.Format)
 	}
 
-	info, err := out.Stat()
+	info, err := set.Original.Stat()
 	if err != nil {
 		t.Fatalf("failed to stat output: %v", err)
 	}
@@ -0,0 +1,105 @@
+package compressor
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"io"
+	"os"
+	"testing"
+
+	"github.com/gen2brain/webp"
+)
+
+// テスト用の画像は、Orientationを反映すると幅32px・高さ48pxで
+// 左上が赤、右上が緑、左下が青、右下が白になるよう保存されています
+// また、Exifに位置情報を含んでいます
+func TestCompressImageSet_Orientation(t *testing.T) {
+	for orientation := 1; orientation <= 8; orientation++ {
+		t.Run(fmt.Sprintf("orientation %d", orientation), func(t *testing.T) {
+			data, err := os.ReadFile(fmt.Sprintf("../../test/exif_orientation_%d.jpg", orientation))
+			if err != nil {
+				t.Fatalf("failed to read test image: %v", err)
+			}
+			if got := jpegOrientation(data); got != orientation {
+				t.Fatalf("jpegOrientation = %d, want %d", got, orientation)
+			}
+
+			set, err := CompressImageSet(bytes.NewReader(data), Profile{})
+			if err != nil {
+				t.Fatalf("CompressImageSet failed: %v", err)
+			}
+			defer set.Close()
+
+			encoded, err := io.ReadAll(set.Original)
+			if err != nil {
+				t.Fatalf("failed to read output: %v", err)
+			}
+			assertNoMetadata(t, encoded)
+
+			img, err := webp.Decode(bytes.NewReader(encoded))
+			if err != nil {
+				t.Fatalf("failed to decode output: %v", err)
+			}
+			assertUpright(t, img)
+		})
+	}
+}
+
+func TestJPEGOrientation_Invalid(t *testing.T) {
+	tests := map[string][]byte{
+		"empty":     nil,
+		"not jpeg":  []byte("\x89PNG\r\n\x1a\n"),
+		"no exif":   {0xFF, 0xD8, 0xFF, 0xDA, 0x00, 0x02},
+		"truncated": {0xFF, 0xD8, 0xFF, 0xE1, 0x00, 0x40, 'E', 'x', 'i', 'f', 0, 0, 'M', 'M'},
+		"bad tiff":  append([]byte{0xFF, 0xD8, 0xFF, 0xE1, 0x00, 0x10}, []byte("Exif\x00\x00XX\x00\x2a\x00\x00\x00\x08")...),
+	}
+
+	for name, data := range tests {
+		if got := jpegOrientation(data); got != 1 {
+			t.Errorf("%s: jpegOrientation = %d, want 1", name, got)
+		}
+	}
+}
+
+// assertNoMetadata webpにExifやXMPのチャンクが含まれていないことを確認します
+func assertNoMetadata(t *testing.T, data []byte) {
+	t.Helper()
+	for _, marker := range []string{"EXIF", "Exif", "XMP ", "GPS"} {
+		if bytes.Contains(data, []byte(marker)) {
+			t.Errorf("output contains metadata marker %q", marker)
+		}
+	}
+}
+
+// assertUpright 画像が正しい向きになっていることを、四隅の色で確認します
+func assertUpright(t *testing.T, img image.Image) {
+	t.Helper()
+
+	b := img.Bounds()
+	if b.Dx() != 32 || b.Dy() != 48 {
+		t.Fatalf("expected 32x48 image, got %dx%d", b.Dx(), b.Dy())
+	}
+
+	corners := []struct {
+		name    string
+		x, y    int
+		r, g, b uint32
+	}{
+		{"top left", 8, 12, 0xff, 0, 0},
+		{"top right", 24, 12, 0, 0xff, 0},
+		{"bottom left", 8, 36, 0, 0, 0xff},
+		{"bottom right", 24, 36, 0xff, 0xff, 0xff},
+	}
+	for _, c := range corners {
+		r, g, bl, _ := img.At(b.Min.X+c.x, b.Min.Y+c.y).RGBA()
+		if !near(r>>8, c.r) || !near(g>>8, c.g) || !near(bl>>8, c.b) {
+			t.Errorf("%s: got rgb(%d, %d, %d), want rgb(%d, %d, %d)", c.name, r>>8, g>>8, bl>>8, c.r, c.g, c.b)
+		}
+	}
+}
+
+// near 非可逆圧縮による誤差を許容して比較します
+func near(got, want uint32) bool {
+	return max(got, want)-min(got, want) < 64
+}
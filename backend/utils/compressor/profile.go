@@ -0,0 +1,50 @@
+package compressor
+
+import "errors"
+
+// defaultQuality Qualityが指定されていない場合のwebpの品質
+const defaultQuality = 75
+
+var (
+	ErrFileTooLarge  = errors.New("file too large")
+	ErrImageTooLarge = errors.New("image dimensions too large")
+)
+
+// Profile 画像の用途ごとの、受け付ける画像の上限と圧縮の設定
+// 上限に0以下を指定した場合は制限しません
+type Profile struct {
+	// MaxBytes 受け付けるファイルの最大バイト数
+	MaxBytes int64
+	// MaxWidth, MaxHeight 受け付ける画像の最大ピクセル数
+	// 画像全体を読み込む前に、ヘッダーから求めた大きさで確認します
+	MaxWidth  int
+	MaxHeight int
+	// MaxEdge 保存する画像の長辺の最大ピクセル数
+	// これより大きい画像は縮小して保存します
+	MaxEdge int
+	// Quality webpの品質 (1-100)。0以下の場合は既定の品質で圧縮します
+	Quality int
+	// Lossless trueの場合は可逆圧縮します
+	Lossless bool
+}
+
+var (
+	// PosterProfile ポスターの画像の既定の設定
+	// 掲示物の文字が読めるよう、アイテムより大きく高品質に保存します
+	PosterProfile = Profile{
+		MaxBytes:  20 << 20,
+		MaxWidth:  8192,
+		MaxHeight: 8192,
+		MaxEdge:   4096,
+		Quality:   85,
+	}
+
+	// ItemProfile アイテムの画像の既定の設定
+	ItemProfile = Profile{
+		MaxBytes:  20 << 20,
+		MaxWidth:  8192,
+		MaxHeight: 8192,
+		MaxEdge:   2048,
+		Quality:   75,
+	}
+)
@@ -0,0 +1,123 @@
+package compressor
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+	"os"
+	"testing"
+
+	"github.com/gen2brain/webp"
+	xwebp "golang.org/x/image/webp"
+)
+
+func gradientPNG(t *testing.T, w, h int) []byte {
+	t.Helper()
+
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	for x := range w {
+		for y := range h {
+			img.Set(x, y, color.RGBA{uint8(x), uint8(y), 128, 255})
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatalf("failed to encode test image: %v", err)
+	}
+	return buf.Bytes()
+}
+
+func TestCompressImageSet_MaxBytes(t *testing.T) {
+	data := gradientPNG(t, 64, 64)
+
+	if _, err := CompressImageSet(bytes.NewReader(data), Profile{MaxBytes: int64(len(data)) - 1}); err != ErrFileTooLarge {
+		t.Errorf("expected ErrFileTooLarge, got %v", err)
+	}
+
+	set, err := CompressImageSet(bytes.NewReader(data), Profile{MaxBytes: int64(len(data))})
+	if err != nil {
+		t.Fatalf("expected image within limit to succeed, got %v", err)
+	}
+	set.Close()
+}
+
+func TestCompressImageSet_MaxDimensions(t *testing.T) {
+	data := gradientPNG(t, 200, 100)
+
+	if _, err := CompressImageSet(bytes.NewReader(data), Profile{MaxWidth: 199}); err != ErrImageTooLarge {
+		t.Errorf("expected ErrImageTooLarge for width, got %v", err)
+	}
+	if _, err := CompressImageSet(bytes.NewReader(data), Profile{MaxHeight: 99}); err != ErrImageTooLarge {
+		t.Errorf("expected ErrImageTooLarge for height, got %v", err)
+	}
+
+	// 保存された向きではなく、Orientationを反映した向きで確認する
+	// exif_orientation_6.jpgは幅48px・高さ32pxで保存され、表示すると幅32px・高さ48pxになる
+	rotated, err := os.ReadFile("../../test/exif_orientation_6.jpg")
+	if err != nil {
+		t.Fatalf("failed to read test image: %v", err)
+	}
+	set, err := CompressImageSet(bytes.NewReader(rotated), Profile{MaxWidth: 32, MaxHeight: 48})
+	if err != nil {
+		t.Fatalf("expected rotated image within limit to succeed, got %v", err)
+	}
+	set.Close()
+	if _, err := CompressImageSet(bytes.NewReader(rotated), Profile{MaxWidth: 47, MaxHeight: 47}); err != ErrImageTooLarge {
+		t.Errorf("expected ErrImageTooLarge for rotated image, got %v", err)
+	}
+}
+
+func TestCompressImageSet_MaxEdge(t *testing.T) {
+	set, err := CompressImageSet(bytes.NewReader(gradientPNG(t, 1200, 600)), Profile{MaxEdge: 500})
+	if err != nil {
+		t.Fatalf("CompressImageSet failed: %v", err)
+	}
+	defer set.Close()
+
+	config, err := webp.DecodeConfig(set.Original)
+	if err != nil {
+		t.Fatalf("failed to decode original: %v", err)
+	}
+	if config.Width != 500 || config.Height != 250 {
+		t.Errorf("expected original to be 500x250, got %dx%d", config.Width, config.Height)
+	}
+
+	// 縮小後の画像より大きい縮小画像は作らない
+	if _, ok := set.Renditions["medium"]; ok {
+		t.Error("expected medium rendition to be skipped")
+	}
+	if _, ok := set.Renditions["thumb"]; !ok {
+		t.Error("expected thumb rendition")
+	}
+}
+
+func TestCompressImageSet_Lossless(t *testing.T) {
+	data := gradientPNG(t, 32, 32)
+	src, err := png.Decode(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("failed to decode test image: %v", err)
+	}
+
+	set, err := CompressImageSet(bytes.NewReader(data), Profile{Lossless: true})
+	if err != nil {
+		t.Fatalf("CompressImageSet failed: %v", err)
+	}
+	defer set.Close()
+
+	// 可逆圧縮の画像を正確に展開できるx/image/webpで確認する
+	img, err := xwebp.Decode(set.Original)
+	if err != nil {
+		t.Fatalf("failed to decode output: %v", err)
+	}
+	for x := range 32 {
+		for y := range 32 {
+			r1, g1, b1, _ := src.At(x, y).RGBA()
+			r2, g2, b2, _ := img.At(x, y).RGBA()
+			if r1 != r2 || g1 != g2 || b1 != b2 {
+				t.Fatalf("pixel (%d, %d) differs after lossless compression", x, y)
+			}
+		}
+	}
+}
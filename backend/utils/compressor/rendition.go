@@ -0,0 +1,115 @@
+package compressor
+
+import (
+	"image"
+	"io"
+	"os"
+
+	"golang.org/x/image/draw"
+)
+
+// Rendition 縮小した画像の種類
+// MaxSizeは画像の長辺の最大ピクセル数です
+type Rendition struct {
+	Name    string
+	MaxSize int
+}
+
+// Renditions アップロード時に生成する縮小画像を、小さい順に並べたもの
+var Renditions = []Rendition{
+	{Name: "thumb", MaxSize: 320},
+	{Name: "medium", MaxSize: 1024},
+}
+
+// PickRendition 長辺がsizeピクセル以上ある最小の縮小画像の名前を返します
+// sizeが最大の縮小画像より大きい場合は、元の画像を表す空文字を返します
+func PickRendition(size int) string {
+	for _, r := range Renditions {
+		if size <= r.MaxSize {
+			return r.Name
+		}
+	}
+	return ""
+}
+
+// ImageSet 圧縮した画像と、その縮小画像
+// 元の画像より大きくなる縮小画像は含みません
+type ImageSet struct {
+	Original   *os.File
+	Format     string
+	Renditions map[string]*os.File
+}
+
+// Close 一時ファイルを閉じて削除します
+func (s *ImageSet) Close() error {
+	for _, f := range s.files() {
+		f.Close()
+		os.Remove(f.Name())
+	}
+	return nil
+}
+
+func (s *ImageSet) files() []*os.File {
+	files := make([]*os.File, 0, len(s.Renditions)+1)
+	if s.Original != nil {
+		files = append(files, s.Original)
+	}
+	for _, f := range s.Renditions {
+		files = append(files, f)
+	}
+	return files
+}
+
+// CompressImageSet profileに従って画像を圧縮し、元のサイズの画像と縮小画像をwebp形式で返します
+// 元のサイズの画像も、長辺がprofile.MaxEdgeを超える場合は縮小します
+func CompressImageSet(src io.Reader, profile Profile) (_ *ImageSet, err error) {
+	srcImage, err := decode(src, profile)
+	if err != nil {
+		return nil, err
+	}
+
+	set := &ImageSet{Format: "webp", Renditions: map[string]*os.File{}}
+	defer func() {
+		if err != nil {
+			set.Close()
+		}
+	}()
+
+	set.Original, err = encode(srcImage, profile)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, r := range Renditions {
+		resized, ok := resize(srcImage, r.MaxSize)
+		if !ok {
+			continue
+		}
+		set.Renditions[r.Name], err = encode(resized, profile)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return set, nil
+}
+
+// resize 長辺がmaxSizeになるよう縮小します
+// 元の画像の長辺がmaxSize以下の場合はfalseを返します
+func resize(src image.Image, maxSize int) (image.Image, bool) {
+	b := src.Bounds()
+	w, h := b.Dx(), b.Dy()
+	if max(w, h) <= maxSize {
+		return nil, false
+	}
+
+	if w >= h {
+		w, h = maxSize, max(1, h*maxSize/w)
+	} else {
+		w, h = max(1, w*maxSize/h), maxSize
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, w, h))
+	draw.CatmullRom.Scale(dst, dst.Bounds(), src, b, draw.Src, nil)
+	return dst, true
+}
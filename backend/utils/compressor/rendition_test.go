@@ -0,0 +1,86 @@
+package compressor
+
+import (
+	"bytes"
+	"image"
+	"image/png"
+	"testing"
+
+	"github.com/gen2brain/webp"
+)
+
+func TestPickRendition(t *testing.T) {
+	tests := []struct {
+		size int
+		want string
+	}{
+		{0, "thumb"},
+		{200, "thumb"},
+		{320, "thumb"},
+		{321, "medium"},
+		{1024, "medium"},
+		{1025, ""},
+	}
+
+	for _, tt := range tests {
+		if got := PickRendition(tt.size); got != tt.want {
+			t.Errorf("PickRendition(%d) = %q, want %q", tt.size, got, tt.want)
+		}
+	}
+}
+
+func TestCompressImageSet(t *testing.T) {
+	encode := func(w, h int) *bytes.Buffer {
+		var buf bytes.Buffer
+		if err := png.Encode(&buf, image.NewRGBA(image.Rect(0, 0, w, h))); err != nil {
+			t.Fatalf("failed to encode test image: %v", err)
+		}
+		return &buf
+	}
+
+	t.Run("large image", func(t *testing.T) {
+		set, err := CompressImageSet(encode(600, 1500), Profile{})
+		if err != nil {
+			t.Fatalf("CompressImageSet failed: %v", err)
+		}
+		defer set.Close()
+
+		want := map[string]image.Point{
+			"thumb":  {128, 320},
+			"medium": {409, 1024},
+		}
+		if len(set.Renditions) != len(want) {
+			t.Fatalf("expected %d renditions, got %d", len(want), len(set.Renditions))
+		}
+		for name, size := range want {
+			cfg, err := webp.DecodeConfig(set.Renditions[name])
+			if err != nil {
+				t.Fatalf("failed to decode %s rendition: %v", name, err)
+			}
+			if cfg.Width != size.X || cfg.Height != size.Y {
+				t.Errorf("expected %s rendition to be %v, got %dx%d", name, size, cfg.Width, cfg.Height)
+			}
+		}
+	})
+
+	t.Run("small image", func(t *testing.T) {
+		set, err := CompressImageSet(encode(500, 300), Profile{})
+		if err != nil {
+			t.Fatalf("CompressImageSet failed: %v", err)
+		}
+		defer set.Close()
+
+		if _, ok := set.Renditions["thumb"]; !ok {
+			t.Error("expected thumb rendition")
+		}
+		if _, ok := set.Renditions["medium"]; ok {
+			t.Error("expected no medium rendition larger than the original")
+		}
+	})
+
+	t.Run("invalid image", func(t *testing.T) {
+		if _, err := CompressImageSet(bytes.NewReader([]byte("not an image")), Profile{}); err != ErrInvalidImage {
+			t.Errorf("expected ErrInvalidImage, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,315 @@
+package diskcache
+
+import (
+	"container/list"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+)
+
+var ErrInvalidKey = errors.New("invalid cache key")
+
+const (
+	// tmpPrefix 書き込み途中のファイルの接頭辞
+	tmpPrefix = ".tmp-"
+	// digestPrefix ファイルの内容のSHA-256を記録するファイルの接頭辞
+	digestPrefix = ".sha256-"
+)
+
+// Cache サイズの上限を持つディスクキャッシュ
+// 上限を超えた場合は、最後に使われてから最も時間が経ったファイルから削除します
+type Cache struct {
+	dir      string
+	maxBytes int64
+
+	mu       sync.Mutex
+	lru      *list.List
+	entries  map[string]*list.Element
+	size     int64
+	inflight map[string]*call
+}
+
+type entry struct {
+	key    string
+	size   int64
+	digest string
+}
+
+// File キャッシュされたファイル
+type File struct {
+	*os.File
+	// Digest ファイルの内容のSHA-256を16進数で表したもの
+	// キャッシュに保存した時点で求めるため、読み込むたびに計算する必要はありません
+	Digest string
+}
+
+// call 実行中の取得処理
+type call struct {
+	done chan struct{}
+	err  error
+}
+
+// New dirをキャッシュとして使うCacheを作成します
+// dirに残っているファイルは更新日時の新しい順に使われたものとして引き継ぎます
+// 内容のSHA-256が記録されていないファイルは削除します
+func New(dir string, maxBytes int64) (*Cache, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+
+	c := &Cache{
+		dir:      dir,
+		maxBytes: maxBytes,
+		lru:      list.New(),
+		entries:  map[string]*list.Element{},
+		inflight: map[string]*call{},
+	}
+
+	dirEntries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	type file struct {
+		key    string
+		info   fs.FileInfo
+		digest string
+	}
+	var files []file
+	keys := map[string]bool{}
+	for _, e := range dirEntries {
+		if !e.Type().IsRegular() {
+			continue
+		}
+		// 前回の書き込み途中のファイルは使えない
+		if strings.HasPrefix(e.Name(), tmpPrefix) {
+			os.Remove(filepath.Join(dir, e.Name()))
+			continue
+		}
+		if strings.HasPrefix(e.Name(), digestPrefix) {
+			continue
+		}
+		keys[e.Name()] = true
+
+		digest, err := os.ReadFile(filepath.Join(dir, digestPrefix+e.Name()))
+		if err != nil || len(digest) != sha256.Size*2 {
+			os.Remove(filepath.Join(dir, e.Name()))
+			continue
+		}
+		info, err := e.Info()
+		if err != nil {
+			continue
+		}
+		files = append(files, file{key: e.Name(), info: info, digest: string(digest)})
+	}
+	// 対応するファイルのない記録を削除する
+	for _, e := range dirEntries {
+		if key, ok := strings.CutPrefix(e.Name(), digestPrefix); ok && !keys[key] {
+			os.Remove(filepath.Join(dir, e.Name()))
+		}
+	}
+	slices.SortFunc(files, func(a, b file) int {
+		return b.info.ModTime().Compare(a.info.ModTime())
+	})
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, f := range files {
+		c.entries[f.key] = c.lru.PushBack(&entry{key: f.key, size: f.info.Size(), digest: f.digest})
+		c.size += f.info.Size()
+	}
+	c.evict()
+
+	return c, nil
+}
+
+func (c *Cache) path(key string) (string, error) {
+	if key == "" || key != filepath.Base(key) || strings.HasPrefix(key, ".") {
+		return "", ErrInvalidKey
+	}
+	return filepath.Join(c.dir, key), nil
+}
+
+// Open キャッシュされたファイルを開きます
+// キャッシュにない場合はfetchで取得してキャッシュします
+// 同じキーの取得が同時に要求された場合、fetchは一度だけ呼ばれます
+// 先に始まった取得がキャンセルされた場合は、待っていた呼び出しが自身のfetchで取得し直します
+func (c *Cache) Open(key string, fetch func(w io.Writer) error) (*File, error) {
+	path, err := c.path(key)
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		c.mu.Lock()
+		if elem, ok := c.entries[key]; ok {
+			c.lru.MoveToFront(elem)
+			digest := elem.Value.(*entry).digest
+			c.mu.Unlock()
+
+			file, err := os.Open(path)
+			if err == nil {
+				return &File{File: file, Digest: digest}, nil
+			}
+			if !errors.Is(err, fs.ErrNotExist) {
+				return nil, err
+			}
+
+			// 外部から削除されていた場合は取得し直す
+			c.Remove(key)
+			continue
+		}
+
+		if cl, ok := c.inflight[key]; ok {
+			c.mu.Unlock()
+			<-cl.done
+			// 取得を始めたリクエストの中断は、待っていたリクエストの失敗ではない
+			if cl.err != nil && !errors.Is(cl.err, context.Canceled) && !errors.Is(cl.err, context.DeadlineExceeded) {
+				return nil, cl.err
+			}
+			continue
+		}
+
+		cl := &call{done: make(chan struct{})}
+		c.inflight[key] = cl
+		c.mu.Unlock()
+
+		file, err := c.store(key, path, fetch)
+
+		c.mu.Lock()
+		delete(c.inflight, key)
+		c.mu.Unlock()
+		cl.err = err
+		close(cl.done)
+
+		return file, err
+	}
+}
+
+// Put srcの内容をキャッシュします
+func (c *Cache) Put(key string, src io.Reader) error {
+	path, err := c.path(key)
+	if err != nil {
+		return err
+	}
+
+	file, err := c.store(key, path, func(w io.Writer) error {
+		_, err := io.Copy(w, src)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	return file.Close()
+}
+
+// store 書き込み途中のファイルが読まれないよう、一時ファイルに書き込んでから名前を変更し、開いたファイルを返します
+// 書き込みながら内容のSHA-256を求め、ファイルとあわせて記録します
+func (c *Cache) store(key, path string, fetch func(w io.Writer) error) (_ *File, err error) {
+	tmpFile, err := os.CreateTemp(c.dir, tmpPrefix+"*")
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err != nil {
+			tmpFile.Close()
+			os.Remove(tmpFile.Name())
+		}
+	}()
+
+	hash := sha256.New()
+	if err := fetch(io.MultiWriter(tmpFile, hash)); err != nil {
+		return nil, err
+	}
+	if err := tmpFile.Close(); err != nil {
+		return nil, err
+	}
+	digest := hex.EncodeToString(hash.Sum(nil))
+	if err := os.WriteFile(filepath.Join(c.dir, digestPrefix+key), []byte(digest), 0o644); err != nil {
+		return nil, err
+	}
+	if err := os.Rename(tmpFile.Name(), path); err != nil {
+		return nil, err
+	}
+
+	// 上限を超えて自身が削除されても読めるよう、登録する前に開いておく
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if elem, ok := c.entries[key]; ok {
+		c.size -= elem.Value.(*entry).size
+		c.lru.Remove(elem)
+	}
+	c.entries[key] = c.lru.PushFront(&entry{key: key, size: info.Size(), digest: digest})
+	c.size += info.Size()
+	c.evict()
+
+	return &File{File: file, Digest: digest}, nil
+}
+
+// Remove キャッシュからファイルを削除します
+func (c *Cache) Remove(key string) error {
+	path, err := c.path(key)
+	if err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	if elem, ok := c.entries[key]; ok {
+		c.size -= elem.Value.(*entry).size
+		c.lru.Remove(elem)
+		delete(c.entries, key)
+	}
+	c.mu.Unlock()
+
+	if err := os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	if err := os.Remove(filepath.Join(c.dir, digestPrefix+key)); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+// Size キャッシュされているファイルの合計サイズを返します
+func (c *Cache) Size() int64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.size
+}
+
+// evict 合計サイズが上限以下になるまで古いファイルを削除します
+// 呼び出す際はmuをロックしている必要があります
+func (c *Cache) evict() {
+	for c.size > c.maxBytes && c.lru.Len() > 0 {
+		elem := c.lru.Back()
+		e := elem.Value.(*entry)
+		c.lru.Remove(elem)
+		delete(c.entries, e.key)
+		c.size -= e.size
+
+		for _, name := range []string{e.key, digestPrefix + e.key} {
+			if err := os.Remove(filepath.Join(c.dir, name)); err != nil && !errors.Is(err, fs.ErrNotExist) {
+				slog.Warn("failed to evict cached file", "key", e.key, "error", err)
+			}
+		}
+	}
+}
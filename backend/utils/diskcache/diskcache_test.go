@@ -0,0 +1,320 @@
+package diskcache
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func fetchString(s string) func(w io.Writer) error {
+	return func(w io.Writer) error {
+		_, err := io.WriteString(w, s)
+		return err
+	}
+}
+
+func readAll(t *testing.T, f *File) string {
+	t.Helper()
+	defer f.Close()
+	data, err := io.ReadAll(f)
+	if err != nil {
+		t.Fatalf("failed to read cached file: %v", err)
+	}
+	return string(data)
+}
+
+func digestOf(s string) string {
+	sum := sha256.Sum256([]byte(s))
+	return hex.EncodeToString(sum[:])
+}
+
+func TestCache_Open(t *testing.T) {
+	c, err := New(t.TempDir(), 1<<20)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+
+	var calls int
+	fetch := func(w io.Writer) error {
+		calls++
+		return fetchString("hello")(w)
+	}
+
+	for range 2 {
+		f, err := c.Open("a.webp", fetch)
+		if err != nil {
+			t.Fatalf("Open failed: %v", err)
+		}
+		if f.Digest != digestOf("hello") {
+			t.Errorf("expected digest of %q, got %q", "hello", f.Digest)
+		}
+		if got := readAll(t, f); got != "hello" {
+			t.Errorf("expected %q, got %q", "hello", got)
+		}
+	}
+	if calls != 1 {
+		t.Errorf("expected fetch to be called once, got %d", calls)
+	}
+	if c.Size() != 5 {
+		t.Errorf("expected size 5, got %d", c.Size())
+	}
+}
+
+func TestCache_FetchError(t *testing.T) {
+	dir := t.TempDir()
+	c, err := New(dir, 1<<20)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+
+	errFetch := errors.New("fetch failed")
+	_, err = c.Open("a.webp", func(w io.Writer) error {
+		io.WriteString(w, "partial")
+		return errFetch
+	})
+	if !errors.Is(err, errFetch) {
+		t.Fatalf("expected fetch error, got %v", err)
+	}
+
+	// 中途半端なファイルが残っていないこと
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("failed to read dir: %v", err)
+	}
+	if len(entries) != 0 {
+		t.Errorf("expected no files after failed fetch, got %v", entries)
+	}
+}
+
+func TestCache_Evict(t *testing.T) {
+	dir := t.TempDir()
+	c, err := New(dir, 10)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+
+	for _, key := range []string{"a", "b"} {
+		if err := c.Put(key, strings.NewReader("12345")); err != nil {
+			t.Fatalf("Put failed: %v", err)
+		}
+	}
+
+	// aを使ってからcを追加すると、最も使われていないbが削除される
+	f, err := c.Open("a", fetchString("unused"))
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	f.Close()
+	if err := c.Put("c", strings.NewReader("12345")); err != nil {
+		t.Fatalf("Put failed: %v", err)
+	}
+
+	if _, err := os.Stat(filepath.Join(dir, "b")); !os.IsNotExist(err) {
+		t.Errorf("expected b to be evicted, got %v", err)
+	}
+	for _, key := range []string{"a", "c"} {
+		if _, err := os.Stat(filepath.Join(dir, key)); err != nil {
+			t.Errorf("expected %s to be cached, got %v", key, err)
+		}
+	}
+	if c.Size() != 10 {
+		t.Errorf("expected size 10, got %d", c.Size())
+	}
+}
+
+func TestCache_TooLarge(t *testing.T) {
+	dir := t.TempDir()
+	c, err := New(dir, 4)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+
+	// 上限を超えるファイルはキャッシュされないが、読むことはできる
+	f, err := c.Open("a", fetchString("hello"))
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	if got := readAll(t, f); got != "hello" {
+		t.Errorf("expected %q, got %q", "hello", got)
+	}
+	if c.Size() != 0 {
+		t.Errorf("expected size 0, got %d", c.Size())
+	}
+}
+
+func TestCache_SingleFlight(t *testing.T) {
+	c, err := New(t.TempDir(), 1<<20)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+
+	var calls atomic.Int32
+	release := make(chan struct{})
+	fetch := func(w io.Writer) error {
+		calls.Add(1)
+		<-release
+		return fetchString("hello")(w)
+	}
+
+	const n = 10
+	var wg sync.WaitGroup
+	results := make([]string, n)
+	errs := make([]error, n)
+	for i := range n {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			f, err := c.Open("a", fetch)
+			if err != nil {
+				errs[i] = err
+				return
+			}
+			defer f.Close()
+			data, err := io.ReadAll(f)
+			results[i], errs[i] = string(data), err
+		}()
+	}
+
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	if calls.Load() != 1 {
+		t.Errorf("expected fetch to be called once, got %d", calls.Load())
+	}
+	for i := range n {
+		if errs[i] != nil {
+			t.Errorf("Open %d failed: %v", i, errs[i])
+		} else if results[i] != "hello" {
+			t.Errorf("expected %q, got %q", "hello", results[i])
+		}
+	}
+}
+
+func TestCache_SingleFlightCanceled(t *testing.T) {
+	c, err := New(t.TempDir(), 1<<20)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+
+	// 先に取得を始めたリクエストが中断される
+	started := make(chan struct{})
+	cancel := make(chan struct{})
+	leaderErr := make(chan error, 1)
+	go func() {
+		_, err := c.Open("a", func(w io.Writer) error {
+			close(started)
+			<-cancel
+			return context.Canceled
+		})
+		leaderErr <- err
+	}()
+	<-started
+
+	waiterErr := make(chan error, 1)
+	var result string
+	go func() {
+		f, err := c.Open("a", fetchString("hello"))
+		if err == nil {
+			result = readAll(t, f)
+		}
+		waiterErr <- err
+	}()
+
+	time.Sleep(50 * time.Millisecond)
+	close(cancel)
+
+	if err := <-leaderErr; !errors.Is(err, context.Canceled) {
+		t.Errorf("expected leader to be canceled, got %v", err)
+	}
+	if err := <-waiterErr; err != nil {
+		t.Fatalf("expected waiter to fetch again, got %v", err)
+	}
+	if result != "hello" {
+		t.Errorf("expected %q, got %q", "hello", result)
+	}
+}
+
+func TestCache_Reload(t *testing.T) {
+	dir := t.TempDir()
+	c, err := New(dir, 1<<20)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	if err := c.Put("a", strings.NewReader("12345")); err != nil {
+		t.Fatalf("Put failed: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, tmpPrefix+"leftover"), []byte("x"), 0o644); err != nil {
+		t.Fatalf("failed to write leftover file: %v", err)
+	}
+	// ハッシュが記録されていないファイルは使えない
+	if err := os.WriteFile(filepath.Join(dir, "b"), []byte("x"), 0o644); err != nil {
+		t.Fatalf("failed to write file without digest: %v", err)
+	}
+
+	c, err = New(dir, 1<<20)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	if c.Size() != 5 {
+		t.Errorf("expected size 5 after reload, got %d", c.Size())
+	}
+	if _, err := os.Stat(filepath.Join(dir, tmpPrefix+"leftover")); !os.IsNotExist(err) {
+		t.Errorf("expected leftover temp file to be removed, got %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "b")); !os.IsNotExist(err) {
+		t.Errorf("expected file without digest to be removed, got %v", err)
+	}
+
+	f, err := c.Open("a", func(io.Writer) error {
+		t.Error("fetch should not be called for reloaded entry")
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	if f.Digest != digestOf("12345") {
+		t.Errorf("expected digest to be reloaded, got %q", f.Digest)
+	}
+	if got := readAll(t, f); got != "12345" {
+		t.Errorf("expected %q, got %q", "12345", got)
+	}
+}
+
+func TestCache_Remove(t *testing.T) {
+	dir := t.TempDir()
+	c, err := New(dir, 1<<20)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	if err := c.Put("a", strings.NewReader("12345")); err != nil {
+		t.Fatalf("Put failed: %v", err)
+	}
+	if err := c.Remove("a"); err != nil {
+		t.Fatalf("Remove failed: %v", err)
+	}
+	if err := c.Remove("a"); err != nil {
+		t.Errorf("expected removing missing key to succeed, got %v", err)
+	}
+	if c.Size() != 0 {
+		t.Errorf("expected size 0, got %d", c.Size())
+	}
+	if entries, err := os.ReadDir(dir); err != nil || len(entries) != 0 {
+		t.Errorf("expected file and digest to be removed, got %v (%v)", entries, err)
+	}
+
+	for _, key := range []string{"", "..", "../a", "dir/a", tmpPrefix + "a"} {
+		if _, err := c.Open(key, fetchString("x")); !errors.Is(err, ErrInvalidKey) {
+			t.Errorf("expected ErrInvalidKey for %q, got %v", key, err)
+		}
+	}
+}
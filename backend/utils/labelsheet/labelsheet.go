@@ -0,0 +1,196 @@
+package labelsheet
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/go-pdf/fpdf"
+	"github.com/skip2/go-qrcode"
+)
+
+// Template A4用紙に並んだラベルシートの寸法(mm)
+type Template struct {
+	Name       string
+	Columns    int
+	Rows       int
+	Width      float64
+	Height     float64
+	MarginLeft float64
+	MarginTop  float64
+	GapX       float64
+	GapY       float64
+}
+
+// PerSheet 1枚のシートに含まれるラベルの数を返します
+func (t Template) PerSheet() int {
+	return t.Columns * t.Rows
+}
+
+// Templates 利用できるラベルシート
+var Templates = map[string]Template{
+	"a4-10": {Name: "a4-10", Columns: 2, Rows: 5, Width: 86.4, Height: 50.8, MarginLeft: 14.0, MarginTop: 21.5, GapX: 9.2},
+	"a4-12": {Name: "a4-12", Columns: 2, Rows: 6, Width: 86.4, Height: 42.3, MarginLeft: 18.6, MarginTop: 21.6},
+	"a4-24": {Name: "a4-24", Columns: 3, Rows: 8, Width: 66.0, Height: 33.9, MarginLeft: 6.0, MarginTop: 12.9},
+}
+
+// DefaultTemplate テンプレートが指定されていない場合に用いるラベルシート
+const DefaultTemplate = "a4-12"
+
+// Label 1枚のラベルに印刷する内容
+type Label struct {
+	Name     string
+	Festival string
+	ID       string
+	// QR QRコードにする文字列
+	QR string
+}
+
+// Options 印刷の設定
+type Options struct {
+	// Skip 1枚目のシートで使用済みのラベルの数
+	Skip int
+	// Outline ラベルの枠線を印刷するか
+	Outline bool
+	// FontPath 日本語を含むTrueTypeフォントのパス
+	// 空文字の場合は組み込みのフォントを用い、ASCII以外の文字は?で置き換える
+	FontPath string
+}
+
+const (
+	padding  = 3.0
+	fontName = "label"
+)
+
+// Render ラベルをテンプレートに並べたPDFをwに書き込みます
+func Render(w io.Writer, tmpl Template, labels []Label, opts Options) error {
+	pdf := fpdf.New("P", "mm", "A4", "")
+	pdf.SetAutoPageBreak(false, 0)
+	pdf.SetCellMargin(0)
+	pdf.SetTitle("Poster Labels", true)
+
+	family, sanitize := "Helvetica", toASCII
+	if opts.FontPath != "" {
+		pdf.AddUTF8Font(fontName, "", opts.FontPath)
+		family, sanitize = fontName, func(s string) string { return s }
+	}
+
+	skip := opts.Skip % tmpl.PerSheet()
+	for i, label := range labels {
+		pos := i + skip
+		if i == 0 || pos%tmpl.PerSheet() == 0 {
+			pdf.AddPage()
+		}
+
+		cell := pos % tmpl.PerSheet()
+		x := tmpl.MarginLeft + float64(cell%tmpl.Columns)*(tmpl.Width+tmpl.GapX)
+		y := tmpl.MarginTop + float64(cell/tmpl.Columns)*(tmpl.Height+tmpl.GapY)
+
+		if err := drawLabel(pdf, x, y, tmpl, label, family, sanitize, opts.Outline, i); err != nil {
+			return err
+		}
+	}
+
+	// ラベルがない場合も空のシートを返す
+	if len(labels) == 0 {
+		pdf.AddPage()
+	}
+
+	if err := pdf.Error(); err != nil {
+		return fmt.Errorf("failed to render labels: %w", err)
+	}
+	return pdf.Output(w)
+}
+
+func drawLabel(pdf *fpdf.Fpdf, x, y float64, tmpl Template, label Label, family string, sanitize func(string) string, outline bool, index int) error {
+	if outline {
+		pdf.SetDrawColor(180, 180, 180)
+		pdf.SetDashPattern([]float64{1, 1}, 0)
+		pdf.Rect(x, y, tmpl.Width, tmpl.Height, "D")
+		pdf.SetDashPattern([]float64{}, 0)
+	}
+
+	// QRコードはラベルの右側に、ラベルの高さいっぱいに配置する
+	qrSize := min(tmpl.Height-2*padding, tmpl.Width/2)
+	if label.QR != "" {
+		png, err := qrcode.Encode(label.QR, qrcode.Medium, 512)
+		if err != nil {
+			return fmt.Errorf("failed to encode QR code: %w", err)
+		}
+		name := fmt.Sprintf("qr-%d", index)
+		pdf.RegisterImageOptionsReader(name, fpdf.ImageOptions{ImageType: "PNG"}, bytes.NewReader(png))
+		pdf.ImageOptions(name, x+tmpl.Width-padding-qrSize, y+(tmpl.Height-qrSize)/2, qrSize, qrSize, false, fpdf.ImageOptions{ImageType: "PNG"}, 0, "")
+	}
+
+	textX := x + padding
+	textW := tmpl.Width - 3*padding - qrSize
+	cursor := y + padding
+
+	pdf.SetTextColor(90, 90, 90)
+	cursor = writeLines(pdf, family, 7, textX, cursor, textW, sanitize(label.Festival), 1)
+
+	pdf.SetTextColor(0, 0, 0)
+	cursor = writeLines(pdf, family, 11, textX, cursor+1, textW, sanitize(label.Name), 2)
+
+	// IDはラベルの下端に揃える
+	pdf.SetTextColor(60, 60, 60)
+	idLines := wrap(pdf, family, 6, label.ID, textW, 2)
+	idY := max(cursor+1, y+tmpl.Height-padding-float64(len(idLines))*lineHeight(6))
+	writeLines(pdf, family, 6, textX, idY, textW, label.ID, 2)
+
+	return nil
+}
+
+// writeLines 幅wに収まるよう折り返して最大maxLines行を書き込み、書き込んだ後のy座標を返します
+func writeLines(pdf *fpdf.Fpdf, family string, size, x, y, w float64, text string, maxLines int) float64 {
+	for _, line := range wrap(pdf, family, size, text, w, maxLines) {
+		pdf.SetXY(x, y)
+		pdf.CellFormat(w, lineHeight(size), line, "", 0, "L", false, 0, "")
+		y += lineHeight(size)
+	}
+	return y
+}
+
+// wrap 幅wに収まるよう文字単位で折り返します
+// maxLinesを超える場合は最後の行の末尾を省略記号にします
+func wrap(pdf *fpdf.Fpdf, family string, size float64, text string, w float64, maxLines int) []string {
+	pdf.SetFont(family, "", size)
+
+	var lines []string
+	var line []rune
+	for _, r := range text {
+		if pdf.GetStringWidth(string(append(line, r))) > w && len(line) > 0 {
+			lines = append(lines, string(line))
+			line = nil
+		}
+		line = append(line, r)
+	}
+	if len(line) > 0 {
+		lines = append(lines, string(line))
+	}
+
+	if len(lines) > maxLines {
+		last := []rune(lines[maxLines-1])
+		for len(last) > 0 && pdf.GetStringWidth(string(last)+"...") > w {
+			last = last[:len(last)-1]
+		}
+		lines = append(lines[:maxLines-1], string(last)+"...")
+	}
+	return lines
+}
+
+// lineHeight フォントサイズ(pt)に対する行の高さ(mm)を返します
+func lineHeight(size float64) float64 {
+	return size * 0.3528 * 1.25
+}
+
+// toASCII 組み込みのフォントで表示できない文字を?で置き換えます
+func toASCII(s string) string {
+	return strings.Map(func(r rune) rune {
+		if r < 0x20 || r > 0x7e {
+			return '?'
+		}
+		return r
+	}, s)
+}
@@ -0,0 +1,63 @@
+package labelsheet
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"testing"
+)
+
+func countPages(pdf []byte) int {
+	return len(regexp.MustCompile(`/Type /Page\b`).FindAll(pdf, -1))
+}
+
+func TestRender(t *testing.T) {
+	tmpl := Templates[DefaultTemplate]
+
+	labels := make([]Label, tmpl.PerSheet()+1)
+	for i := range labels {
+		labels[i] = Label{
+			Name:     fmt.Sprintf("ポスター %d with a very long name that does not fit on a single label", i),
+			Festival: "Test Festival",
+			ID:       "0199a3c4-7e1b-7c3d-9f2a-0123456789ab",
+			QR:       "http://localhost:8080/api/v1/actions/token",
+		}
+	}
+
+	tests := []struct {
+		name   string
+		labels []Label
+		opts   Options
+		pages  int
+	}{
+		{"empty", nil, Options{}, 1},
+		{"one sheet", labels[:tmpl.PerSheet()], Options{}, 1},
+		{"overflow to second sheet", labels, Options{Outline: true}, 2},
+		{"skip used labels", labels[:tmpl.PerSheet()], Options{Skip: 1}, 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := Render(&buf, tmpl, tt.labels, tt.opts); err != nil {
+				t.Fatalf("Render failed: %v", err)
+			}
+			if !bytes.HasPrefix(buf.Bytes(), []byte("%PDF-")) {
+				t.Fatal("output is not a PDF")
+			}
+			if got := countPages(buf.Bytes()); got != tt.pages {
+				t.Errorf("expected %d pages, got %d", tt.pages, got)
+			}
+		})
+	}
+}
+
+func TestTemplatesFitA4(t *testing.T) {
+	for name, tmpl := range Templates {
+		width := tmpl.MarginLeft + float64(tmpl.Columns)*tmpl.Width + float64(tmpl.Columns-1)*tmpl.GapX
+		height := tmpl.MarginTop + float64(tmpl.Rows)*tmpl.Height + float64(tmpl.Rows-1)*tmpl.GapY
+		if width > 210 || height > 297 {
+			t.Errorf("template %s does not fit on A4: %.1fx%.1f", name, width, height)
+		}
+	}
+}
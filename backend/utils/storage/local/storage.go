@@ -0,0 +1,251 @@
+package local
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/Luke256/ducks/utils/compressor"
+	"github.com/Luke256/ducks/utils/storage"
+	"github.com/google/uuid"
+)
+
+// digestPrefix ファイルの内容のSHA-256を記録するファイルの接頭辞
+// ドットで始まるため、ファイルとして取得・一覧されることはありません
+const digestPrefix = ".sha256-"
+
+// LocalStorage ファイルをローカルのディレクトリに保存するStorageの実装
+type LocalStorage struct {
+	root string
+}
+
+func NewLocalStorage(root string) (*LocalStorage, error) {
+	root, err := filepath.Abs(root)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := os.MkdirAll(root, 0o755); err != nil {
+		return nil, err
+	}
+
+	slog.Info("Local Storage initialized", "root", root)
+
+	return &LocalStorage{root: root}, nil
+}
+
+// path ファイル名から保存先のパスを返します
+// ルートディレクトリの外を指すファイル名の場合はErrFileNotFoundを返します
+func (s *LocalStorage) path(fileName string) (string, error) {
+	if fileName == "" || fileName != filepath.Base(fileName) || strings.HasPrefix(fileName, ".") {
+		return "", storage.ErrFileNotFound
+	}
+	return filepath.Join(s.root, fileName), nil
+}
+
+func (s *LocalStorage) UploadFile(ctx context.Context, src io.Reader, meta storage.Metadata, profile compressor.Profile) (string, error) {
+	if err := meta.Validate(); err != nil {
+		return "", err
+	}
+
+	images, err := compressor.CompressImageSet(src, profile)
+	if err != nil {
+		return "", err
+	}
+	defer images.Close()
+
+	// 圧縮に時間がかかるため、書き込む前にキャンセルされていないか確認する
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+
+	fileID, err := uuid.NewV7()
+	if err != nil {
+		return "", err
+	}
+
+	fileName := fmt.Sprintf("%s.%s", fileID.String(), strings.ToLower(images.Format))
+
+	// 元の画像が保存された時点で縮小画像が揃っているよう、縮小画像から書き込む
+	for name, f := range images.Renditions {
+		if err := s.writeFile(storage.RenditionFileName(fileName, name), f); err != nil {
+			return "", err
+		}
+	}
+	if err := s.writeFile(fileName, images.Original); err != nil {
+		return "", err
+	}
+
+	return fileName, nil
+}
+
+// PutFile ファイルをそのまま保存します
+func (s *LocalStorage) PutFile(ctx context.Context, fileName string, src io.Reader, meta storage.Metadata) error {
+	if _, err := s.path(fileName); err != nil {
+		return err
+	}
+	return s.writeFile(fileName, src)
+}
+
+// writeFile ファイルを保存し、書き込みながら求めた内容のSHA-256を記録します
+func (s *LocalStorage) writeFile(fileName string, src io.Reader) error {
+	hash := sha256.New()
+	if err := s.writeAtomic(fileName, io.TeeReader(src, hash)); err != nil {
+		return err
+	}
+	return s.writeAtomic(digestPrefix+fileName, strings.NewReader(hex.EncodeToString(hash.Sum(nil))))
+}
+
+// digest 記録されたファイルの内容のSHA-256を返します
+// 記録する前に保存されたファイルの場合は、ここで一度だけ求めて記録します
+func (s *LocalStorage) digest(fileName string, file io.ReadSeeker) (string, error) {
+	digest, err := os.ReadFile(filepath.Join(s.root, digestPrefix+fileName))
+	if err == nil && len(digest) == sha256.Size*2 {
+		return string(digest), nil
+	}
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, file); err != nil {
+		return "", err
+	}
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		return "", err
+	}
+	sum := hex.EncodeToString(hash.Sum(nil))
+
+	// 記録できなくても、次回に求め直せばよい
+	if err := s.writeAtomic(digestPrefix+fileName, strings.NewReader(sum)); err != nil {
+		slog.Warn("failed to record file digest", "file_name", fileName, "error", err)
+	}
+	return sum, nil
+}
+
+// writeAtomic 書き込み途中のファイルが読まれないよう、一時ファイルに書き込んでから名前を変更します
+func (s *LocalStorage) writeAtomic(fileName string, src io.Reader) (err error) {
+	tmpFile, err := os.CreateTemp(s.root, ".upload-*")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			tmpFile.Close()
+			os.Remove(tmpFile.Name())
+		}
+	}()
+
+	if _, err := io.Copy(tmpFile, src); err != nil {
+		return err
+	}
+	if err := tmpFile.Sync(); err != nil {
+		return err
+	}
+	if err := tmpFile.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmpFile.Name(), filepath.Join(s.root, fileName))
+}
+
+func (s *LocalStorage) DownloadFile(ctx context.Context, fileName string) (io.ReadSeekCloser, storage.Metadata, error) {
+	path, err := s.path(fileName)
+	if err != nil {
+		return nil, storage.Metadata{}, err
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, storage.Metadata{}, storage.ErrFileNotFound
+		}
+		return nil, storage.Metadata{}, err
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return nil, storage.Metadata{}, err
+	}
+
+	digest, err := s.digest(fileName, file)
+	if err != nil {
+		file.Close()
+		return nil, storage.Metadata{}, err
+	}
+
+	// 保存した形式はファイル名の拡張子に表れている
+	return file, storage.Metadata{
+		ContentType: storage.ContentTypeByFileName(fileName),
+		Size:        info.Size(),
+		ModTime:     info.ModTime(),
+		Digest:      digest,
+	}, nil
+}
+
+// ListFiles ルートディレクトリにあるファイルの一覧を返します
+// 書き込み途中の一時ファイルは含みません
+func (s *LocalStorage) ListFiles(ctx context.Context) ([]storage.FileInfo, error) {
+	entries, err := os.ReadDir(s.root)
+	if err != nil {
+		return nil, err
+	}
+
+	files := make([]storage.FileInfo, 0, len(entries))
+	for _, entry := range entries {
+		if !entry.Type().IsRegular() || strings.HasPrefix(entry.Name(), ".") {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			// 一覧を取得してから削除された
+			if errors.Is(err, fs.ErrNotExist) {
+				continue
+			}
+			return nil, err
+		}
+		files = append(files, storage.FileInfo{
+			Name:    entry.Name(),
+			Size:    info.Size(),
+			ModTime: info.ModTime(),
+		})
+	}
+	return files, nil
+}
+
+// DeleteFile ファイルと、その縮小画像を削除します
+// 記録した内容のSHA-256もあわせて削除します
+func (s *LocalStorage) DeleteFile(ctx context.Context, fileName string) error {
+	if _, err := s.path(fileName); err != nil {
+		return err
+	}
+
+	names := []string{fileName}
+	for _, r := range compressor.Renditions {
+		names = append(names, storage.RenditionFileName(fileName, r.Name))
+	}
+
+	var paths []string
+	for _, name := range names {
+		paths = append(paths, filepath.Join(s.root, name), filepath.Join(s.root, digestPrefix+name))
+	}
+
+	// S3と同様に、存在しないファイルの削除はエラーにしない
+	for _, p := range paths {
+		if err := os.Remove(p); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *LocalStorage) GetFileURL(fileName string) string {
+	endpoint := os.Getenv("API_ENDPOINT")
+	return fmt.Sprintf("%s/api/v1/images/%s", endpoint, fileName)
+}
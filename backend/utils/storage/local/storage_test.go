@@ -0,0 +1,288 @@
+package local
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Luke256/ducks/utils/compressor"
+	"github.com/Luke256/ducks/utils/storage"
+)
+
+func testImage(t *testing.T) []byte {
+	t.Helper()
+	return testImageSized(t, 16, 16)
+}
+
+func testImageSized(t *testing.T, w, h int) []byte {
+	t.Helper()
+
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	for x := range w {
+		for y := range h {
+			img.Set(x, y, color.RGBA{uint8(x * 16), uint8(y * 16), 128, 255})
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatalf("failed to encode test image: %v", err)
+	}
+	return buf.Bytes()
+}
+
+func TestLocalStorage(t *testing.T) {
+	root := filepath.Join(t.TempDir(), "images")
+	s, err := NewLocalStorage(root)
+	if err != nil {
+		t.Fatalf("NewLocalStorage failed: %v", err)
+	}
+
+	fileName, err := s.UploadFile(context.Background(), bytes.NewReader(testImage(t)), storage.Metadata{ContentType: "image/png"}, compressor.Profile{})
+	if err != nil {
+		t.Fatalf("UploadFile failed: %v", err)
+	}
+	if filepath.Ext(fileName) != ".webp" {
+		t.Errorf("expected .webp file, got %q", fileName)
+	}
+
+	entries, err := os.ReadDir(root)
+	if err != nil {
+		t.Fatalf("failed to read root: %v", err)
+	}
+	if len(entries) != 2 || entries[0].Name() != digestPrefix+fileName || entries[1].Name() != fileName {
+		t.Errorf("expected only %q and its digest in root, got %v", fileName, entries)
+	}
+
+	file, meta, err := s.DownloadFile(context.Background(), fileName)
+	if err != nil {
+		t.Fatalf("DownloadFile failed: %v", err)
+	}
+	if meta.ContentType != "image/webp" {
+		t.Errorf("expected content type image/webp, got %q", meta.ContentType)
+	}
+	data, err := io.ReadAll(file)
+	file.Close()
+	if err != nil {
+		t.Fatalf("failed to read file: %v", err)
+	}
+	if len(data) == 0 {
+		t.Error("downloaded file is empty")
+	}
+	if sum := sha256.Sum256(data); meta.Digest != hex.EncodeToString(sum[:]) {
+		t.Errorf("expected digest of downloaded file, got %q", meta.Digest)
+	}
+
+	if err := s.DeleteFile(context.Background(), fileName); err != nil {
+		t.Fatalf("DeleteFile failed: %v", err)
+	}
+	if _, _, err := s.DownloadFile(context.Background(), fileName); err != storage.ErrFileNotFound {
+		t.Errorf("expected ErrFileNotFound after delete, got %v", err)
+	}
+	if err := s.DeleteFile(context.Background(), fileName); err != nil {
+		t.Errorf("expected deleting missing file to succeed, got %v", err)
+	}
+}
+
+func TestLocalStorage_InvalidFileName(t *testing.T) {
+	s, err := NewLocalStorage(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewLocalStorage failed: %v", err)
+	}
+
+	for _, name := range []string{"", ".", "..", "../secret", "dir/file.webp", ".upload-123"} {
+		if _, _, err := s.DownloadFile(context.Background(), name); err != storage.ErrFileNotFound {
+			t.Errorf("expected ErrFileNotFound for %q, got %v", name, err)
+		}
+		if err := s.DeleteFile(context.Background(), name); err != storage.ErrFileNotFound {
+			t.Errorf("expected ErrFileNotFound when deleting %q, got %v", name, err)
+		}
+		if err := s.PutFile(context.Background(), name, bytes.NewReader([]byte("data")), storage.Metadata{}); err != storage.ErrFileNotFound {
+			t.Errorf("expected ErrFileNotFound when putting %q, got %v", name, err)
+		}
+	}
+}
+
+func TestLocalStorage_InvalidImage(t *testing.T) {
+	root := t.TempDir()
+	s, err := NewLocalStorage(root)
+	if err != nil {
+		t.Fatalf("NewLocalStorage failed: %v", err)
+	}
+
+	if _, err := s.UploadFile(context.Background(), bytes.NewReader([]byte("not an image")), storage.Metadata{}, compressor.Profile{}); err == nil {
+		t.Fatal("expected error for invalid image")
+	}
+
+	if _, err := s.UploadFile(context.Background(), bytes.NewReader(testImage(t)), storage.Metadata{ContentType: "text/plain"}, compressor.Profile{}); err != storage.ErrUnsupportedContentType {
+		t.Errorf("expected ErrUnsupportedContentType, got %v", err)
+	}
+
+	entries, err := os.ReadDir(root)
+	if err != nil {
+		t.Fatalf("failed to read root: %v", err)
+	}
+	if len(entries) != 0 {
+		t.Errorf("expected no files after failed upload, got %v", entries)
+	}
+}
+
+func TestLocalStorage_Canceled(t *testing.T) {
+	root := t.TempDir()
+	s, err := NewLocalStorage(root)
+	if err != nil {
+		t.Fatalf("NewLocalStorage failed: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, err := s.UploadFile(ctx, bytes.NewReader(testImage(t)), storage.Metadata{}, compressor.Profile{}); err != context.Canceled {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+}
+
+func TestLocalStorage_Renditions(t *testing.T) {
+	root := t.TempDir()
+	s, err := NewLocalStorage(root)
+	if err != nil {
+		t.Fatalf("NewLocalStorage failed: %v", err)
+	}
+
+	fileName, err := s.UploadFile(context.Background(), bytes.NewReader(testImageSized(t, 1200, 600)), storage.Metadata{}, compressor.Profile{})
+	if err != nil {
+		t.Fatalf("UploadFile failed: %v", err)
+	}
+
+	for _, name := range []string{"thumb", "medium"} {
+		file, _, err := s.DownloadFile(context.Background(), storage.RenditionFileName(fileName, name))
+		if err != nil {
+			t.Fatalf("failed to download %s rendition: %v", name, err)
+		}
+		file.Close()
+	}
+
+	if err := s.DeleteFile(context.Background(), fileName); err != nil {
+		t.Fatalf("DeleteFile failed: %v", err)
+	}
+
+	entries, err := os.ReadDir(root)
+	if err != nil {
+		t.Fatalf("failed to read root: %v", err)
+	}
+	if len(entries) != 0 {
+		t.Errorf("expected renditions to be deleted, got %v", entries)
+	}
+}
+
+func TestLocalStorage_ListFiles(t *testing.T) {
+	root := t.TempDir()
+	s, err := NewLocalStorage(root)
+	if err != nil {
+		t.Fatalf("NewLocalStorage failed: %v", err)
+	}
+
+	fileName, err := s.UploadFile(context.Background(), bytes.NewReader(testImageSized(t, 1200, 600)), storage.Metadata{}, compressor.Profile{})
+	if err != nil {
+		t.Fatalf("UploadFile failed: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(root, ".upload-123"), []byte("partial"), 0o644); err != nil {
+		t.Fatalf("failed to write temp file: %v", err)
+	}
+
+	files, err := s.ListFiles(context.Background())
+	if err != nil {
+		t.Fatalf("ListFiles failed: %v", err)
+	}
+
+	want := map[string]bool{
+		fileName: true,
+		storage.RenditionFileName(fileName, "thumb"):  true,
+		storage.RenditionFileName(fileName, "medium"): true,
+	}
+	if len(files) != len(want) {
+		t.Fatalf("expected %d files, got %v", len(want), files)
+	}
+	for _, f := range files {
+		if !want[f.Name] {
+			t.Errorf("unexpected file %q", f.Name)
+		}
+		if f.Size == 0 || f.ModTime.IsZero() {
+			t.Errorf("expected size and mod time for %q, got %+v", f.Name, f)
+		}
+	}
+}
+
+func TestLocalStorage_PutFile(t *testing.T) {
+	root := t.TempDir()
+	s, err := NewLocalStorage(root)
+	if err != nil {
+		t.Fatalf("NewLocalStorage failed: %v", err)
+	}
+
+	// 圧縮されずにそのまま保存される
+	fileName := "0190c5a4-0000-7000-8000-000000000001_thumb.webp"
+	if err := s.PutFile(context.Background(), fileName, bytes.NewReader([]byte("raw data")), storage.Metadata{ContentType: "image/webp"}); err != nil {
+		t.Fatalf("PutFile failed: %v", err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(root, fileName))
+	if err != nil {
+		t.Fatalf("failed to read file: %v", err)
+	}
+	if string(data) != "raw data" {
+		t.Errorf("expected raw data, got %q", data)
+	}
+
+	entries, err := os.ReadDir(root)
+	if err != nil {
+		t.Fatalf("failed to read root: %v", err)
+	}
+	if len(entries) != 2 {
+		t.Errorf("expected no temporary files left, got %v", entries)
+	}
+}
+
+func TestLocalStorage_DigestNotRecorded(t *testing.T) {
+	root := t.TempDir()
+	s, err := NewLocalStorage(root)
+	if err != nil {
+		t.Fatalf("NewLocalStorage failed: %v", err)
+	}
+
+	// ハッシュを記録する前に保存されたファイル
+	fileName := "0190c5a4-0000-7000-8000-000000000001.webp"
+	if err := os.WriteFile(filepath.Join(root, fileName), []byte("raw data"), 0o644); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+
+	file, meta, err := s.DownloadFile(context.Background(), fileName)
+	if err != nil {
+		t.Fatalf("DownloadFile failed: %v", err)
+	}
+	data, err := io.ReadAll(file)
+	file.Close()
+	if err != nil {
+		t.Fatalf("failed to read file: %v", err)
+	}
+	if string(data) != "raw data" {
+		t.Errorf("expected file to be read from the start, got %q", data)
+	}
+
+	sum := sha256.Sum256([]byte("raw data"))
+	if meta.Digest != hex.EncodeToString(sum[:]) {
+		t.Errorf("expected digest of file, got %q", meta.Digest)
+	}
+	recorded, err := os.ReadFile(filepath.Join(root, digestPrefix+fileName))
+	if err != nil || string(recorded) != meta.Digest {
+		t.Errorf("expected digest to be recorded, got %q (%v)", recorded, err)
+	}
+}
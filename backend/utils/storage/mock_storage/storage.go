@@ -1,27 +1,119 @@
 package mockstorage
 
 import (
+	"bytes"
+	"context"
 	"io"
-	"mime/multipart"
+	"sync"
+	"time"
 
+	"github.com/Luke256/ducks/utils/compressor"
+	"github.com/Luke256/ducks/utils/storage"
 	"github.com/google/uuid"
 )
 
 // MockStorage は Storage インターフェースのモック実装
 // S3を使わず、ローカルファイルシステムに保存します
 
-type MockStorage struct {}
+type MockStorage struct {
+	mu      sync.Mutex
+	uploads map[string]mockUpload
+	files   map[string]struct{}
+}
+
+type mockUpload struct {
+	data []byte
+	meta storage.Metadata
+}
+
+
+func (s *MockStorage) UploadFile(ctx context.Context, src io.Reader, meta storage.Metadata, profile compressor.Profile) (string, error) {
+	if err := meta.Validate(); err != nil {
+		return "", err
+	}
+	// 実際のストレージと同様に、profileの上限を超えるファイルは保存しない
+	if profile.MaxBytes > 0 {
+		n, err := io.Copy(io.Discard, io.LimitReader(src, profile.MaxBytes+1))
+		if err != nil {
+			return "", err
+		}
+		if n > profile.MaxBytes {
+			return "", compressor.ErrFileTooLarge
+		}
+	}
+
+	fileName := uuid.NewString()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.files == nil {
+		s.files = map[string]struct{}{}
+	}
+	s.files[fileName] = struct{}{}
+	return fileName, nil
+}
+
+func (s *MockStorage) PutFile(ctx context.Context, fileName string, src io.Reader, meta storage.Metadata) error {
+	return nil
+}
 
+func (s *MockStorage) DownloadFile(ctx context.Context, fileName string) (io.ReadSeekCloser, storage.Metadata, error) {
+	return nil, storage.Metadata{}, storage.ErrFileNotFound
+}
 
-func (s *MockStorage) UploadFile(fileHeader *multipart.FileHeader) (string, error) {
-	return uuid.NewString(), nil
+func (s *MockStorage) DeleteFile(ctx context.Context, fileName string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.files, fileName)
+	return nil
 }
 
-func (s *MockStorage) DownloadFile(fileName string) (io.ReadSeekCloser, error) {
+// HasFile UploadFileで保存され、まだ削除されていないファイルかを返します
+func (s *MockStorage) HasFile(fileName string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, ok := s.files[fileName]
+	return ok
+}
+
+func (s *MockStorage) ListFiles(ctx context.Context) ([]storage.FileInfo, error) {
 	return nil, nil
 }
 
-func (s *MockStorage) DeleteFile(fileName string) error {
+func (s *MockStorage) PresignUpload(ctx context.Context, key string, contentType string, expires time.Duration) (storage.PresignedUpload, error) {
+	return storage.PresignedUpload{
+		URL:     "https://storage.example.com/" + key,
+		Method:  "PUT",
+		Headers: map[string]string{"Content-Type": contentType},
+	}, nil
+}
+
+// PutUpload 署名付きURLへのアップロードを模して、keyにファイルを保存します
+func (s *MockStorage) PutUpload(key string, data []byte, contentType string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.uploads == nil {
+		s.uploads = map[string]mockUpload{}
+	}
+	s.uploads[key] = mockUpload{
+		data: data,
+		meta: storage.Metadata{ContentType: contentType, Size: int64(len(data))},
+	}
+}
+
+func (s *MockStorage) OpenUpload(ctx context.Context, key string) (io.ReadCloser, storage.Metadata, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	upload, ok := s.uploads[key]
+	if !ok {
+		return nil, storage.Metadata{}, storage.ErrFileNotFound
+	}
+	return io.NopCloser(bytes.NewReader(upload.data)), upload.meta, nil
+}
+
+func (s *MockStorage) DeleteUpload(ctx context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.uploads, key)
 	return nil
 }
 
@@ -2,38 +2,48 @@ package s3
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"log/slog"
-	"mime/multipart"
 	"os"
-	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/Luke256/ducks/utils/compressor"
+	"github.com/Luke256/ducks/utils/diskcache"
+	"github.com/Luke256/ducks/utils/storage"
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/credentials"
 	"github.com/aws/aws-sdk-go-v2/feature/s3/manager"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
 	"github.com/google/uuid"
 )
 
 type S3Storage struct {
 	client     *s3.Client
 	bucketName string
+	cache      *diskcache.Cache
 }
 
-func NewS3Storage(bucketName string) (*S3Storage, error) {
-	endpoint := os.Getenv("STORAGE_ENDPOINT")
-	accessKey := os.Getenv("STORAGE_ACCESS_KEY")
-	secretKey := os.Getenv("STORAGE_SECRET_KEY")
+// Config S3互換ストレージへの接続の設定
+type Config struct {
+	Endpoint   string
+	AccessKey  string
+	SecretKey  string
+	BucketName string
+}
 
+// NewS3Storage S3Storageを作成します
+// アップロード・ダウンロードしたファイルはcacheに保存します
+func NewS3Storage(c Config, cache *diskcache.Cache) (*S3Storage, error) {
 	cfg, err := config.LoadDefaultConfig(
-		context.TODO(),
+		context.Background(),
 		config.WithCredentialsProvider(credentials.NewStaticCredentialsProvider(
-			accessKey,
-			secretKey,
+			c.AccessKey,
+			c.SecretKey,
 			"",
 		)),
 		config.WithRegion("auto"),
@@ -43,113 +53,168 @@ func NewS3Storage(bucketName string) (*S3Storage, error) {
 	}
 
 	s3Client := s3.NewFromConfig(cfg, func(o *s3.Options) {
-		o.BaseEndpoint = aws.String(endpoint)
+		o.BaseEndpoint = aws.String(c.Endpoint)
 		o.UsePathStyle = true
 	})
 
-	slog.Info("S3 Storage initialized", "endpoint", endpoint, "bucket", bucketName)
+	slog.Info("S3 Storage initialized", "endpoint", c.Endpoint, "bucket", c.BucketName)
 
 	return &S3Storage{
 		client:     s3Client,
-		bucketName: bucketName,
+		bucketName: c.BucketName,
+		cache:      cache,
 	}, nil
 }
 
-func (s *S3Storage) UploadFile(fileHeader *multipart.FileHeader) (string, error) {
-	rawImage, err := fileHeader.Open()
-	if err != nil {
+func (s *S3Storage) UploadFile(ctx context.Context, src io.Reader, meta storage.Metadata, profile compressor.Profile) (string, error) {
+	if err := meta.Validate(); err != nil {
 		return "", err
 	}
-	defer rawImage.Close()
 
-	compressedImage, format, err := compressor.CompressImage(rawImage)
+	images, err := compressor.CompressImageSet(src, profile)
 	if err != nil {
 		return "", err
 	}
-	defer compressedImage.Close()
+	defer images.Close()
 
 	fileID, err := uuid.NewV7()
 	if err != nil {
 		return "", err
 	}
 
-	fileName := fmt.Sprintf("%s.%s", fileID.String(), strings.ToLower(format))
+	fileName := fmt.Sprintf("%s.%s", fileID.String(), strings.ToLower(images.Format))
+
+	// 元の画像がアップロードされた時点で縮小画像が揃っているよう、縮小画像からアップロードする
+	for name, f := range images.Renditions {
+		if err := s.upload(ctx, storage.RenditionFileName(fileName, name), images.Format, f); err != nil {
+			return "", err
+		}
+	}
+	if err := s.upload(ctx, fileName, images.Format, images.Original); err != nil {
+		return "", err
+	}
+
+	return fileName, nil
+}
 
+// upload ファイルをアップロードし、ローカルにキャッシュします
+func (s *S3Storage) upload(ctx context.Context, fileName, format string, file *os.File) error {
 	uploader := manager.NewUploader(s.client)
 
-	_, err = uploader.Upload(context.TODO(), &s3.PutObjectInput{
-		Bucket: aws.String(s.bucketName),
-		Key:    aws.String(fileName),
-		Body:   compressedImage,
+	_, err := uploader.Upload(ctx, &s3.PutObjectInput{
+		Bucket:      aws.String(s.bucketName),
+		Key:         aws.String(fileName),
+		Body:        file,
 		ContentType: aws.String("image/" + format),
 	})
 	if err != nil {
-		return "", err
+		return err
 	}
 
-	// ファイルのキャッシュ
-	cachedImage, err := os.Create(filepath.Join(os.TempDir(), fileName))
+	_, err = file.Seek(0, 0)
 	if err != nil {
-		return "", err
+		return err
 	}
-	defer cachedImage.Close()
 
-	_, err = compressedImage.Seek(0, 0)
-	if err != nil {
-		return "", err
+	// アップロードは済んでいるので、キャッシュに失敗しても次回のダウンロード時に取得し直せばよい
+	if err := s.cache.Put(fileName, file); err != nil {
+		slog.Warn("failed to cache uploaded file", "file_name", fileName, "error", err)
 	}
 
-	if _, err := io.Copy(cachedImage, compressedImage); err != nil {
-		return "", err
+	return nil
+}
+
+// PutFile ファイルをそのままアップロードします
+// 移行先で正しく保存できたか確かめられるよう、キャッシュには保存しません
+func (s *S3Storage) PutFile(ctx context.Context, fileName string, src io.Reader, meta storage.Metadata) error {
+	contentType := meta.ContentType
+	if contentType == "" {
+		contentType = storage.ContentTypeByFileName(fileName)
 	}
 
-	return fileName, nil
+	uploader := manager.NewUploader(s.client)
+	_, err := uploader.Upload(ctx, &s3.PutObjectInput{
+		Bucket:      aws.String(s.bucketName),
+		Key:         aws.String(fileName),
+		Body:        src,
+		ContentType: aws.String(contentType),
+	})
+	if err != nil {
+		return err
+	}
+
+	// 上書きした場合に古い内容を返さないよう、キャッシュを消しておく
+	if err := s.cache.Remove(fileName); err != nil && !errors.Is(err, diskcache.ErrInvalidKey) {
+		return err
+	}
+	return nil
 }
 
-func (s *S3Storage) DownloadFile(fileName string) (io.ReadSeekCloser, error) {
-	downloader := manager.NewDownloader(s.client)
+func (s *S3Storage) DownloadFile(ctx context.Context, fileName string) (io.ReadSeekCloser, storage.Metadata, error) {
+	var contentType *string
+	// ローカルにキャッシュがなければS3から取得する
+	// 同じファイルが同時に要求された場合も取得は一度だけ行われる
+	file, err := s.cache.Open(fileName, func(w io.Writer) error {
+		slog.Info("Downloading file from S3", "file_name", fileName)
 
-	// ローカルにキャッシュがあればそれを返す
-	cachedFilePath := filepath.Join(os.TempDir(), fileName)
-	if _, err := os.Stat(cachedFilePath); err == nil {
-		cachedFile, err := os.Open(cachedFilePath)
-		
+		object, err := s.client.GetObject(ctx, &s3.GetObjectInput{
+			Bucket: aws.String(s.bucketName),
+			Key:    aws.String(fileName),
+		})
 		if err != nil {
-			return nil, err
+			var noSuchKey *types.NoSuchKey
+			if errors.As(err, &noSuchKey) {
+				return storage.ErrFileNotFound
+			}
+			return err
 		}
-		slog.Info("Using cached file", "file_name", fileName)
-		return cachedFile, nil
-	}
-	slog.Info("Downloading file from S3", "file_name", fileName)
+		defer object.Body.Close()
 
-	downloadFile, err := os.Create(filepath.Join(os.TempDir(), fileName))
+		if _, err := io.Copy(w, object.Body); err != nil {
+			return err
+		}
+		contentType = object.ContentType
+		return nil
+	})
 	if err != nil {
-		return nil, err
+		if errors.Is(err, diskcache.ErrInvalidKey) {
+			return nil, storage.Metadata{}, storage.ErrFileNotFound
+		}
+		return nil, storage.Metadata{}, err
 	}
 
-	_, err = downloader.Download(
-		context.TODO(),
-		downloadFile,
-		&s3.GetObjectInput{
-			Bucket: aws.String(s.bucketName),
-			Key:    aws.String(fileName),
-		},
-	)
-	if err != nil {
-		return nil, err
+	meta := fileMetadata(file.File)
+	meta.Digest = file.Digest
+	if contentType != nil {
+		meta.ContentType = *contentType
 	}
 
-	_, err = downloadFile.Seek(0, 0)
-	if err != nil {
-		return nil, err
+	return file, meta, nil
+}
+
+// fileMetadata キャッシュしたファイルの情報を返します
+// Content-Typeはアップロード時に付けた拡張子から求めます
+func fileMetadata(file *os.File) storage.Metadata {
+	meta := storage.Metadata{ContentType: storage.ContentTypeByFileName(file.Name())}
+	if info, err := file.Stat(); err == nil {
+		meta.Size = info.Size()
+		meta.ModTime = info.ModTime()
 	}
+	return meta
+}
 
-	slog.Info("File downloaded and cached", "file_name", fileName)
-	return downloadFile, nil
+// DeleteFile ファイルと、その縮小画像を削除します
+func (s *S3Storage) DeleteFile(ctx context.Context, fileName string) error {
+	for _, r := range compressor.Renditions {
+		if err := s.deleteObject(ctx, storage.RenditionFileName(fileName, r.Name)); err != nil {
+			return err
+		}
+	}
+	return s.deleteObject(ctx, fileName)
 }
 
-func (s *S3Storage) DeleteFile(fileName string) error {
-	_, err := s.client.DeleteObject(context.TODO(), &s3.DeleteObjectInput{
+func (s *S3Storage) deleteObject(ctx context.Context, fileName string) error {
+	_, err := s.client.DeleteObject(ctx, &s3.DeleteObjectInput{
 		Bucket: aws.String(s.bucketName),
 		Key:    aws.String(fileName),
 	})
@@ -157,15 +222,92 @@ func (s *S3Storage) DeleteFile(fileName string) error {
 		return err
 	}
 
-	cachedFilePath := filepath.Join(os.TempDir(), fileName)
-	if _, err := os.Stat(cachedFilePath); err == nil {
-		if err := os.Remove(cachedFilePath); err != nil {
-			return err
-		}
+	if err := s.cache.Remove(fileName); err != nil && !errors.Is(err, diskcache.ErrInvalidKey) {
+		return err
 	}
 	return nil
 }
 
+// ListFiles バケットにあるオブジェクトの一覧を返します
+func (s *S3Storage) ListFiles(ctx context.Context) ([]storage.FileInfo, error) {
+	var files []storage.FileInfo
+	paginator := s3.NewListObjectsV2Paginator(s.client, &s3.ListObjectsV2Input{
+		Bucket: aws.String(s.bucketName),
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, object := range page.Contents {
+			files = append(files, storage.FileInfo{
+				Name:    aws.ToString(object.Key),
+				Size:    aws.ToInt64(object.Size),
+				ModTime: aws.ToTime(object.LastModified),
+			})
+		}
+	}
+	return files, nil
+}
+
+// PresignUpload keyにPUTするための署名付きURLを作成します
+func (s *S3Storage) PresignUpload(ctx context.Context, key string, contentType string, expires time.Duration) (storage.PresignedUpload, error) {
+	req, err := s3.NewPresignClient(s.client).PresignPutObject(ctx, &s3.PutObjectInput{
+		Bucket:      aws.String(s.bucketName),
+		Key:         aws.String(key),
+		ContentType: aws.String(contentType),
+	}, s3.WithPresignExpires(expires))
+	if err != nil {
+		return storage.PresignedUpload{}, err
+	}
+
+	// 署名に含まれるヘッダーは、クライアントが同じ値で送信する必要がある
+	headers := map[string]string{}
+	for name, values := range req.SignedHeader {
+		if strings.EqualFold(name, "Host") || len(values) == 0 {
+			continue
+		}
+		headers[name] = values[0]
+	}
+
+	return storage.PresignedUpload{
+		URL:     req.URL,
+		Method:  req.Method,
+		Headers: headers,
+	}, nil
+}
+
+// OpenUpload 直接アップロードされたオブジェクトを開きます
+// 一度しか読まないため、キャッシュには保存しません
+func (s *S3Storage) OpenUpload(ctx context.Context, key string) (io.ReadCloser, storage.Metadata, error) {
+	object, err := s.client.GetObject(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(s.bucketName),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		var noSuchKey *types.NoSuchKey
+		if errors.As(err, &noSuchKey) {
+			return nil, storage.Metadata{}, storage.ErrFileNotFound
+		}
+		return nil, storage.Metadata{}, err
+	}
+
+	return object.Body, storage.Metadata{
+		ContentType: aws.ToString(object.ContentType),
+		Size:        aws.ToInt64(object.ContentLength),
+		ModTime:     aws.ToTime(object.LastModified),
+	}, nil
+}
+
+// DeleteUpload 直接アップロードされたオブジェクトを削除します
+func (s *S3Storage) DeleteUpload(ctx context.Context, key string) error {
+	_, err := s.client.DeleteObject(ctx, &s3.DeleteObjectInput{
+		Bucket: aws.String(s.bucketName),
+		Key:    aws.String(key),
+	})
+	return err
+}
+
 func (s *S3Storage) GetFileURL(fileName string) string {
 	endpoint := os.Getenv("API_ENDPOINT")
 	return fmt.Sprintf("%s/api/v1/images/%s", endpoint, fileName)
@@ -1,25 +1,132 @@
 package storage
 
 import (
+	"context"
 	"errors"
 	"io"
+	"mime"
 	"mime/multipart"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/Luke256/ducks/utils/compressor"
 )
 
 var (
-	ErrFileNotFound = errors.New("file not found")
+	ErrFileNotFound           = errors.New("file not found")
+	ErrUnsupportedContentType = errors.New("unsupported content type")
 )
 
+// Metadata ファイルの情報
+// 不明な項目はゼロ値のままにします
+type Metadata struct {
+	ContentType string
+	Size        int64
+	ModTime     time.Time
+	// Digest ファイルの内容のSHA-256を16進数で表したもの
+	// 保存時に求めたものを返し、ダウンロードのたびには計算しません
+	Digest string
+}
+
+// Validate 画像として受け付けられないファイルの場合はErrUnsupportedContentTypeを返します
+func (m Metadata) Validate() error {
+	if m.ContentType != "" && m.ContentType != "application/octet-stream" && !strings.HasPrefix(m.ContentType, "image/") {
+		return ErrUnsupportedContentType
+	}
+	return nil
+}
+
+// FileInfo 保存されているファイルの情報
+type FileInfo struct {
+	Name    string
+	Size    int64
+	ModTime time.Time
+}
+
+// RenditionFileName ファイル名から、その縮小画像のファイル名を返します
+// renditionが空文字の場合は元のファイル名を返します
+func RenditionFileName(fileName, rendition string) string {
+	if rendition == "" {
+		return fileName
+	}
+	ext := path.Ext(fileName)
+	return strings.TrimSuffix(fileName, ext) + "_" + rendition + ext
+}
+
+// FileURL ファイル名をもとにファイルのURLを返します
+// 画像がまだ設定されていない場合は空文字を返します
+func FileURL(s Storage, fileName string) string {
+	if fileName == "" {
+		return ""
+	}
+	return s.GetFileURL(fileName)
+}
+
+// ContentTypeByFileName ファイル名の拡張子からContent-Typeを返します
+// アップロードしたファイルには、保存した形式の拡張子が付いています
+func ContentTypeByFileName(fileName string) string {
+	if contentType := mime.TypeByExtension(path.Ext(fileName)); contentType != "" {
+		return contentType
+	}
+	return "application/octet-stream"
+}
+
 type Storage interface {
-	// UploadFile ファイルをアップロードし、そのファイル名を返します
-	UploadFile(fileHeader *multipart.FileHeader) (string, error)
+	// UploadFile 読み込んだ画像をprofileに従って圧縮してアップロードし、そのファイル名を返します
+	// 縮小画像もあわせて保存し、RenditionFileNameで求めたファイル名で取得できます
+	UploadFile(ctx context.Context, src io.Reader, meta Metadata, profile compressor.Profile) (string, error)
+
+	// PutFile 読み込んだファイルを圧縮せずにfileNameとして保存します
+	// 別のストレージからファイルを移行する際に使います
+	PutFile(ctx context.Context, fileName string, src io.Reader, meta Metadata) error
 
 	// DeleteFile ファイル名をもとにファイルを削除します
-	DeleteFile(fileName string) error
+	// 縮小画像もあわせて削除します
+	DeleteFile(ctx context.Context, fileName string) error
+
+	// DownloadFile ファイル名をもとにファイルをダウンロードし、保存されているファイルの情報とともに返します
+	DownloadFile(ctx context.Context, fileName string) (io.ReadSeekCloser, Metadata, error)
 
-	// DownloadFile ファイル名をもとにファイルをダウンロードします
-	DownloadFile(fileName string) (io.ReadSeekCloser, error)
+	// ListFiles 保存されているファイルの一覧を返します
+	// 縮小画像も含みます
+	ListFiles(ctx context.Context) ([]FileInfo, error)
 
 	// GetFileURL ファイル名をもとにファイルのURLを取得します
 	GetFileURL(fileName string) string
 }
+
+// PresignedUpload 署名付きURLでアップロードするためのリクエスト
+// クライアントはHeadersを付けてMethodでURLにファイルを送信します
+type PresignedUpload struct {
+	URL     string
+	Method  string
+	Headers map[string]string
+}
+
+// DirectUploader クライアントからの直接アップロードに対応したStorage
+// 直接アップロードされたファイルは圧縮前のものであり、UploadFileで保存したファイルとは別に扱います
+type DirectUploader interface {
+	// PresignUpload keyにファイルをアップロードするための署名付きリクエストを作成します
+	PresignUpload(ctx context.Context, key string, contentType string, expires time.Duration) (PresignedUpload, error)
+
+	// OpenUpload 直接アップロードされたファイルを開きます
+	// アップロードされていない場合はErrFileNotFoundを返します
+	OpenUpload(ctx context.Context, key string) (io.ReadCloser, Metadata, error)
+
+	// DeleteUpload 直接アップロードされたファイルを削除します
+	DeleteUpload(ctx context.Context, key string) error
+}
+
+// OpenFileHeader フォームで送信されたファイルを開き、その情報とともに返します
+func OpenFileHeader(fileHeader *multipart.FileHeader) (multipart.File, Metadata, error) {
+	file, err := fileHeader.Open()
+	if err != nil {
+		return nil, Metadata{}, err
+	}
+
+	return file, Metadata{
+		ContentType: fileHeader.Header.Get("Content-Type"),
+		Size:        fileHeader.Size,
+	}, nil
+}
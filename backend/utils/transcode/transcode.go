@@ -0,0 +1,82 @@
+package transcode
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"io/fs"
+
+	"github.com/Luke256/ducks/utils/diskcache"
+	_ "github.com/gen2brain/webp"
+)
+
+var ErrUnsupportedFormat = errors.New("unsupported format")
+
+// Formats 変換先として利用できる形式と、そのContent-Type
+var Formats = map[string]string{
+	"jpeg": "image/jpeg",
+	"png":  "image/png",
+}
+
+// Cache 変換した画像をディスクに保存し、同じ画像の再変換を避けます
+// 保存された画像は名前が変わらない限り内容も変わらないため、キャッシュを無効化する必要はありません
+type Cache struct {
+	files *diskcache.Cache
+}
+
+// NewCache 合計サイズがmaxBytesを超えた場合は、最後に使われてから最も時間が経った画像から削除します
+func NewCache(dir string, maxBytes int64) (*Cache, error) {
+	files, err := diskcache.New(dir, maxBytes)
+	if err != nil {
+		return nil, err
+	}
+	return &Cache{files: files}, nil
+}
+
+// Open fileNameの画像をformatに変換したファイルを返します
+// キャッシュがない場合はsrcを読み込んで変換し、キャッシュに保存します
+// 同じ画像の変換が同時に要求された場合、変換は一度だけ行います
+func (c *Cache) Open(fileName, format string, src func() (io.ReadCloser, error)) (*diskcache.File, error) {
+	if _, ok := Formats[format]; !ok {
+		return nil, ErrUnsupportedFormat
+	}
+
+	file, err := c.files.Open(fileName+"."+format, func(w io.Writer) error {
+		r, err := src()
+		if err != nil {
+			return err
+		}
+		defer r.Close()
+		return Transcode(w, r, format)
+	})
+	if errors.Is(err, diskcache.ErrInvalidKey) {
+		return nil, fs.ErrNotExist
+	}
+	return file, err
+}
+
+// Transcode 画像をformatの形式に変換してwに書き込みます
+// JPEGは透過を扱えないため、透過部分は白で塗りつぶします
+func Transcode(w io.Writer, src io.Reader, format string) error {
+	img, _, err := image.Decode(src)
+	if err != nil {
+		return fmt.Errorf("failed to decode image: %w", err)
+	}
+
+	switch format {
+	case "jpeg":
+		dst := image.NewRGBA(img.Bounds())
+		draw.Draw(dst, dst.Bounds(), image.NewUniform(color.White), image.Point{}, draw.Src)
+		draw.Draw(dst, dst.Bounds(), img, img.Bounds().Min, draw.Over)
+		return jpeg.Encode(w, dst, &jpeg.Options{Quality: 85})
+	case "png":
+		return png.Encode(w, img)
+	default:
+		return ErrUnsupportedFormat
+	}
+}
@@ -0,0 +1,97 @@
+package transcode
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"os"
+	"testing"
+
+	"github.com/gen2brain/webp"
+)
+
+func webpImage(t *testing.T) []byte {
+	t.Helper()
+
+	img := image.NewNRGBA(image.Rect(0, 0, 8, 8))
+	img.Set(0, 0, color.NRGBA{255, 0, 0, 255})
+
+	var buf bytes.Buffer
+	if err := webp.Encode(&buf, img, webp.Options{Lossless: true}); err != nil {
+		t.Fatalf("failed to encode test image: %v", err)
+	}
+	return buf.Bytes()
+}
+
+func TestTranscode(t *testing.T) {
+	src := webpImage(t)
+
+	t.Run("jpeg", func(t *testing.T) {
+		var buf bytes.Buffer
+		if err := Transcode(&buf, bytes.NewReader(src), "jpeg"); err != nil {
+			t.Fatalf("Transcode failed: %v", err)
+		}
+		img, err := jpeg.Decode(&buf)
+		if err != nil {
+			t.Fatalf("output is not a JPEG: %v", err)
+		}
+		// 透過部分は白で塗りつぶされる
+		if r, g, b, _ := img.At(7, 7).RGBA(); r>>8 < 240 || g>>8 < 240 || b>>8 < 240 {
+			t.Errorf("expected transparent pixel to be white, got %v", img.At(7, 7))
+		}
+	})
+
+	t.Run("png", func(t *testing.T) {
+		var buf bytes.Buffer
+		if err := Transcode(&buf, bytes.NewReader(src), "png"); err != nil {
+			t.Fatalf("Transcode failed: %v", err)
+		}
+		if _, err := png.Decode(&buf); err != nil {
+			t.Fatalf("output is not a PNG: %v", err)
+		}
+	})
+
+	t.Run("unsupported format", func(t *testing.T) {
+		if err := Transcode(io.Discard, bytes.NewReader(src), "gif"); err != ErrUnsupportedFormat {
+			t.Errorf("expected ErrUnsupportedFormat, got %v", err)
+		}
+	})
+}
+
+func TestCache(t *testing.T) {
+	cache, err := NewCache(t.TempDir(), 1<<20)
+	if err != nil {
+		t.Fatalf("NewCache failed: %v", err)
+	}
+
+	src := webpImage(t)
+	calls := 0
+	open := func() (io.ReadCloser, error) {
+		calls++
+		return io.NopCloser(bytes.NewReader(src)), nil
+	}
+
+	for range 2 {
+		file, err := cache.Open("image.webp", "jpeg", open)
+		if err != nil {
+			t.Fatalf("Open failed: %v", err)
+		}
+		if _, err := jpeg.Decode(file); err != nil {
+			t.Errorf("cached file is not a JPEG: %v", err)
+		}
+		file.Close()
+	}
+	if calls != 1 {
+		t.Errorf("expected source to be read once, got %d", calls)
+	}
+
+	if _, err := cache.Open("../image.webp", "jpeg", open); !os.IsNotExist(err) {
+		t.Errorf("expected not exist error for invalid file name, got %v", err)
+	}
+	if _, err := cache.Open("image.webp", "gif", open); err != ErrUnsupportedFormat {
+		t.Errorf("expected ErrUnsupportedFormat, got %v", err)
+	}
+}
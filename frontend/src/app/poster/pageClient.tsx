@@ -8,12 +8,16 @@ import { useFestivalList } from "@/hooks/festivalHook";
 import { usePosterList } from "@/hooks/posterHook";
 import { useState } from "react";
 import { useSessionStorage } from "@/hooks/sessStorage";
-import { Poster, PosterStatusLabels } from "@/types/poster";
+import { Poster, PosterStatus, PosterStatusLabels } from "@/types/poster";
 import { toast } from "react-toastify";
 import { useRouter } from "next/navigation";
 
-const posterItemBg = {
-  "uncollected": "bg-yellow-100",
+const posterItemBg: { [key in PosterStatus]: string } = {
+  "submitted": "bg-gray-100",
+  "in_review": "bg-blue-100",
+  "approved": "bg-cyan-100",
+  "rejected": "bg-orange-100",
+  "posted": "bg-yellow-100",
   "collected": "bg-green-100",
   "lost": "bg-red-100"
 }
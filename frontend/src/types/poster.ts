@@ -1,9 +1,13 @@
 import { Festival } from "./festival";
 
-type PosterStatus = 'uncollected' | 'collected' | 'lost';
+type PosterStatus = 'submitted' | 'in_review' | 'approved' | 'rejected' | 'posted' | 'collected' | 'lost';
 
 const PosterStatusLabels: { [key in PosterStatus]: string } = {
-    'uncollected': '未回収',
+    'submitted': '提出済み',
+    'in_review': '審査中',
+    'approved': '承認済み',
+    'rejected': '差し戻し',
+    'posted': '掲示中',
     'collected': '回収済み',
     'lost': '消失',
 };
@@ -14,6 +18,7 @@ type Poster = {
     description: string;
     image_url: string;
     status: PosterStatus;
+    rejection_reason?: string;
     festival: Festival;
 }
 